@@ -8,8 +8,10 @@ import (
 
 type apiGenerator struct {
 	apiName          string
+	ifacePath        string // fully qualified interface name. ex: "github.com/user/kv.KVStore"
 	goDoc            string
 	serviceIdVarName string
+	descVarName      string // variable holding irpcgen.ServiceDesc
 	methods          []methodGenerator
 }
 
@@ -25,8 +27,10 @@ func newApiGenerator(tr typeResolver, apiName string, astIface *ast.InterfaceTyp
 
 	return apiGenerator{
 		apiName:          apiName,
+		ifacePath:        tr.srcPkg.PkgPath + "." + apiName,
 		goDoc:            godocFromAstCommentGroup(godocCg),
 		serviceIdVarName: fmt.Sprintf("_%sIrpcId", apiName),
+		descVarName:      fmt.Sprintf("_%sIrpcDesc", apiName),
 		methods:          methods,
 	}, nil
 }
@@ -35,6 +39,12 @@ func (ag apiGenerator) paramStructs() []paramStructGenerator {
 	paramStructs := make([]paramStructGenerator, 0, len(ag.methods)*2)
 	for _, method := range ag.methods {
 		paramStructs = append(paramStructs, method.req, method.resp)
+		if method.stream != nil {
+			paramStructs = append(paramStructs, method.stream.item)
+		}
+		if method.paramStream != nil {
+			paramStructs = append(paramStructs, method.paramStream.item)
+		}
 	}
 	return paramStructs
 }
@@ -43,6 +53,44 @@ func (ag apiGenerator) serviceIdVarDefinition(hash []byte) string {
 	return fmt.Sprintf("var %s = irpcgen.ServiceId(%s)", ag.serviceIdVarName, serviceIdLiteralFromBytes(ag.serviceId(hash)))
 }
 
+// descriptorCode defines variable with the service's irpcgen.ServiceDesc
+func (ag apiGenerator) descriptorCode(q *qualifier) string {
+	sb := &strings.Builder{}
+	fmt.Fprintf(sb, `var %s = irpcgen.ServiceDesc{
+		Id: %s,
+		Name: %q,
+		Interface: %q,
+		Methods: []irpcgen.MethodDesc{
+		`, ag.descVarName, ag.serviceIdVarName, ag.apiName, ag.ifacePath)
+	for _, m := range ag.methods {
+		fmt.Fprintf(sb, "{Name: %q, Params: %s, Results: %s, HasContext: %t", m.name, paramDescsCode(q, m.req.params), paramDescsCode(q, m.resultParams()), m.hasContext())
+		if m.idempotent {
+			sb.WriteString(", Idempotent: true")
+		}
+		sb.WriteString("},\n")
+	}
+	sb.WriteString("},\n}\n")
+	return sb.String()
+}
+
+// paramDescsCode returns []irpcgen.ParamDesc literal
+func paramDescsCode(q *qualifier, params []genParam) string {
+	if len(params) == 0 {
+		return "nil"
+	}
+	sb := &strings.Builder{}
+	sb.WriteString("[]irpcgen.ParamDesc{")
+	for i, p := range params {
+		if i != 0 {
+			sb.WriteString(", ")
+		}
+		// all the types appear in client's method signatures, so their imports are used anyway
+		fmt.Fprintf(sb, "{Name: %q, Type: %q}", p.identifier, p.typ.name(q))
+	}
+	sb.WriteString("}")
+	return sb.String()
+}
+
 func (ag apiGenerator) clientTypeName() string {
 	return ag.apiName + "IrpcClient"
 }
@@ -67,12 +115,12 @@ func (ag apiGenerator) clientCode(q *qualifier) string {
 	}
 
 	func %[2]s(endpoint irpcgen.Endpoint) (*%[1]s, error) {
-		if err := endpoint.RegisterClient(%[3]s); err != nil {
+		if err := irpcgen.RegisterClient(endpoint, &%[3]s); err != nil {
 			return nil, fmt.Errorf("register failed: %%w", err)
 		}
 		return &%[1]s{endpoint: endpoint}, nil
 	}
-	`, ag.clientTypeName(), generateStructConstructorName(ag.clientTypeName()), ag.serviceIdVarName)
+	`, ag.clientTypeName(), generateStructConstructorName(ag.clientTypeName()), ag.descVarName)
 
 	// func calls
 	for _, m := range ag.methods {
@@ -88,8 +136,11 @@ func (ag apiGenerator) clientCode(q *qualifier) string {
 
 		// func header
 		fmt.Fprintf(sb, "// %s implements [%s]\n//\n", m.name, ag.apiName)
+		if m.stream != nil && m.stream.typ.kind == chanStream {
+			sb.WriteString("// Any error closes the returned channel early. Use [irpcgen.ContextWithStreamErrorHandler] to learn about it.\n//\n")
+		}
 		sb.WriteString(m.goDoc)
-		fmt.Fprintf(sb, "func(%s *%s)%s(%s)(%s){\n", fncReceiverName, ag.clientTypeName(), m.name, m.req.funcCallParams(q), m.resp.funcCallParams(q))
+		fmt.Fprintf(sb, "func(%s *%s)%s(%s)(%s){\n", fncReceiverName, ag.clientTypeName(), m.name, m.req.funcCallParams(q), m.resultsDeclaration(q))
 
 		// request
 		var reqVarName string
@@ -100,8 +151,8 @@ func (ag apiGenerator) clientCode(q *qualifier) string {
 			// request construction
 			fmt.Fprintf(sb, "var %s = %s {\n", reqVarName, m.req.structName)
 			for _, p := range m.req.params {
-				if p.isContext() {
-					// we skip contexts, as they are treated special
+				if p.isContext() || p.isStream() {
+					// we skip contexts and streams, as they are treated special
 					sb.WriteString("// ")
 				}
 				fmt.Fprintf(sb, "%s: %s,\n", p.structFieldName, p.identifier)
@@ -109,6 +160,33 @@ func (ag apiGenerator) clientCode(q *qualifier) string {
 			fmt.Fprintf(sb, "}\n") // end struct assignment
 		}
 
+		// streamed parameter's items are sent after the request
+		if m.paramStream != nil {
+			q.addUsedImport(contextImport)
+			streamReqVarName := allVarIds.generateUniqueVarName("reqStream")
+			ctxVarName := allVarIds.generateUniqueVarName("ctx")
+			sendVarName := allVarIds.generateUniqueVarName("send")
+			var sendCode string
+			switch m.paramStream.typ.kind {
+			case seqStream:
+				sendCode = fmt.Sprintf("irpcgen.SendSeq(%s, %s, %s)", m.paramStream.identifier, m.newParamItemFuncCode(q), sendVarName)
+			case chanStream:
+				sendCode = fmt.Sprintf("irpcgen.SendChan(%s, %s, %s, %s)", ctxVarName, m.paramStream.identifier, m.newParamItemFuncCode(q), sendVarName)
+			default:
+				panic(fmt.Sprintf("unknown stream kind %d", m.paramStream.typ.kind))
+			}
+			fmt.Fprintf(sb, `%s := irpcgen.NewStreamSerializable(%s, func(%s context.Context, %s irpcgen.ItemSender) {
+				%s
+			})
+			`, streamReqVarName, reqVarName, ctxVarName, sendVarName, sendCode)
+			reqVarName = streamReqVarName
+		}
+
+		if m.stream != nil {
+			ag.streamClientCallCode(sb, q, m, fncReceiverName, reqVarName, allVarIds)
+			continue
+		}
+
 		// response
 		var respVarName string
 		if m.resp.isEmpty() {
@@ -180,6 +258,13 @@ func (ag apiGenerator) serviceCode(q *qualifier) string {
 	}
 	`, ag.serviceTypeName(), ag.serviceIdVarName)
 
+	// Descriptor() func
+	fmt.Fprintf(w, `// Descriptor implements [irpcgen.DescribedService] interface.
+	func (s *%s) Descriptor() *irpcgen.ServiceDesc {
+		return &%s
+	}
+	`, ag.serviceTypeName(), ag.descVarName)
+
 	// Call func call switch
 	fmt.Fprintf(w, `// GetFuncCall implements [irpcgen.Service] interface
 	func (s *%s) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error){
@@ -222,3 +307,65 @@ func (ag apiGenerator) serviceId(hash []byte) []byte {
 
 	return generateServiceIdHash(hash, ag.apiName, generatedIdLen)
 }
+
+// streamClientCallCode generates the body of client's streaming function after the request was constructed
+func (ag apiGenerator) streamClientCallCode(sb *strings.Builder, q *qualifier, m methodGenerator, fncReceiverName, reqVarName string, allVarIds varNames) {
+	itemVarName := allVarIds.generateUniqueVarName("item")
+	errVarName := allVarIds.generateUniqueVarName("err")
+	newItem := fmt.Sprintf("func() irpcgen.Deserializable { return new(%s) }", m.stream.item.structName)
+	itemValue := fmt.Sprintf("%s.(*%s).v", itemVarName, m.stream.item.structName)
+
+	switch m.stream.typ.kind {
+	case seq2Stream:
+		yieldVarName := allVarIds.generateUniqueVarName("yield")
+		respVarName := allVarIds.generateUniqueVarName("resp")
+		zeroVarName := allVarIds.generateUniqueVarName("zero")
+		fmt.Fprintf(sb, `return func(%[1]s func(%[2]s, error) bool) {
+			var %[3]s %[4]s
+			for %[5]s, %[6]s := range %[7]s.endpoint.CallRemoteStream(%[8]s, %[9]s, %[10]d, %[11]s, %[12]s, &%[3]s) {
+				if %[6]s != nil {
+					var %[13]s %[14]s
+					%[1]s(%[13]s.v, %[6]s)
+					return
+				}
+				if !%[1]s(%[15]s, nil) {
+					return
+				}
+			}
+			if %[3]s.%[16]s != nil {
+				var %[13]s %[14]s
+				%[1]s(%[13]s.v, %[3]s.%[16]s)
+			}
+		}
+		`, yieldVarName, m.stream.typ.elemT.name(q), respVarName, m.resp.structName, itemVarName, errVarName,
+			fncReceiverName, m.ctxVar, ag.serviceIdVarName, m.index, reqVarName, newItem,
+			zeroVarName, m.stream.item.structName, itemValue, m.resp.params[0].structFieldName)
+
+	case chanStream:
+		// errors cannot be sent over the channel. it is closed and the error goes to the handler in context
+		chVarName := allVarIds.generateUniqueVarName("ch")
+		fmt.Fprintf(sb, `%[1]s := make(chan %[2]s)
+		go func() {
+			defer close(%[1]s)
+			for %[3]s, %[4]s := range %[5]s.endpoint.CallRemoteStream(%[6]s, %[7]s, %[8]d, %[9]s, %[10]s, irpcgen.EmptyDeserializable{}) {
+				if %[4]s != nil {
+					irpcgen.ReportStreamError(%[6]s, %[4]s)
+					return
+				}
+				select {
+				case %[1]s <- %[11]s:
+				case <-%[6]s.Done():
+					return
+				}
+			}
+		}()
+		return %[1]s
+		`, chVarName, m.stream.typ.elemT.name(q), itemVarName, errVarName,
+			fncReceiverName, m.ctxVar, ag.serviceIdVarName, m.index, reqVarName, newItem, itemValue)
+
+	default:
+		panic(fmt.Sprintf("unknown stream kind %d", m.stream.typ.kind))
+	}
+
+	fmt.Fprintf(sb, "}\n") // end of func
+}
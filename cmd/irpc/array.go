@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+)
+
+var _ Type = arrayType{}
+
+// arrayType is a fixed-size array. its length is known at compile time, so we don't serialize it
+type arrayType struct {
+	elemT Type
+	len   int64
+	ni    *namedInfo
+}
+
+func (tr *typeResolver) newArrayType(apiName string, ni *namedInfo, at *types.Array, astExpr ast.Expr) (arrayType, error) {
+	var elemAst ast.Expr
+	if astExpr != nil {
+		arrayAst, ok := astExpr.(*ast.ArrayType)
+		if !ok {
+			return arrayType{}, fmt.Errorf("array's astExpression is not *ast.ArrayType, but %T of value %#[1]v", astExpr)
+		}
+		elemAst = arrayAst.Elt
+	}
+
+	elemT, err := tr.newType(apiName, at.Elem(), elemAst)
+	if err != nil {
+		return arrayType{}, fmt.Errorf("newType() for array's element %q: %w", at.Elem(), err)
+	}
+
+	return arrayType{
+		elemT: elemT,
+		len:   at.Len(),
+		ni:    ni,
+	}, nil
+}
+
+// name implements Type.
+func (at arrayType) name(q *qualifier) string {
+	if at.ni != nil {
+		return q.qualifyNamedInfo(*at.ni)
+	}
+	return fmt.Sprintf("[%d]%s", at.len, at.elemT.name(q))
+}
+
+// genEncFunc implements Type.
+func (at arrayType) genEncFunc(q *qualifier) string {
+	lq := q.copy()
+	return fmt.Sprintf(`func(enc *irpcgen.Encoder, a %s) error{
+		return irpcgen.EncArray(enc, a[:], %q, %s)
+	}`, at.name(q), at.elemT.name(lq), at.elemT.genEncFunc(q))
+}
+
+// genDecFunc implements Type.
+func (at arrayType) genDecFunc(q *qualifier) string {
+	lq := q.copy()
+	return fmt.Sprintf(`func(dec *irpcgen.Decoder, a *%s) error {
+		return irpcgen.DecArray(dec, a[:], %q, %s)
+	}`, at.name(q), at.elemT.name(lq), at.elemT.genDecFunc(q))
+}
+
+// codeblocks implements Type.
+func (at arrayType) codeblocks(q *qualifier) []string {
+	return at.elemT.codeblocks(q)
+}
@@ -147,23 +147,53 @@ func godocFromAstCommentGroup(cg *ast.CommentGroup) string {
 		return ""
 	}
 
-	var sb strings.Builder
+	var lines []string
 	for _, l := range cg.List {
 		text := l.Text
 
-		// filter out go directives
+		// filter out go and irpc directives
 		if strings.HasPrefix(text, "//go:") ||
 			strings.HasPrefix(text, "/*go:") ||
-			strings.HasPrefix(text, "//line ") {
+			strings.HasPrefix(text, "//line ") ||
+			strings.HasPrefix(text, irpcDirectivePrefix) {
 			continue
 		}
 
-		sb.WriteString(text)
+		lines = append(lines, text)
+	}
+
+	// directives are usually separated from the doc by an empty line
+	for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "//" {
+		lines = lines[:len(lines)-1]
+	}
+
+	var sb strings.Builder
+	for _, l := range lines {
+		sb.WriteString(l)
 		sb.WriteByte('\n')
 	}
 	return sb.String()
 }
 
+// irpcDirectivePrefix starts comment lines, that configure the generator. ex: //irpc:idempotent
+const irpcDirectivePrefix = "//irpc:"
+
+// idempotentDirective marks interface method, that can be safely retried
+const idempotentDirective = "idempotent"
+
+// hasDirective reports, whether comment group contains //irpc:<directive> line
+func hasDirective(cg *ast.CommentGroup, directive string) bool {
+	if cg == nil {
+		return false
+	}
+	for _, l := range cg.List {
+		if strings.TrimSpace(l.Text) == irpcDirectivePrefix+directive {
+			return true
+		}
+	}
+	return false
+}
+
 func canonicalSrcFilePath(file string, srcPkg *packages.Package) (string, error) {
 	fileAbsPath, err := filepath.Abs(file)
 	if err != nil {
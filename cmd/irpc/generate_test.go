@@ -1,6 +1,9 @@
 package main
 
-import "testing"
+import (
+	"go/ast"
+	"testing"
+)
 
 func TestGenerateNewFuncName(t *testing.T) {
 	type test struct {
@@ -44,3 +47,21 @@ func TestByteSliceLiteral(t *testing.T) {
 		}
 	}
 }
+
+func TestGodocDirectives(t *testing.T) {
+	cg := &ast.CommentGroup{List: []*ast.Comment{
+		{Text: "// Get returns the value"},
+		{Text: "//"},
+		{Text: "//irpc:idempotent"},
+	}}
+
+	if got, want := godocFromAstCommentGroup(cg), "// Get returns the value\n"; got != want {
+		t.Fatalf("godoc: got %q, want %q", got, want)
+	}
+	if !hasDirective(cg, idempotentDirective) {
+		t.Fatalf("idempotent directive not found")
+	}
+	if hasDirective(&ast.CommentGroup{List: cg.List[:1]}, idempotentDirective) {
+		t.Fatalf("unexpected idempotent directive")
+	}
+}
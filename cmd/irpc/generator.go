@@ -72,6 +72,7 @@ func (g generator) generate(w io.Writer, generatorVersion string, hash []byte) e
 
 	for _, service := range g.services {
 		codeBlocks.add(service.serviceIdVarDefinition(hash))
+		codeBlocks.add(service.descriptorCode(q))
 		codeBlocks.add(service.serviceCode(q))
 		codeBlocks.add(service.clientCode(q))
 
@@ -7,13 +7,34 @@ import (
 )
 
 type methodGenerator struct {
-	name      string
-	index     int
-	req, resp paramStructGenerator
-	ctxVar    string // context used for method call (either there is context param, or we use context.Background() )
-	goDoc     string
+	name        string
+	index       int
+	req, resp   paramStructGenerator
+	ctxVar      string // context used for method call (either there is context param, or we use context.Background() )
+	goDoc       string
+	idempotent  bool          // marked with //irpc:idempotent
+	stream      *streamResult // nil, unless the method streams its result
+	paramStream *streamParam  // nil, unless the method has a streamed parameter
 }
 
+// streamResult is the only result of a server-streaming method
+// stream's final outcome (the error of iter.Seq2) is sent in method's resp struct
+type streamResult struct {
+	identifier string // result name as declared in the interface. can be empty
+	typ        streamType
+	item       paramStructGenerator // struct carrying one streamed item
+}
+
+// streamParam is a method's parameter, whose items are sent after the request
+type streamParam struct {
+	identifier string // parameter's identifier in req struct
+	typ        streamType
+	item       paramStructGenerator // struct carrying one streamed item
+}
+
+// paramItemsVar is the variable holding the received streamed parameter in service's executor
+const paramItemsVar = "items"
+
 func newMethodGenerator(tr typeResolver, apiName string, methodField *ast.Field, index int) (methodGenerator, error) {
 	if len(methodField.Names) == 0 {
 		return methodGenerator{}, fmt.Errorf("method of interface %q has no name", apiName)
@@ -38,6 +59,47 @@ func newMethodGenerator(tr typeResolver, apiName string, methodField *ast.Field,
 		}
 	}
 
+	streamParamsCnt := 0
+	for _, p := range params {
+		st, ok := p.typ.(streamType)
+		if !ok {
+			continue
+		}
+		if st.kind == seq2Stream {
+			return methodGenerator{}, fmt.Errorf("%s - %s : streamed parameter must be iter.Seq[T] or <-chan T", apiName, methodName)
+		}
+		streamParamsCnt++
+	}
+	if streamParamsCnt > 1 {
+		return methodGenerator{}, fmt.Errorf("%s - %s : cannot have more than one streamed parameter", apiName, methodName)
+	}
+
+	var stream *streamResult
+	for _, r := range results {
+		st, ok := r.typ.(streamType)
+		if !ok {
+			continue
+		}
+		if st.kind == seqStream {
+			return methodGenerator{}, fmt.Errorf("%s - %s : streamed result must be iter.Seq2[T, error] or <-chan T", apiName, methodName)
+		}
+		if len(results) != 1 {
+			return methodGenerator{}, fmt.Errorf("%s - %s : stream must be the only result of a method", apiName, methodName)
+		}
+		stream = &streamResult{
+			identifier: r.name,
+			typ:        st,
+			item: paramStructGenerator{
+				structName: "_irpc_" + apiName + "_" + methodName + "Item",
+				params:     []genParam{{identifier: "v", structFieldName: "v", typ: st.elemT}},
+			},
+		}
+		results = nil
+		if st.kind == seq2Stream {
+			results = []rpcParam{{pos: 0, typ: st.errT}}
+		}
+	}
+
 	req, resp, err := newReqRespStructsGenerator(apiName, methodName, params, results)
 	if err != nil {
 		return methodGenerator{}, fmt.Errorf("newReqRespStructsGenerator(): %w", err)
@@ -52,6 +114,20 @@ func newMethodGenerator(tr typeResolver, apiName string, methodField *ast.Field,
 			ctxParams = append(ctxParams, p)
 		}
 	}
+	var paramStream *streamParam
+	for _, p := range req.params {
+		if p.isStream() {
+			paramStream = &streamParam{
+				identifier: p.identifier,
+				typ:        p.typ.(streamType),
+				item: paramStructGenerator{
+					structName: "_irpc_" + apiName + "_" + methodName + "ParamItem",
+					params:     []genParam{{identifier: "v", structFieldName: "v", typ: p.typ.(streamType).elemT}},
+				},
+			}
+		}
+	}
+
 	var ctxVarName string
 	switch len(ctxParams) {
 	case 0:
@@ -69,16 +145,23 @@ func newMethodGenerator(tr typeResolver, apiName string, methodField *ast.Field,
 		resp:   resp,
 		ctxVar: ctxVarName,
 		goDoc:  godocFromAstCommentGroup(methodField.Doc),
+		stream: stream,
+
+		paramStream: paramStream,
+		idempotent:  hasDirective(methodField.Doc, idempotentDirective),
 	}, nil
 }
 
 // creates method call list with each var prefixed with 'prefix'
 // replaces any parameter of type context.Context with 'ctxVarName'
+// and streamed parameter with paramItemsVar
 func (mg methodGenerator) requestParamsListPrefixed(prefix, ctxVarName string) string {
 	sb := &strings.Builder{}
 	for i, p := range mg.req.params {
 		if p.isContext() {
 			sb.WriteString(ctxVarName)
+		} else if p.isStream() {
+			sb.WriteString(paramItemsVar)
 		} else {
 			fmt.Fprintf(sb, "%s%s", prefix, p.structFieldName)
 		}
@@ -91,16 +174,87 @@ func (mg methodGenerator) requestParamsListPrefixed(prefix, ctxVarName string) s
 
 func (mg methodGenerator) executorFuncCode(q *qualifier) string {
 	q.addUsedImport(contextImport)
+	if mg.stream != nil {
+		return mg.streamExecutorFuncCode(q)
+	}
 	if mg.resp.isEmpty() {
 		return fmt.Sprintf(`func(ctx context.Context) irpcgen.Serializable {
-				s.impl.%[2]s(%[3]s)
+				%[4]ss.impl.%[2]s(%[3]s)
 				return irpcgen.EmptySerializable{}
-			}`, mg.resp.structName, mg.name, mg.requestParamsListPrefixed("args.", "ctx"))
+			}`, mg.resp.structName, mg.name, mg.requestParamsListPrefixed("args.", "ctx"), mg.paramStreamRecvCode(q))
 	}
 
 	return fmt.Sprintf(`func(ctx context.Context) irpcgen.Serializable {
-				var resp %[1]s
+				%[5]svar resp %[1]s
 				%[2]s = s.impl.%[3]s(%[4]s)
 				return resp
-			}`, mg.resp.structName, mg.resp.paramListPrefixed("resp."), mg.name, mg.requestParamsListPrefixed("args.", "ctx"))
+			}`, mg.resp.structName, mg.resp.paramListPrefixed("resp."), mg.name, mg.requestParamsListPrefixed("args.", "ctx"), mg.paramStreamRecvCode(q))
+}
+
+// declares paramItemsVar, which receives the streamed parameter's items from the peer
+// returns empty string for methods without streamed parameter
+func (mg methodGenerator) paramStreamRecvCode(q *qualifier) string {
+	if mg.paramStream == nil {
+		return ""
+	}
+	recvFunc := "irpcgen.RecvSeq"
+	if mg.paramStream.typ.kind == chanStream {
+		recvFunc = "irpcgen.RecvChan"
+	}
+	return fmt.Sprintf("%s := %s(ctx, func() irpcgen.Deserializable { return new(%s) }, func(item irpcgen.Deserializable) %s { return item.(*%[3]s).v })\n",
+		paramItemsVar, recvFunc, mg.paramStream.item.structName, mg.paramStream.typ.elemT.name(q))
+}
+
+// newParamItemFuncCode returns function wrapping streamed parameter's value into its item struct
+func (mg methodGenerator) newParamItemFuncCode(q *qualifier) string {
+	return fmt.Sprintf("func(v %s) irpcgen.Serializable { return %s{v: v} }", mg.paramStream.typ.elemT.name(q), mg.paramStream.item.structName)
+}
+
+// the implementation's stream is sent item by item, before the response
+func (mg methodGenerator) streamExecutorFuncCode(q *qualifier) string {
+	newItem := fmt.Sprintf("func(v %s) irpcgen.Serializable { return %s{v: v} }", mg.stream.typ.elemT.name(q), mg.stream.item.structName)
+	switch mg.stream.typ.kind {
+	case seq2Stream:
+		return fmt.Sprintf(`func(ctx context.Context) irpcgen.Serializable {
+				%[6]svar resp %[1]s
+				seq := s.impl.%[2]s(%[3]s)
+				return irpcgen.NewStreamSerializable(&resp, func(ctx context.Context, send irpcgen.ItemSender) {
+					resp.%[4]s = irpcgen.SendSeq2(seq, %[5]s, send)
+				})
+			}`, mg.resp.structName, mg.name, mg.requestParamsListPrefixed("args.", "ctx"), mg.resp.params[0].structFieldName, newItem, mg.paramStreamRecvCode(q))
+	case chanStream:
+		return fmt.Sprintf(`func(ctx context.Context) irpcgen.Serializable {
+				%[4]sch := s.impl.%[1]s(%[2]s)
+				return irpcgen.NewStreamSerializable(irpcgen.EmptySerializable{}, func(ctx context.Context, send irpcgen.ItemSender) {
+					irpcgen.SendChan(ctx, ch, %[3]s, send)
+				})
+			}`, mg.name, mg.requestParamsListPrefixed("args.", "ctx"), newItem, mg.paramStreamRecvCode(q))
+	default:
+		panic(fmt.Sprintf("unknown stream kind %d", mg.stream.typ.kind))
+	}
+}
+
+// resultParams returns method's results as declared in the interface
+func (mg methodGenerator) resultParams() []genParam {
+	if mg.stream != nil {
+		return []genParam{{identifier: mg.stream.identifier, typ: mg.stream.typ}}
+	}
+	return mg.resp.params
+}
+
+func (mg methodGenerator) hasContext() bool {
+	for _, p := range mg.req.params {
+		if p.isContext() {
+			return true
+		}
+	}
+	return false
+}
+
+// client func's results. ex: "a int, err error"
+func (mg methodGenerator) resultsDeclaration(q *qualifier) string {
+	if mg.stream != nil {
+		return mg.stream.identifier + " " + mg.stream.typ.name(q)
+	}
+	return mg.resp.funcCallParams(q)
 }
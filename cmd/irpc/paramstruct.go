@@ -8,6 +8,7 @@ import (
 type paramStructGenerator struct {
 	structName string
 	params     []genParam
+	isResp     bool // response of a function. it implements irpcgen.ErrorResult, if the function returns error
 }
 
 func newReqRespStructsGenerator(apiName, methodName string, reqParams, respParams []rpcParam) (req, resp paramStructGenerator, err error) {
@@ -62,6 +63,7 @@ func newReqRespStructsGenerator(apiName, methodName string, reqParams, respParam
 	resp = paramStructGenerator{
 		structName: "_irpc_" + apiName + "_" + methodName + "Resp",
 		params:     respStructParams,
+		isResp:     true,
 	}
 
 	return req, resp, nil
@@ -71,8 +73,8 @@ func (sg paramStructGenerator) code(q *qualifier) string {
 	sb := &strings.Builder{}
 	fmt.Fprintf(sb, "type %s struct{\n", sg.structName)
 	for _, p := range sg.params {
-		if p.isContext() {
-			// we comment out context var as it is not filled anyway
+		if p.isContext() || p.isStream() {
+			// we comment out context and stream vars as they are not filled anyway
 			sb.WriteString("//")
 		}
 		sb.WriteString(p.structFieldName + " " + p.typ.name(q) + "\n")
@@ -80,10 +82,19 @@ func (sg paramStructGenerator) code(q *qualifier) string {
 	sb.WriteString("\n}\n")
 	sb.WriteString(sg.serializeFunc(q) + "\n")
 	sb.WriteString(sg.deserializeFunc(q))
+	if sg.isResp && sg.isLastTypeError(q) {
+		sb.WriteString("\n" + sg.resultErrorFunc())
+	}
 
 	return sb.String()
 }
 
+// resultErrorFunc implements irpcgen.ErrorResult, so that interceptors see the error returned by the function
+func (sg paramStructGenerator) resultErrorFunc() string {
+	last := sg.params[len(sg.params)-1]
+	return fmt.Sprintf("func (s %s)ResultError() error {\nreturn s.%s\n}", sg.structName, last.structFieldName)
+}
+
 func (sg paramStructGenerator) isEmpty() bool {
 	return len(sg.params) == 0
 }
@@ -204,3 +215,11 @@ func (fp genParam) isContext() bool {
 	}
 	return false
 }
+
+// returns true if field is a streamed parameter. its items are sent after the request
+func (fp genParam) isStream() bool {
+	if _, ok := fp.typ.(streamType); ok {
+		return true
+	}
+	return false
+}
@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/types"
+)
+
+type streamKind int
+
+const (
+	seq2Stream streamKind = iota // iter.Seq2[T, error]
+	chanStream                   // <-chan T
+	seqStream                    // iter.Seq[T]
+)
+
+var _ Type = streamType{}
+
+// streamType is a method's result or parameter, whose items are sent to the peer one by one as they are produced
+// it is never serialized as a whole, so it can only appear at the top level of method's params/results
+type streamType struct {
+	kind  streamKind
+	elemT Type
+	errT  Type       // the 'error' of iter.Seq2[T, error]. nil for other kinds
+	ni    *namedInfo // the 'iter.Seq2' or 'iter.Seq' part. nil for channels
+}
+
+// newStreamType returns false, if t is not a stream
+func (tr typeResolver) newStreamType(apiName string, t types.Type, astExpr ast.Expr) (streamType, bool, error) {
+	switch tt := t.(type) {
+	case *types.Chan:
+		if tt.Dir() != types.RecvOnly {
+			return streamType{}, false, nil
+		}
+		var elemAst ast.Expr
+		if astExpr != nil {
+			chanAst, ok := astExpr.(*ast.ChanType)
+			if !ok {
+				return streamType{}, false, fmt.Errorf("channel's astExpr is not *ast.ChanType, but %T", astExpr)
+			}
+			elemAst = chanAst.Value
+		}
+		elemT, err := tr.newType(apiName, tt.Elem(), elemAst)
+		if err != nil {
+			return streamType{}, false, fmt.Errorf("newType() for channel element %q: %w", tt.Elem(), err)
+		}
+		return streamType{kind: chanStream, elemT: elemT}, true, nil
+
+	case *types.Named:
+		obj := tt.Obj()
+		if obj.Pkg() == nil || obj.Pkg().Path() != "iter" {
+			return streamType{}, false, nil
+		}
+		switch obj.Name() {
+		case "Seq":
+			return tr.newSeqStreamType(apiName, tt, astExpr)
+		case "Seq2":
+		default:
+			return streamType{}, false, nil
+		}
+		typeArgs := tt.TypeArgs()
+		if typeArgs.Len() != 2 || !types.Identical(typeArgs.At(1), types.Universe.Lookup("error").Type()) {
+			return streamType{}, false, fmt.Errorf("only iter.Seq2[T, error] streams are supported, got %s", tt)
+		}
+
+		ni := &namedInfo{
+			namedName:  obj.Name(),
+			importSpec: importSpec{path: obj.Pkg().Path(), pkgName: obj.Pkg().Name()},
+		}
+		var elemAst, errAst ast.Expr
+		if astExpr != nil {
+			indexAst, ok := astExpr.(*ast.IndexListExpr)
+			if !ok {
+				return streamType{}, false, fmt.Errorf("iter.Seq2's astExpr is not *ast.IndexListExpr, but %T", astExpr)
+			}
+			ni.importSpec.alias = packagePrefixFromAst(indexAst.X)
+			elemAst, errAst = indexAst.Indices[0], indexAst.Indices[1]
+		}
+		elemT, err := tr.newType(apiName, typeArgs.At(0), elemAst)
+		if err != nil {
+			return streamType{}, false, fmt.Errorf("newType() for stream element %q: %w", typeArgs.At(0), err)
+		}
+		errT, err := tr.newType(apiName, typeArgs.At(1), errAst)
+		if err != nil {
+			return streamType{}, false, fmt.Errorf("newType() for stream error: %w", err)
+		}
+		return streamType{kind: seq2Stream, elemT: elemT, errT: errT, ni: ni}, true, nil
+	}
+
+	return streamType{}, false, nil
+}
+
+func (tr typeResolver) newSeqStreamType(apiName string, t *types.Named, astExpr ast.Expr) (streamType, bool, error) {
+	obj := t.Obj()
+	ni := &namedInfo{
+		namedName:  obj.Name(),
+		importSpec: importSpec{path: obj.Pkg().Path(), pkgName: obj.Pkg().Name()},
+	}
+	var elemAst ast.Expr
+	if astExpr != nil {
+		indexAst, ok := astExpr.(*ast.IndexExpr)
+		if !ok {
+			return streamType{}, false, fmt.Errorf("iter.Seq's astExpr is not *ast.IndexExpr, but %T", astExpr)
+		}
+		ni.importSpec.alias = packagePrefixFromAst(indexAst.X)
+		elemAst = indexAst.Index
+	}
+	elemT, err := tr.newType(apiName, t.TypeArgs().At(0), elemAst)
+	if err != nil {
+		return streamType{}, false, fmt.Errorf("newType() for stream element %q: %w", t.TypeArgs().At(0), err)
+	}
+	return streamType{kind: seqStream, elemT: elemT, ni: ni}, true, nil
+}
+
+// name implements Type.
+func (st streamType) name(q *qualifier) string {
+	switch st.kind {
+	case seq2Stream:
+		return q.qualifyNamedInfo(*st.ni) + "[" + st.elemT.name(q) + ", " + st.errT.name(q) + "]"
+	case chanStream:
+		return "<-chan " + st.elemT.name(q)
+	case seqStream:
+		return q.qualifyNamedInfo(*st.ni) + "[" + st.elemT.name(q) + "]"
+	default:
+		panic(fmt.Sprintf("unknown stream kind %d", st.kind))
+	}
+}
+
+// genEncFunc implements Type.
+// streams are never encoded as a whole. items are encoded one by one
+func (st streamType) genEncFunc(q *qualifier) string {
+	return ""
+}
+
+// genDecFunc implements Type.
+func (st streamType) genDecFunc(q *qualifier) string {
+	return ""
+}
+
+// codeblocks implements Type.
+// item's code blocks are generated with the stream's item struct
+func (st streamType) codeblocks(q *qualifier) []string {
+	return nil
+}
@@ -0,0 +1,112 @@
+package irpctestpkg
+
+import "time"
+
+//go:generate go run ../
+
+type hash32 [32]byte
+
+type vect3f [3]float64
+
+type arrayStruct struct {
+	id  hash32
+	pts [2]vect3f
+}
+
+type arrayTest interface {
+	ArraySum(a [4]int) int
+	ByteArrayXor(a, b [8]byte) [8]byte
+	NamedHashRev(h hash32) hash32
+	VectScale(v vect3f, s float64) vect3f
+	ArrayOfArrays(a [2][3]int) [3][2]int
+	ArrayOfSlices(a [2][]string) int
+	ArrayOfStructs(a [2]struct{ A int }) int
+	StructWithArray(s arrayStruct) arrayStruct
+	ArrayPtr(p *[2]int) *[2]int
+	ArrayOfTimes(ts [2]time.Time) [2]time.Time
+	EmptyArray(a [0]int) [0]int
+}
+
+var _ arrayTest = arrayTestImpl{}
+
+type arrayTestImpl struct{}
+
+// ArraySum implements arrayTest.
+func (arrayTestImpl) ArraySum(a [4]int) int {
+	var sum int
+	for _, v := range a {
+		sum += v
+	}
+	return sum
+}
+
+// ByteArrayXor implements arrayTest.
+func (arrayTestImpl) ByteArrayXor(a, b [8]byte) [8]byte {
+	var res [8]byte
+	for i := range res {
+		res[i] = a[i] ^ b[i]
+	}
+	return res
+}
+
+// NamedHashRev implements arrayTest.
+func (arrayTestImpl) NamedHashRev(h hash32) hash32 {
+	var res hash32
+	for i, b := range h {
+		res[len(h)-1-i] = b
+	}
+	return res
+}
+
+// VectScale implements arrayTest.
+func (arrayTestImpl) VectScale(v vect3f, s float64) vect3f {
+	for i := range v {
+		v[i] *= s
+	}
+	return v
+}
+
+// ArrayOfArrays implements arrayTest.
+func (arrayTestImpl) ArrayOfArrays(a [2][3]int) [3][2]int {
+	var res [3][2]int
+	for i := range a {
+		for j := range a[i] {
+			res[j][i] = a[i][j]
+		}
+	}
+	return res
+}
+
+// ArrayOfSlices implements arrayTest.
+func (arrayTestImpl) ArrayOfSlices(a [2][]string) int {
+	return len(a[0]) + len(a[1])
+}
+
+// ArrayOfStructs implements arrayTest.
+func (arrayTestImpl) ArrayOfStructs(a [2]struct{ A int }) int {
+	return a[0].A + a[1].A
+}
+
+// StructWithArray implements arrayTest.
+func (arrayTestImpl) StructWithArray(s arrayStruct) arrayStruct {
+	s.pts[0], s.pts[1] = s.pts[1], s.pts[0]
+	return s
+}
+
+// ArrayPtr implements arrayTest.
+func (arrayTestImpl) ArrayPtr(p *[2]int) *[2]int {
+	if p == nil {
+		return nil
+	}
+	return &[2]int{p[1], p[0]}
+}
+
+// ArrayOfTimes implements arrayTest.
+func (arrayTestImpl) ArrayOfTimes(ts [2]time.Time) [2]time.Time {
+	return [2]time.Time{ts[1], ts[0]}
+}
+
+// EmptyArray implements arrayTest.
+func (arrayTestImpl) EmptyArray(a [0]int) [0]int {
+	return a
+}
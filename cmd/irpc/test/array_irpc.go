@@ -0,0 +1,886 @@
+// Code generated by irpc (devel); DO NOT EDIT
+// Source: github.com/marben/irpc/cmd/irpc/test/array.go
+package irpctestpkg
+
+import (
+	"context"
+	"fmt"
+	"github.com/marben/irpc/irpcgen"
+	"time"
+)
+
+var _arrayTestIrpcId = irpcgen.ServiceId(0xe04a2b781f0a02b3)
+
+var _arrayTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _arrayTestIrpcId,
+	Name:      "arrayTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.arrayTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "ArraySum", Params: []irpcgen.ParamDesc{{Name: "a", Type: "[4]int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "ByteArrayXor", Params: []irpcgen.ParamDesc{{Name: "a", Type: "[8]byte"}, {Name: "b", Type: "[8]byte"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[8]byte"}}, HasContext: false},
+		{Name: "NamedHashRev", Params: []irpcgen.ParamDesc{{Name: "h", Type: "hash32"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "hash32"}}, HasContext: false},
+		{Name: "VectScale", Params: []irpcgen.ParamDesc{{Name: "v", Type: "vect3f"}, {Name: "s", Type: "float64"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "vect3f"}}, HasContext: false},
+		{Name: "ArrayOfArrays", Params: []irpcgen.ParamDesc{{Name: "a", Type: "[2][3]int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[3][2]int"}}, HasContext: false},
+		{Name: "ArrayOfSlices", Params: []irpcgen.ParamDesc{{Name: "a", Type: "[2][]string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "ArrayOfStructs", Params: []irpcgen.ParamDesc{{Name: "a", Type: "[2]struct{A int;}"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "StructWithArray", Params: []irpcgen.ParamDesc{{Name: "s", Type: "arrayStruct"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "arrayStruct"}}, HasContext: false},
+		{Name: "ArrayPtr", Params: []irpcgen.ParamDesc{{Name: "p", Type: "*[2]int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "*[2]int"}}, HasContext: false},
+		{Name: "ArrayOfTimes", Params: []irpcgen.ParamDesc{{Name: "ts", Type: "[2]time.Time"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[2]time.Time"}}, HasContext: false},
+		{Name: "EmptyArray", Params: []irpcgen.ParamDesc{{Name: "a", Type: "[0]int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[0]int"}}, HasContext: false},
+	},
+}
+
+// arrayTestIrpcService provides [arrayTest] interface over irpc
+type arrayTestIrpcService struct {
+	impl arrayTest
+}
+
+// newArrayTestIrpcService returns new [irpcgen.Service] forwarding [arrayTest] network calls to impl
+func newArrayTestIrpcService(impl arrayTest) *arrayTestIrpcService {
+	return &arrayTestIrpcService{
+		impl: impl,
+	}
+}
+
+// Id implements [irpcgen.Service] interface.
+func (s *arrayTestIrpcService) Id() irpcgen.ServiceId {
+	return _arrayTestIrpcId
+}
+
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *arrayTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_arrayTestIrpcDesc
+}
+
+// GetFuncCall implements [irpcgen.Service] interface
+func (s *arrayTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
+	switch funcId {
+	case 0: // ArraySum
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_ArraySumReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_ArraySumResp
+				resp.p0 = s.impl.ArraySum(args.a)
+				return resp
+			}, nil
+		}, nil
+	case 1: // ByteArrayXor
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_ByteArrayXorReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_ByteArrayXorResp
+				resp.p0 = s.impl.ByteArrayXor(args.a, args.b)
+				return resp
+			}, nil
+		}, nil
+	case 2: // NamedHashRev
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_NamedHashRevReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_NamedHashRevResp
+				resp.p0 = s.impl.NamedHashRev(args.h)
+				return resp
+			}, nil
+		}, nil
+	case 3: // VectScale
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_VectScaleReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_VectScaleResp
+				resp.p0 = s.impl.VectScale(args.v, args.s)
+				return resp
+			}, nil
+		}, nil
+	case 4: // ArrayOfArrays
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_ArrayOfArraysReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_ArrayOfArraysResp
+				resp.p0 = s.impl.ArrayOfArrays(args.a)
+				return resp
+			}, nil
+		}, nil
+	case 5: // ArrayOfSlices
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_ArrayOfSlicesReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_ArrayOfSlicesResp
+				resp.p0 = s.impl.ArrayOfSlices(args.a)
+				return resp
+			}, nil
+		}, nil
+	case 6: // ArrayOfStructs
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_ArrayOfStructsReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_ArrayOfStructsResp
+				resp.p0 = s.impl.ArrayOfStructs(args.a)
+				return resp
+			}, nil
+		}, nil
+	case 7: // StructWithArray
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_StructWithArrayReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_StructWithArrayResp
+				resp.p0 = s.impl.StructWithArray(args.s)
+				return resp
+			}, nil
+		}, nil
+	case 8: // ArrayPtr
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_ArrayPtrReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_ArrayPtrResp
+				resp.p0 = s.impl.ArrayPtr(args.p)
+				return resp
+			}, nil
+		}, nil
+	case 9: // ArrayOfTimes
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_ArrayOfTimesReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_ArrayOfTimesResp
+				resp.p0 = s.impl.ArrayOfTimes(args.ts)
+				return resp
+			}, nil
+		}, nil
+	case 10: // EmptyArray
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_arrayTest_EmptyArrayReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_arrayTest_EmptyArrayResp
+				resp.p0 = s.impl.EmptyArray(args.a)
+				return resp
+			}, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("function '%d' doesn't exist on service '%s'", funcId, s.Id())
+	}
+}
+
+// arrayTestIrpcClient implements [arrayTest] interface. It by forwards calls over network to [arrayTestIrpcService] that provides the implementation.
+type arrayTestIrpcClient struct {
+	endpoint irpcgen.Endpoint
+}
+
+func newArrayTestIrpcClient(endpoint irpcgen.Endpoint) (*arrayTestIrpcClient, error) {
+	if err := irpcgen.RegisterClient(endpoint, &_arrayTestIrpcDesc); err != nil {
+		return nil, fmt.Errorf("register failed: %w", err)
+	}
+	return &arrayTestIrpcClient{endpoint: endpoint}, nil
+}
+
+// ArraySum implements [arrayTest]
+//
+func (_c *arrayTestIrpcClient) ArraySum(a [4]int) int {
+	var req = _irpc_arrayTest_ArraySumReq{
+		a: a,
+	}
+	var resp _irpc_arrayTest_ArraySumResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 0, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// ByteArrayXor implements [arrayTest]
+func (_c *arrayTestIrpcClient) ByteArrayXor(a [8]byte, b [8]byte) [8]byte {
+	var req = _irpc_arrayTest_ByteArrayXorReq{
+		a: a,
+		b: b,
+	}
+	var resp _irpc_arrayTest_ByteArrayXorResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 1, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// NamedHashRev implements [arrayTest]
+func (_c *arrayTestIrpcClient) NamedHashRev(h hash32) hash32 {
+	var req = _irpc_arrayTest_NamedHashRevReq{
+		h: h,
+	}
+	var resp _irpc_arrayTest_NamedHashRevResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 2, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// VectScale implements [arrayTest]
+func (_c *arrayTestIrpcClient) VectScale(v vect3f, s float64) vect3f {
+	var req = _irpc_arrayTest_VectScaleReq{
+		v: v,
+		s: s,
+	}
+	var resp _irpc_arrayTest_VectScaleResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 3, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// ArrayOfArrays implements [arrayTest]
+func (_c *arrayTestIrpcClient) ArrayOfArrays(a [2][3]int) [3][2]int {
+	var req = _irpc_arrayTest_ArrayOfArraysReq{
+		a: a,
+	}
+	var resp _irpc_arrayTest_ArrayOfArraysResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 4, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// ArrayOfSlices implements [arrayTest]
+func (_c *arrayTestIrpcClient) ArrayOfSlices(a [2][]string) int {
+	var req = _irpc_arrayTest_ArrayOfSlicesReq{
+		a: a,
+	}
+	var resp _irpc_arrayTest_ArrayOfSlicesResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 5, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// ArrayOfStructs implements [arrayTest]
+func (_c *arrayTestIrpcClient) ArrayOfStructs(a [2]struct{ A int }) int {
+	var req = _irpc_arrayTest_ArrayOfStructsReq{
+		a: a,
+	}
+	var resp _irpc_arrayTest_ArrayOfStructsResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 6, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// StructWithArray implements [arrayTest]
+func (_c *arrayTestIrpcClient) StructWithArray(s arrayStruct) arrayStruct {
+	var req = _irpc_arrayTest_StructWithArrayReq{
+		s: s,
+	}
+	var resp _irpc_arrayTest_StructWithArrayResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 7, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// ArrayPtr implements [arrayTest]
+func (_c *arrayTestIrpcClient) ArrayPtr(p *[2]int) *[2]int {
+	var req = _irpc_arrayTest_ArrayPtrReq{
+		p: p,
+	}
+	var resp _irpc_arrayTest_ArrayPtrResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 8, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// ArrayOfTimes implements [arrayTest]
+func (_c *arrayTestIrpcClient) ArrayOfTimes(ts [2]time.Time) [2]time.Time {
+	var req = _irpc_arrayTest_ArrayOfTimesReq{
+		ts: ts,
+	}
+	var resp _irpc_arrayTest_ArrayOfTimesResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 9, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// EmptyArray implements [arrayTest]
+func (_c *arrayTestIrpcClient) EmptyArray(a [0]int) [0]int {
+	var req = _irpc_arrayTest_EmptyArrayReq{
+		a: a,
+	}
+	var resp _irpc_arrayTest_EmptyArrayResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _arrayTestIrpcId, 10, req, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+type _irpc_arrayTest_ArraySumReq struct {
+	a [4]int
+}
+
+func (s _irpc_arrayTest_ArraySumReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [4]int) error {
+		return irpcgen.EncArray(enc, a[:], "int", irpcgen.EncInt)
+	}(e, s.a); err != nil {
+		return fmt.Errorf("serialize \"a\" of type [4]int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArraySumReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[4]int) error {
+		return irpcgen.DecArray(dec, a[:], "int", irpcgen.DecInt)
+	}(d, &s.a); err != nil {
+		return fmt.Errorf("deserialize a of type [4]int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArraySumResp struct {
+	p0 int
+}
+
+func (s _irpc_arrayTest_ArraySumResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArraySumResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ByteArrayXorReq struct {
+	a [8]byte
+	b [8]byte
+}
+
+func (s _irpc_arrayTest_ByteArrayXorReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [8]byte) error {
+		return irpcgen.EncArray(enc, a[:], "byte", irpcgen.EncUint8)
+	}(e, s.a); err != nil {
+		return fmt.Errorf("serialize \"a\" of type [8]byte: %w", err)
+	}
+	if err := func(enc *irpcgen.Encoder, a [8]byte) error {
+		return irpcgen.EncArray(enc, a[:], "byte", irpcgen.EncUint8)
+	}(e, s.b); err != nil {
+		return fmt.Errorf("serialize \"b\" of type [8]byte: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ByteArrayXorReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[8]byte) error {
+		return irpcgen.DecArray(dec, a[:], "byte", irpcgen.DecUint8)
+	}(d, &s.a); err != nil {
+		return fmt.Errorf("deserialize a of type [8]byte: %w", err)
+	}
+	if err := func(dec *irpcgen.Decoder, a *[8]byte) error {
+		return irpcgen.DecArray(dec, a[:], "byte", irpcgen.DecUint8)
+	}(d, &s.b); err != nil {
+		return fmt.Errorf("deserialize b of type [8]byte: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ByteArrayXorResp struct {
+	p0 [8]byte
+}
+
+func (s _irpc_arrayTest_ByteArrayXorResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [8]byte) error {
+		return irpcgen.EncArray(enc, a[:], "byte", irpcgen.EncUint8)
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type [8]byte: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ByteArrayXorResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[8]byte) error {
+		return irpcgen.DecArray(dec, a[:], "byte", irpcgen.DecUint8)
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type [8]byte: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_NamedHashRevReq struct {
+	h hash32
+}
+
+func (s _irpc_arrayTest_NamedHashRevReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a hash32) error {
+		return irpcgen.EncArray(enc, a[:], "byte", irpcgen.EncUint8)
+	}(e, s.h); err != nil {
+		return fmt.Errorf("serialize \"h\" of type hash32: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_NamedHashRevReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *hash32) error {
+		return irpcgen.DecArray(dec, a[:], "byte", irpcgen.DecUint8)
+	}(d, &s.h); err != nil {
+		return fmt.Errorf("deserialize h of type hash32: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_NamedHashRevResp struct {
+	p0 hash32
+}
+
+func (s _irpc_arrayTest_NamedHashRevResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a hash32) error {
+		return irpcgen.EncArray(enc, a[:], "byte", irpcgen.EncUint8)
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type hash32: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_NamedHashRevResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *hash32) error {
+		return irpcgen.DecArray(dec, a[:], "byte", irpcgen.DecUint8)
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type hash32: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_VectScaleReq struct {
+	v vect3f
+	s float64
+}
+
+func (s _irpc_arrayTest_VectScaleReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a vect3f) error {
+		return irpcgen.EncArray(enc, a[:], "float64", irpcgen.EncFloat64)
+	}(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type vect3f: %w", err)
+	}
+	if err := irpcgen.EncFloat64(e, s.s); err != nil {
+		return fmt.Errorf("serialize \"s\" of type float64: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_VectScaleReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *vect3f) error {
+		return irpcgen.DecArray(dec, a[:], "float64", irpcgen.DecFloat64)
+	}(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type vect3f: %w", err)
+	}
+	if err := irpcgen.DecFloat64(d, &s.s); err != nil {
+		return fmt.Errorf("deserialize s of type float64: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_VectScaleResp struct {
+	p0 vect3f
+}
+
+func (s _irpc_arrayTest_VectScaleResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a vect3f) error {
+		return irpcgen.EncArray(enc, a[:], "float64", irpcgen.EncFloat64)
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type vect3f: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_VectScaleResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *vect3f) error {
+		return irpcgen.DecArray(dec, a[:], "float64", irpcgen.DecFloat64)
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type vect3f: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayOfArraysReq struct {
+	a [2][3]int
+}
+
+func (s _irpc_arrayTest_ArrayOfArraysReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [2][3]int) error {
+		return irpcgen.EncArray(enc, a[:], "[3]int", func(enc *irpcgen.Encoder, a [3]int) error {
+			return irpcgen.EncArray(enc, a[:], "int", irpcgen.EncInt)
+		})
+	}(e, s.a); err != nil {
+		return fmt.Errorf("serialize \"a\" of type [2][3]int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayOfArraysReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[2][3]int) error {
+		return irpcgen.DecArray(dec, a[:], "[3]int", func(dec *irpcgen.Decoder, a *[3]int) error {
+			return irpcgen.DecArray(dec, a[:], "int", irpcgen.DecInt)
+		})
+	}(d, &s.a); err != nil {
+		return fmt.Errorf("deserialize a of type [2][3]int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayOfArraysResp struct {
+	p0 [3][2]int
+}
+
+func (s _irpc_arrayTest_ArrayOfArraysResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [3][2]int) error {
+		return irpcgen.EncArray(enc, a[:], "[2]int", func(enc *irpcgen.Encoder, a [2]int) error {
+			return irpcgen.EncArray(enc, a[:], "int", irpcgen.EncInt)
+		})
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type [3][2]int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayOfArraysResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[3][2]int) error {
+		return irpcgen.DecArray(dec, a[:], "[2]int", func(dec *irpcgen.Decoder, a *[2]int) error {
+			return irpcgen.DecArray(dec, a[:], "int", irpcgen.DecInt)
+		})
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type [3][2]int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayOfSlicesReq struct {
+	a [2][]string
+}
+
+func (s _irpc_arrayTest_ArrayOfSlicesReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [2][]string) error {
+		return irpcgen.EncArray(enc, a[:], "[]string", func(enc *irpcgen.Encoder, sl []string) error {
+			return irpcgen.EncSlice(enc, sl, "string", irpcgen.EncString)
+		})
+	}(e, s.a); err != nil {
+		return fmt.Errorf("serialize \"a\" of type [2][]string: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayOfSlicesReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[2][]string) error {
+		return irpcgen.DecArray(dec, a[:], "[]string", func(dec *irpcgen.Decoder, sl *[]string) error {
+			return irpcgen.DecSlice(dec, sl, "string", irpcgen.DecString)
+		})
+	}(d, &s.a); err != nil {
+		return fmt.Errorf("deserialize a of type [2][]string: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayOfSlicesResp struct {
+	p0 int
+}
+
+func (s _irpc_arrayTest_ArrayOfSlicesResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayOfSlicesResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayOfStructsReq struct {
+	a [2]struct{ A int }
+}
+
+func (s _irpc_arrayTest_ArrayOfStructsReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [2]struct{ A int }) error {
+		return irpcgen.EncArray(enc, a[:], "struct{A int;}", func(enc *irpcgen.Encoder, s struct{ A int }) error {
+			if err := irpcgen.EncInt(enc, s.A); err != nil {
+				return fmt.Errorf("serialize s.A of type int: %w", err)
+			}
+			return nil
+		})
+	}(e, s.a); err != nil {
+		return fmt.Errorf("serialize \"a\" of type [2]struct{A int;}: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayOfStructsReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[2]struct{ A int }) error {
+		return irpcgen.DecArray(dec, a[:], "struct{A int;}", func(dec *irpcgen.Decoder, s *struct{ A int }) error {
+			if err := irpcgen.DecInt(dec, &s.A); err != nil {
+				return fmt.Errorf("deserialize s.A of type int: %w", err)
+			}
+			return nil
+		})
+	}(d, &s.a); err != nil {
+		return fmt.Errorf("deserialize a of type [2]struct{A int;}: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayOfStructsResp struct {
+	p0 int
+}
+
+func (s _irpc_arrayTest_ArrayOfStructsResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayOfStructsResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_StructWithArrayReq struct {
+	s arrayStruct
+}
+
+func (s _irpc_arrayTest_StructWithArrayReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, s arrayStruct) error {
+		if err := func(enc *irpcgen.Encoder, a hash32) error {
+			return irpcgen.EncArray(enc, a[:], "byte", irpcgen.EncUint8)
+		}(enc, s.id); err != nil {
+			return fmt.Errorf("serialize s.id of type hash32: %w", err)
+		}
+		if err := func(enc *irpcgen.Encoder, a [2]vect3f) error {
+			return irpcgen.EncArray(enc, a[:], "vect3f", func(enc *irpcgen.Encoder, a vect3f) error {
+				return irpcgen.EncArray(enc, a[:], "float64", irpcgen.EncFloat64)
+			})
+		}(enc, s.pts); err != nil {
+			return fmt.Errorf("serialize s.pts of type [2]vect3f: %w", err)
+		}
+		return nil
+	}(e, s.s); err != nil {
+		return fmt.Errorf("serialize \"s\" of type arrayStruct: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_StructWithArrayReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, s *arrayStruct) error {
+		if err := func(dec *irpcgen.Decoder, a *hash32) error {
+			return irpcgen.DecArray(dec, a[:], "byte", irpcgen.DecUint8)
+		}(dec, &s.id); err != nil {
+			return fmt.Errorf("deserialize s.id of type hash32: %w", err)
+		}
+		if err := func(dec *irpcgen.Decoder, a *[2]vect3f) error {
+			return irpcgen.DecArray(dec, a[:], "vect3f", func(dec *irpcgen.Decoder, a *vect3f) error {
+				return irpcgen.DecArray(dec, a[:], "float64", irpcgen.DecFloat64)
+			})
+		}(dec, &s.pts); err != nil {
+			return fmt.Errorf("deserialize s.pts of type [2]vect3f: %w", err)
+		}
+		return nil
+	}(d, &s.s); err != nil {
+		return fmt.Errorf("deserialize s of type arrayStruct: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_StructWithArrayResp struct {
+	p0 arrayStruct
+}
+
+func (s _irpc_arrayTest_StructWithArrayResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, s arrayStruct) error {
+		if err := func(enc *irpcgen.Encoder, a hash32) error {
+			return irpcgen.EncArray(enc, a[:], "byte", irpcgen.EncUint8)
+		}(enc, s.id); err != nil {
+			return fmt.Errorf("serialize s.id of type hash32: %w", err)
+		}
+		if err := func(enc *irpcgen.Encoder, a [2]vect3f) error {
+			return irpcgen.EncArray(enc, a[:], "vect3f", func(enc *irpcgen.Encoder, a vect3f) error {
+				return irpcgen.EncArray(enc, a[:], "float64", irpcgen.EncFloat64)
+			})
+		}(enc, s.pts); err != nil {
+			return fmt.Errorf("serialize s.pts of type [2]vect3f: %w", err)
+		}
+		return nil
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type arrayStruct: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_StructWithArrayResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, s *arrayStruct) error {
+		if err := func(dec *irpcgen.Decoder, a *hash32) error {
+			return irpcgen.DecArray(dec, a[:], "byte", irpcgen.DecUint8)
+		}(dec, &s.id); err != nil {
+			return fmt.Errorf("deserialize s.id of type hash32: %w", err)
+		}
+		if err := func(dec *irpcgen.Decoder, a *[2]vect3f) error {
+			return irpcgen.DecArray(dec, a[:], "vect3f", func(dec *irpcgen.Decoder, a *vect3f) error {
+				return irpcgen.DecArray(dec, a[:], "float64", irpcgen.DecFloat64)
+			})
+		}(dec, &s.pts); err != nil {
+			return fmt.Errorf("deserialize s.pts of type [2]vect3f: %w", err)
+		}
+		return nil
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type arrayStruct: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayPtrReq struct {
+	p *[2]int
+}
+
+func (s _irpc_arrayTest_ArrayPtrReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, pt *[2]int) error {
+		return irpcgen.EncPointer(enc, pt, "[2]int", func(enc *irpcgen.Encoder, a [2]int) error {
+			return irpcgen.EncArray(enc, a[:], "int", irpcgen.EncInt)
+		})
+	}(e, s.p); err != nil {
+		return fmt.Errorf("serialize \"p\" of type *[2]int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayPtrReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, pt **[2]int) error {
+		return irpcgen.DecPointer(dec, pt, "[2]int", func(dec *irpcgen.Decoder, a *[2]int) error {
+			return irpcgen.DecArray(dec, a[:], "int", irpcgen.DecInt)
+		})
+	}(d, &s.p); err != nil {
+		return fmt.Errorf("deserialize p of type *[2]int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayPtrResp struct {
+	p0 *[2]int
+}
+
+func (s _irpc_arrayTest_ArrayPtrResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, pt *[2]int) error {
+		return irpcgen.EncPointer(enc, pt, "[2]int", func(enc *irpcgen.Encoder, a [2]int) error {
+			return irpcgen.EncArray(enc, a[:], "int", irpcgen.EncInt)
+		})
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type *[2]int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayPtrResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, pt **[2]int) error {
+		return irpcgen.DecPointer(dec, pt, "[2]int", func(dec *irpcgen.Decoder, a *[2]int) error {
+			return irpcgen.DecArray(dec, a[:], "int", irpcgen.DecInt)
+		})
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type *[2]int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayOfTimesReq struct {
+	ts [2]time.Time
+}
+
+func (s _irpc_arrayTest_ArrayOfTimesReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [2]time.Time) error {
+		return irpcgen.EncArray(enc, a[:], "time.Time", irpcgen.EncBinaryMarshaler)
+	}(e, s.ts); err != nil {
+		return fmt.Errorf("serialize \"ts\" of type [2]time.Time: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayOfTimesReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[2]time.Time) error {
+		return irpcgen.DecArray(dec, a[:], "time.Time", irpcgen.DecBinaryUnmarshaler)
+	}(d, &s.ts); err != nil {
+		return fmt.Errorf("deserialize ts of type [2]time.Time: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_ArrayOfTimesResp struct {
+	p0 [2]time.Time
+}
+
+func (s _irpc_arrayTest_ArrayOfTimesResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [2]time.Time) error {
+		return irpcgen.EncArray(enc, a[:], "time.Time", irpcgen.EncBinaryMarshaler)
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type [2]time.Time: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_ArrayOfTimesResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[2]time.Time) error {
+		return irpcgen.DecArray(dec, a[:], "time.Time", irpcgen.DecBinaryUnmarshaler)
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type [2]time.Time: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_EmptyArrayReq struct {
+	a [0]int
+}
+
+func (s _irpc_arrayTest_EmptyArrayReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [0]int) error {
+		return irpcgen.EncArray(enc, a[:], "int", irpcgen.EncInt)
+	}(e, s.a); err != nil {
+		return fmt.Errorf("serialize \"a\" of type [0]int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_EmptyArrayReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[0]int) error {
+		return irpcgen.DecArray(dec, a[:], "int", irpcgen.DecInt)
+	}(d, &s.a); err != nil {
+		return fmt.Errorf("deserialize a of type [0]int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_arrayTest_EmptyArrayResp struct {
+	p0 [0]int
+}
+
+func (s _irpc_arrayTest_EmptyArrayResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, a [0]int) error {
+		return irpcgen.EncArray(enc, a[:], "int", irpcgen.EncInt)
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type [0]int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_arrayTest_EmptyArrayResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, a *[0]int) error {
+		return irpcgen.DecArray(dec, a[:], "int", irpcgen.DecInt)
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type [0]int: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package irpctestpkg
+
+import (
+	"testing"
+	"time"
+
+	"github.com/marben/irpc/cmd/irpc/test/testtools"
+)
+
+func TestArray(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	remoteEp.RegisterService(newArrayTestIrpcService(arrayTestImpl{}))
+	c, err := newArrayTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if res := c.ArraySum([4]int{1, 2, 3, 4}); res != 1+2+3+4 {
+		t.Fatalf("ArraySum(): %d", res)
+	}
+
+	resX := c.ByteArrayXor([8]byte{0xff, 0, 1, 2, 3, 4, 5, 6}, [8]byte{0x0f, 0, 1, 0, 0, 0, 0, 0xff})
+	if resX != [8]byte{0xf0, 0, 0, 2, 3, 4, 5, 0xf9} {
+		t.Fatalf("ByteArrayXor(): %v", resX)
+	}
+
+	var h hash32
+	for i := range h {
+		h[i] = byte(i)
+	}
+	resH := c.NamedHashRev(h)
+	for i := range resH {
+		if resH[i] != byte(len(h)-1-i) {
+			t.Fatalf("NamedHashRev(): %v", resH)
+		}
+	}
+
+	if resV := c.VectScale(vect3f{1, 2.5, -3}, 2); resV != (vect3f{2, 5, -6}) {
+		t.Fatalf("VectScale(): %v", resV)
+	}
+
+	resAA := c.ArrayOfArrays([2][3]int{{1, 2, 3}, {4, 5, 6}})
+	if resAA != [3][2]int{{1, 4}, {2, 5}, {3, 6}} {
+		t.Fatalf("ArrayOfArrays(): %v", resAA)
+	}
+
+	if res := c.ArrayOfSlices([2][]string{{"a", "b"}, nil}); res != 2 {
+		t.Fatalf("ArrayOfSlices(): %d", res)
+	}
+
+	if res := c.ArrayOfStructs([2]struct{ A int }{{A: 3}, {A: 7}}); res != 10 {
+		t.Fatalf("ArrayOfStructs(): %d", res)
+	}
+
+	s := arrayStruct{id: h, pts: [2]vect3f{{1, 2, 3}, {4, 5, 6}}}
+	resS := c.StructWithArray(s)
+	if resS.id != s.id || resS.pts[0] != s.pts[1] || resS.pts[1] != s.pts[0] {
+		t.Fatalf("StructWithArray(): %v", resS)
+	}
+
+	if res := c.ArrayPtr(&[2]int{1, 2}); res == nil || *res != [2]int{2, 1} {
+		t.Fatalf("ArrayPtr(): %v", res)
+	}
+	if res := c.ArrayPtr(nil); res != nil {
+		t.Fatalf("ArrayPtr(nil): %v", res)
+	}
+
+	now := time.Now()
+	resT := c.ArrayOfTimes([2]time.Time{now, now.Add(time.Hour)})
+	if !resT[0].Equal(now.Add(time.Hour)) || !resT[1].Equal(now) {
+		t.Fatalf("ArrayOfTimes(): %v", resT)
+	}
+
+	c.EmptyArray([0]int{})
+}
@@ -8,7 +8,32 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _basicAPIIrpcId = irpcgen.ServiceId(0xdea07c83779db0a2)
+var _basicAPIIrpcId = irpcgen.ServiceId(0xc3a1cdf9871a1952)
+
+var _basicAPIIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _basicAPIIrpcId,
+	Name:      "basicAPI",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.basicAPI",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "addByte", Params: []irpcgen.ParamDesc{{Name: "a", Type: "byte"}, {Name: "b", Type: "byte"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "byte"}}, HasContext: false},
+		{Name: "addInt", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "swapInt", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}, {Name: "", Type: "int"}}, HasContext: false},
+		{Name: "subUint", Params: []irpcgen.ParamDesc{{Name: "a", Type: "uint"}, {Name: "b", Type: "uint"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "uint"}}, HasContext: false},
+		{Name: "addInt8", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int8"}, {Name: "b", Type: "int8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int8"}}, HasContext: false},
+		{Name: "addUint8", Params: []irpcgen.ParamDesc{{Name: "a", Type: "uint8"}, {Name: "b", Type: "uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "uint8"}}, HasContext: false},
+		{Name: "addInt16", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int16"}, {Name: "b", Type: "int16"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int16"}}, HasContext: false},
+		{Name: "addUint16", Params: []irpcgen.ParamDesc{{Name: "a", Type: "uint16"}, {Name: "b", Type: "uint16"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "uint16"}}, HasContext: false},
+		{Name: "addInt32", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int32"}, {Name: "b", Type: "int32"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int32"}}, HasContext: false},
+		{Name: "addUint32", Params: []irpcgen.ParamDesc{{Name: "a", Type: "uint32"}, {Name: "b", Type: "uint32"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "uint32"}}, HasContext: false},
+		{Name: "addInt64", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int64"}, {Name: "b", Type: "int64"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int64"}}, HasContext: false},
+		{Name: "addUint64", Params: []irpcgen.ParamDesc{{Name: "a", Type: "uint64"}, {Name: "b", Type: "uint64"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "uint64"}}, HasContext: false},
+		{Name: "addFloat64", Params: []irpcgen.ParamDesc{{Name: "a", Type: "float64"}, {Name: "b", Type: "float64"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "float64"}}, HasContext: false},
+		{Name: "addFloat32", Params: []irpcgen.ParamDesc{{Name: "a", Type: "float32"}, {Name: "b", Type: "float32"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "float32"}}, HasContext: false},
+		{Name: "toUpper", Params: []irpcgen.ParamDesc{{Name: "c", Type: "rune"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "rune"}}, HasContext: false},
+		{Name: "toUpperString", Params: []irpcgen.ParamDesc{{Name: "s", Type: "string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}}, HasContext: false},
+		{Name: "negBool", Params: []irpcgen.ParamDesc{{Name: "ok", Type: "bool"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+	},
+}
 
 // basicAPIIrpcService provides [basicAPI] interface over irpc
 type basicAPIIrpcService struct {
@@ -27,6 +52,11 @@ func (s *basicAPIIrpcService) Id() irpcgen.ServiceId {
 	return _basicAPIIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *basicAPIIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_basicAPIIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *basicAPIIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -248,7 +278,7 @@ type basicAPIIrpcClient struct {
 }
 
 func newBasicAPIIrpcClient(endpoint irpcgen.Endpoint) (*basicAPIIrpcClient, error) {
-	if err := endpoint.RegisterClient(_basicAPIIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_basicAPIIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &basicAPIIrpcClient{endpoint: endpoint}, nil
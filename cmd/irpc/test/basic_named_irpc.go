@@ -9,7 +9,19 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _basicNamedAPIIrpcId = irpcgen.ServiceId(0x1a46ba8c402c8424)
+var _basicNamedAPIIrpcId = irpcgen.ServiceId(0x1b4c5ca7cd1df0c1)
+
+var _basicNamedAPIIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _basicNamedAPIIrpcId,
+	Name:      "basicNamedAPI",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.basicNamedAPI",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "addFakeUint8", Params: []irpcgen.ParamDesc{{Name: "a", Type: "out2.Uint8"}, {Name: "b", Type: "out2.Uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "FakeUint8"}}, HasContext: false},
+		{Name: "addUint8", Params: []irpcgen.ParamDesc{{Name: "a", Type: "uint8"}, {Name: "b", Type: "uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "uint8"}}, HasContext: false},
+		{Name: "addByte", Params: []irpcgen.ParamDesc{{Name: "a", Type: "byte"}, {Name: "b", Type: "byte"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "byte"}}, HasContext: false},
+		{Name: "retNamedString", Params: []irpcgen.ParamDesc{{Name: "ns", Type: "NamedString"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}}, HasContext: false},
+	},
+}
 
 // basicNamedAPIIrpcService provides [basicNamedAPI] interface over irpc
 type basicNamedAPIIrpcService struct {
@@ -28,6 +40,11 @@ func (s *basicNamedAPIIrpcService) Id() irpcgen.ServiceId {
 	return _basicNamedAPIIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *basicNamedAPIIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_basicNamedAPIIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *basicNamedAPIIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -90,7 +107,7 @@ type basicNamedAPIIrpcClient struct {
 }
 
 func newBasicNamedAPIIrpcClient(endpoint irpcgen.Endpoint) (*basicNamedAPIIrpcClient, error) {
-	if err := endpoint.RegisterClient(_basicNamedAPIIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_basicNamedAPIIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &basicNamedAPIIrpcClient{endpoint: endpoint}, nil
@@ -9,7 +9,21 @@ import (
 	"time"
 )
 
-var _binMarshalIrpcId = irpcgen.ServiceId(0xb76d72a44428062e)
+var _binMarshalIrpcId = irpcgen.ServiceId(0x518fc193981db560)
+
+var _binMarshalIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _binMarshalIrpcId,
+	Name:      "binMarshal",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.binMarshal",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "reflect", Params: []irpcgen.ParamDesc{{Name: "t", Type: "time.Time"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "time.Time"}}, HasContext: false},
+		{Name: "addHour", Params: []irpcgen.ParamDesc{{Name: "t", Type: "time.Time"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "time.Time"}}, HasContext: false},
+		{Name: "addMyHour", Params: []irpcgen.ParamDesc{{Name: "t", Type: "myTime"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "myTime"}}, HasContext: false},
+		{Name: "addMyStructHour", Params: []irpcgen.ParamDesc{{Name: "t", Type: "myStructTime"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "myStructTime"}}, HasContext: false},
+		{Name: "structPass", Params: []irpcgen.ParamDesc{{Name: "st", Type: "structContainingBinMarshallable"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "structContainingBinMarshallable"}}, HasContext: false},
+		{Name: "myMarshalableStructPass", Params: []irpcgen.ParamDesc{{Name: "s", Type: "myMarshallableStruct"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}}, HasContext: false},
+	},
+}
 
 // binMarshalIrpcService provides [binMarshal] interface over irpc
 type binMarshalIrpcService struct {
@@ -28,6 +42,11 @@ func (s *binMarshalIrpcService) Id() irpcgen.ServiceId {
 	return _binMarshalIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *binMarshalIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_binMarshalIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *binMarshalIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -114,7 +133,7 @@ type binMarshalIrpcClient struct {
 }
 
 func newBinMarshalIrpcClient(endpoint irpcgen.Endpoint) (*binMarshalIrpcClient, error) {
-	if err := endpoint.RegisterClient(_binMarshalIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_binMarshalIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &binMarshalIrpcClient{endpoint: endpoint}, nil
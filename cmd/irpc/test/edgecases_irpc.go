@@ -8,7 +8,14 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _emptyAPIIrpcId = irpcgen.ServiceId(0x2ef6d05bf89afe15)
+var _emptyAPIIrpcId = irpcgen.ServiceId(0x718283a195cbab2c)
+
+var _emptyAPIIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _emptyAPIIrpcId,
+	Name:      "emptyAPI",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.emptyAPI",
+	Methods:   []irpcgen.MethodDesc{},
+}
 
 // emptyAPIIrpcService provides [emptyAPI] interface over irpc
 type emptyAPIIrpcService struct {
@@ -27,6 +34,11 @@ func (s *emptyAPIIrpcService) Id() irpcgen.ServiceId {
 	return _emptyAPIIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *emptyAPIIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_emptyAPIIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *emptyAPIIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -41,13 +53,29 @@ type emptyAPIIrpcClient struct {
 }
 
 func newEmptyAPIIrpcClient(endpoint irpcgen.Endpoint) (*emptyAPIIrpcClient, error) {
-	if err := endpoint.RegisterClient(_emptyAPIIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_emptyAPIIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &emptyAPIIrpcClient{endpoint: endpoint}, nil
 }
 
-var _edgeCasesIrpcId = irpcgen.ServiceId(0x3ae6963c63768097)
+var _edgeCasesIrpcId = irpcgen.ServiceId(0x3bdeee93bac118df)
+
+var _edgeCasesIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _edgeCasesIrpcId,
+	Name:      "edgeCases",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.edgeCases",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "noReturn", Params: []irpcgen.ParamDesc{{Name: "i", Type: "int"}}, Results: nil, HasContext: false},
+		{Name: "noParams", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "nothingAtAll", Params: nil, Results: nil, HasContext: false},
+		{Name: "unnamedIntParam", Params: []irpcgen.ParamDesc{{Name: "p0", Type: "int"}, {Name: "p1", Type: "int"}}, Results: nil, HasContext: false},
+		{Name: "mixedParamIds", Params: []irpcgen.ParamDesc{{Name: "p02", Type: "int"}, {Name: "p0", Type: "uint8"}, {Name: "p2", Type: "struct{a int;}"}}, Results: nil, HasContext: false},
+		{Name: "underscoreParamNames", Params: []irpcgen.ParamDesc{{Name: "p02", Type: "int"}, {Name: "p0", Type: "uint8"}, {Name: "p2", Type: "float64"}}, Results: []irpcgen.ParamDesc{{Name: "_", Type: "float64"}}, HasContext: false},
+		{Name: "underscoreRtnName", Params: []irpcgen.ParamDesc{{Name: "p0", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "_", Type: "int"}, {Name: "_", Type: "uint8"}}, HasContext: false},
+		{Name: "paramNamedAsReceiver", Params: []irpcgen.ParamDesc{{Name: "_c", Type: "int"}}, Results: nil, HasContext: false},
+	},
+}
 
 // edgeCasesIrpcService provides [edgeCases] interface over irpc
 type edgeCasesIrpcService struct {
@@ -66,6 +94,11 @@ func (s *edgeCasesIrpcService) Id() irpcgen.ServiceId {
 	return _edgeCasesIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *edgeCasesIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_edgeCasesIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *edgeCasesIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -163,7 +196,7 @@ type edgeCasesIrpcClient struct {
 }
 
 func newEdgeCasesIrpcClient(endpoint irpcgen.Endpoint) (*edgeCasesIrpcClient, error) {
-	if err := endpoint.RegisterClient(_edgeCasesIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_edgeCasesIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &edgeCasesIrpcClient{endpoint: endpoint}, nil
@@ -462,7 +495,16 @@ func (s *_irpc_edgeCases_paramNamedAsReceiverReq) Deserialize(d *irpcgen.Decoder
 	return nil
 }
 
-var _anotherInterfaceIrpcId = irpcgen.ServiceId(0xd93f207d28173b88)
+var _anotherInterfaceIrpcId = irpcgen.ServiceId(0x2acac2e982c24972)
+
+var _anotherInterfaceIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _anotherInterfaceIrpcId,
+	Name:      "anotherInterface",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.anotherInterface",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "anotherAdd", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+	},
+}
 
 // anotherInterfaceIrpcService provides [anotherInterface] interface over irpc
 type anotherInterfaceIrpcService struct {
@@ -481,6 +523,11 @@ func (s *anotherInterfaceIrpcService) Id() irpcgen.ServiceId {
 	return _anotherInterfaceIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *anotherInterfaceIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_anotherInterfaceIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *anotherInterfaceIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -507,7 +554,7 @@ type anotherInterfaceIrpcClient struct {
 }
 
 func newAnotherInterfaceIrpcClient(endpoint irpcgen.Endpoint) (*anotherInterfaceIrpcClient, error) {
-	if err := endpoint.RegisterClient(_anotherInterfaceIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_anotherInterfaceIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &anotherInterfaceIrpcClient{endpoint: endpoint}, nil
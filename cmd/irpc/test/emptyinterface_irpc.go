@@ -7,7 +7,14 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _emptyInterfaceIrpcId = irpcgen.ServiceId(0x89a0aa875ef6bd1b)
+var _emptyInterfaceIrpcId = irpcgen.ServiceId(0xbacb0397a7278aff)
+
+var _emptyInterfaceIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _emptyInterfaceIrpcId,
+	Name:      "emptyInterface",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.emptyInterface",
+	Methods:   []irpcgen.MethodDesc{},
+}
 
 // emptyInterfaceIrpcService provides [emptyInterface] interface over irpc
 type emptyInterfaceIrpcService struct {
@@ -26,6 +33,11 @@ func (s *emptyInterfaceIrpcService) Id() irpcgen.ServiceId {
 	return _emptyInterfaceIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *emptyInterfaceIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_emptyInterfaceIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *emptyInterfaceIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -40,7 +52,7 @@ type emptyInterfaceIrpcClient struct {
 }
 
 func newEmptyInterfaceIrpcClient(endpoint irpcgen.Endpoint) (*emptyInterfaceIrpcClient, error) {
-	if err := endpoint.RegisterClient(_emptyInterfaceIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_emptyInterfaceIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &emptyInterfaceIrpcClient{endpoint: endpoint}, nil
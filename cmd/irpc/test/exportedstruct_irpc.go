@@ -8,7 +8,16 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _FileServerIrpcId = irpcgen.ServiceId(0x27a8e417553cee1c)
+var _FileServerIrpcId = irpcgen.ServiceId(0x3506097025a8f19e)
+
+var _FileServerIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _FileServerIrpcId,
+	Name:      "FileServer",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.FileServer",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "ListFiles", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "[]FileInfo"}, {Name: "", Type: "error"}}, HasContext: false},
+	},
+}
 
 // FileServerIrpcService provides [FileServer] interface over irpc
 type FileServerIrpcService struct {
@@ -27,6 +36,11 @@ func (s *FileServerIrpcService) Id() irpcgen.ServiceId {
 	return _FileServerIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *FileServerIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_FileServerIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *FileServerIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -49,7 +63,7 @@ type FileServerIrpcClient struct {
 }
 
 func NewFileServerIrpcClient(endpoint irpcgen.Endpoint) (*FileServerIrpcClient, error) {
-	if err := endpoint.RegisterClient(_FileServerIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_FileServerIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &FileServerIrpcClient{endpoint: endpoint}, nil
@@ -83,20 +97,7 @@ func (s _irpc_FileServer_ListFilesResp) Serialize(e *irpcgen.Encoder) error {
 	}(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type []FileInfo: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -112,30 +113,11 @@ func (s *_irpc_FileServer_ListFilesResp) Deserialize(d *irpcgen.Decoder) error {
 	}(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type []FileInfo: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_FileServer_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_FileServer_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_FileServer_impl) Error() string {
-	return i._Error_0_
+func (s _irpc_FileServer_ListFilesResp) ResultError() error {
+	return s.p1
 }
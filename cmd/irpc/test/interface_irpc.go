@@ -10,7 +10,23 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _interfaceTestIrpcId = irpcgen.ServiceId(0xe1d4feea546fa811)
+var _interfaceTestIrpcId = irpcgen.ServiceId(0xfd94f1e6d1258895)
+
+var _interfaceTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _interfaceTestIrpcId,
+	Name:      "interfaceTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.interfaceTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "rtnErrorWithMessage", Params: []irpcgen.ParamDesc{{Name: "msg", Type: "string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "error"}}, HasContext: false},
+		{Name: "rtnNilError", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "error"}}, HasContext: false},
+		{Name: "rtnTwoErrors", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "error"}, {Name: "", Type: "error"}}, HasContext: false},
+		{Name: "rtnStringAndError", Params: []irpcgen.ParamDesc{{Name: "msg", Type: "string"}}, Results: []irpcgen.ParamDesc{{Name: "s", Type: "string"}, {Name: "err", Type: "error"}}, HasContext: false},
+		{Name: "passCustomInterfaceAndReturnItModified", Params: []irpcgen.ParamDesc{{Name: "ci", Type: "customInterface"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "customInterface"}, {Name: "", Type: "error"}}, HasContext: false},
+		{Name: "passJustCustomInterfaceWithoutError", Params: []irpcgen.ParamDesc{{Name: "ci", Type: "customInterface"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "customInterface"}}, HasContext: false},
+		{Name: "passAnonInterface", Params: []irpcgen.ParamDesc{{Name: "input", Type: "interface{Age() ( int);Name() ( string);}"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}}, HasContext: false},
+		{Name: "passAnonInterfaceWithNamedParams", Params: []irpcgen.ParamDesc{{Name: "input", Type: "interface{a() ( out.Uint8, int);b() ( out2.Uint8);c() ( out2.Uint8, error);}"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}}, HasContext: false},
+	},
+}
 
 // interfaceTestIrpcService provides [interfaceTest] interface over irpc
 type interfaceTestIrpcService struct {
@@ -29,6 +45,11 @@ func (s *interfaceTestIrpcService) Id() irpcgen.ServiceId {
 	return _interfaceTestIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *interfaceTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_interfaceTestIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *interfaceTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -131,7 +152,7 @@ type interfaceTestIrpcClient struct {
 }
 
 func newInterfaceTestIrpcClient(endpoint irpcgen.Endpoint) (*interfaceTestIrpcClient, error) {
-	if err := endpoint.RegisterClient(_interfaceTestIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_interfaceTestIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &interfaceTestIrpcClient{endpoint: endpoint}, nil
@@ -262,51 +283,19 @@ type _irpc_interfaceTest_rtnErrorWithMessageResp struct {
 }
 
 func (s _irpc_interfaceTest_rtnErrorWithMessageResp) Serialize(e *irpcgen.Encoder) error {
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p0); err != nil {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
 }
 func (s *_irpc_interfaceTest_rtnErrorWithMessageResp) Deserialize(d *irpcgen.Decoder) error {
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_interfaceTest_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p0); err != nil {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_interfaceTest_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_interfaceTest_impl) Error() string {
-	return i._Error_0_
+func (s _irpc_interfaceTest_rtnErrorWithMessageResp) ResultError() error {
+	return s.p0
 }
 
 type _irpc_interfaceTest_rtnNilErrorResp struct {
@@ -314,44 +303,20 @@ type _irpc_interfaceTest_rtnNilErrorResp struct {
 }
 
 func (s _irpc_interfaceTest_rtnNilErrorResp) Serialize(e *irpcgen.Encoder) error {
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p0); err != nil {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
 }
 func (s *_irpc_interfaceTest_rtnNilErrorResp) Deserialize(d *irpcgen.Decoder) error {
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_interfaceTest_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p0); err != nil {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_interfaceTest_rtnNilErrorResp) ResultError() error {
+	return s.p0
+}
 
 type _irpc_interfaceTest_rtnTwoErrorsResp struct {
 	p0 error
@@ -359,77 +324,26 @@ type _irpc_interfaceTest_rtnTwoErrorsResp struct {
 }
 
 func (s _irpc_interfaceTest_rtnTwoErrorsResp) Serialize(e *irpcgen.Encoder) error {
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p0); err != nil {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
 }
 func (s *_irpc_interfaceTest_rtnTwoErrorsResp) Deserialize(d *irpcgen.Decoder) error {
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_interfaceTest_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p0); err != nil {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_interfaceTest_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_interfaceTest_rtnTwoErrorsResp) ResultError() error {
+	return s.p1
+}
 
 type _irpc_interfaceTest_rtnStringAndErrorReq struct {
 	msg string
@@ -457,20 +371,7 @@ func (s _irpc_interfaceTest_rtnStringAndErrorResp) Serialize(e *irpcgen.Encoder)
 	if err := irpcgen.EncString(e, s.s); err != nil {
 		return fmt.Errorf("serialize \"s\" of type string: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.err); err != nil {
+	if err := irpcgen.EncError(e, s.err); err != nil {
 		return fmt.Errorf("serialize \"err\" of type error: %w", err)
 	}
 	return nil
@@ -479,25 +380,14 @@ func (s *_irpc_interfaceTest_rtnStringAndErrorResp) Deserialize(d *irpcgen.Decod
 	if err := irpcgen.DecString(d, &s.s); err != nil {
 		return fmt.Errorf("deserialize s of type string: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_interfaceTest_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.err); err != nil {
+	if err := irpcgen.DecError(d, &s.err); err != nil {
 		return fmt.Errorf("deserialize err of type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_interfaceTest_rtnStringAndErrorResp) ResultError() error {
+	return s.err
+}
 
 type _irpc_interfaceTest_passCustomInterfaceAndReturnItModifiedReq struct {
 	ci customInterface
@@ -588,20 +478,7 @@ func (s _irpc_interfaceTest_passCustomInterfaceAndReturnItModifiedResp) Serializ
 	}(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type customInterface: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -627,25 +504,14 @@ func (s *_irpc_interfaceTest_passCustomInterfaceAndReturnItModifiedResp) Deseria
 	}(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type customInterface: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_interfaceTest_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_interfaceTest_passCustomInterfaceAndReturnItModifiedResp) ResultError() error {
+	return s.p1
+}
 
 type _irpc_interfaceTest_passJustCustomInterfaceWithoutErrorReq struct {
 	ci customInterface
@@ -845,20 +711,7 @@ func (s _irpc_interfaceTest_passAnonInterfaceWithNamedParamsReq) Serialize(e *ir
 		return fmt.Errorf("serialize \"v.b()\" of type out2.Uint8: %w", err)
 	}; _c_0_, _c_1_ := v.c(); if err := irpcgen.EncUint8(enc, _c_0_); err != nil {
 		return fmt.Errorf("serialize \"v.c()\" of type out2.Uint8: %w", err)
-	}; if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(enc, _c_1_); err != nil {
+	}; if err := irpcgen.EncError(enc, _c_1_); err != nil {
 		return fmt.Errorf("serialize \"v.c()\" of type error: %w", err)
 	}; return nil }(e, s.input); err != nil {
 		return fmt.Errorf("serialize \"input\" of type interface{a() ( out.Uint8, int);b() ( out2.Uint8);c() ( out2.Uint8, error);}: %w", err)
@@ -882,21 +735,7 @@ func (s *_irpc_interfaceTest_passAnonInterfaceWithNamedParamsReq) Deserialize(d
 		return fmt.Errorf("deserialize \"_b_0_\" out2.Uint8: %w", err)
 	}; if err := irpcgen.DecUint8(dec, &impl._c_0_); err != nil {
 		return fmt.Errorf("deserialize \"_c_0_\" out2.Uint8: %w", err)
-	}; if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_interfaceTest_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(dec, &impl._c_1_); err != nil {
+	}; if err := irpcgen.DecError(dec, &impl._c_1_); err != nil {
 		return fmt.Errorf("deserialize \"_c_1_\" error: %w", err)
 	}; *s = impl; return nil }(d, &s.input); err != nil {
 		return fmt.Errorf("deserialize input of type interface{a() ( out.Uint8, int);b() ( out2.Uint8);c() ( out2.Uint8, error);}: %w", err)
@@ -939,7 +778,17 @@ func (s *_irpc_interfaceTest_passAnonInterfaceWithNamedParamsResp) Deserialize(d
 	return nil
 }
 
-var _customInterfaceIrpcId = irpcgen.ServiceId(0xd3efc7e5eee316f7)
+var _customInterfaceIrpcId = irpcgen.ServiceId(0x4e3be294e6295f70)
+
+var _customInterfaceIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _customInterfaceIrpcId,
+	Name:      "customInterface",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.customInterface",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "IntFunc", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "StringFunc", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}}, HasContext: false},
+	},
+}
 
 // customInterfaceIrpcService provides [customInterface] interface over irpc
 type customInterfaceIrpcService struct {
@@ -958,6 +807,11 @@ func (s *customInterfaceIrpcService) Id() irpcgen.ServiceId {
 	return _customInterfaceIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *customInterfaceIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_customInterfaceIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *customInterfaceIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -990,7 +844,7 @@ type customInterfaceIrpcClient struct {
 }
 
 func newCustomInterfaceIrpcClient(endpoint irpcgen.Endpoint) (*customInterfaceIrpcClient, error) {
-	if err := endpoint.RegisterClient(_customInterfaceIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_customInterfaceIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &customInterfaceIrpcClient{endpoint: endpoint}, nil
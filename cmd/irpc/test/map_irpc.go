@@ -9,7 +9,23 @@ import (
 	"time"
 )
 
-var _mapTestIrpcId = irpcgen.ServiceId(0x1b5f2b54a0eb63f9)
+var _mapTestIrpcId = irpcgen.ServiceId(0x272513ee4065e6c0)
+
+var _mapTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _mapTestIrpcId,
+	Name:      "mapTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.mapTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "mapSum", Params: []irpcgen.ParamDesc{{Name: "in", Type: "map[int]float64"}}, Results: []irpcgen.ParamDesc{{Name: "keysSum", Type: "int"}, {Name: "valsSum", Type: "float64"}}, HasContext: false},
+		{Name: "sumStructs", Params: []irpcgen.ParamDesc{{Name: "in", Type: "map[intStruct]intStruct"}}, Results: []irpcgen.ParamDesc{{Name: "keysSum", Type: "int"}, {Name: "valsSum", Type: "int"}}, HasContext: false},
+		{Name: "sumSlices", Params: []irpcgen.ParamDesc{{Name: "in", Type: "map[intStruct][]intStruct"}}, Results: []irpcgen.ParamDesc{{Name: "keysSum", Type: "int"}, {Name: "valsSum", Type: "int"}}, HasContext: false},
+		{Name: "namedMapInc", Params: []irpcgen.ParamDesc{{Name: "in", Type: "namedIntFloatMap"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "namedIntFloatMap"}}, HasContext: false},
+		{Name: "namedKeySum", Params: []irpcgen.ParamDesc{{Name: "in", Type: "map[mapNamedInt]mapNamedFloat64"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "mapNamedFloat64"}}, HasContext: false},
+		{Name: "emptyInterfaceMapReflect", Params: []irpcgen.ParamDesc{{Name: "in", Type: "map[int]interface{}"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "map[int]interface{}"}}, HasContext: false},
+		{Name: "isNil", Params: []irpcgen.ParamDesc{{Name: "p0", Type: "map[int]string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "mapWithTime", Params: []irpcgen.ParamDesc{{Name: "p0", Type: "map[time.Time]struct{}"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[]time.Time"}}, HasContext: false},
+	},
+}
 
 // mapTestIrpcService provides [mapTest] interface over irpc
 type mapTestIrpcService struct {
@@ -28,6 +44,11 @@ func (s *mapTestIrpcService) Id() irpcgen.ServiceId {
 	return _mapTestIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *mapTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_mapTestIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *mapTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -138,7 +159,7 @@ type mapTestIrpcClient struct {
 }
 
 func newMapTestIrpcClient(endpoint irpcgen.Endpoint) (*mapTestIrpcClient, error) {
-	if err := endpoint.RegisterClient(_mapTestIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_mapTestIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &mapTestIrpcClient{endpoint: endpoint}, nil
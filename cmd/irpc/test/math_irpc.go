@@ -8,7 +8,16 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _MathIrpcId = irpcgen.ServiceId(0xdfcd821f503023fa)
+var _MathIrpcId = irpcgen.ServiceId(0xd13f960a9dd60fc2)
+
+var _MathIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _MathIrpcId,
+	Name:      "Math",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.Math",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "Add", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}, {Name: "", Type: "error"}}, HasContext: false},
+	},
+}
 
 // MathIrpcService provides [Math] interface over irpc
 type MathIrpcService struct {
@@ -27,6 +36,11 @@ func (s *MathIrpcService) Id() irpcgen.ServiceId {
 	return _MathIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *MathIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_MathIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *MathIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -53,7 +67,7 @@ type MathIrpcClient struct {
 }
 
 func NewMathIrpcClient(endpoint irpcgen.Endpoint) (*MathIrpcClient, error) {
-	if err := endpoint.RegisterClient(_MathIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_MathIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &MathIrpcClient{endpoint: endpoint}, nil
@@ -108,20 +122,7 @@ func (s _irpc_Math_AddResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncInt(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type int: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -130,30 +131,11 @@ func (s *_irpc_Math_AddResp) Deserialize(d *irpcgen.Decoder) error {
 	if err := irpcgen.DecInt(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type int: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_Math_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_Math_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_Math_impl) Error() string {
-	return i._Error_0_
+func (s _irpc_Math_AddResp) ResultError() error {
+	return s.p1
 }
@@ -8,7 +8,21 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _namedTestIrpcId = irpcgen.ServiceId(0xd318fc983ebd408a)
+var _namedTestIrpcId = irpcgen.ServiceId(0xf4fea27308142ed5)
+
+var _namedTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _namedTestIrpcId,
+	Name:      "namedTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.namedTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "isWeekend", Params: []irpcgen.ParamDesc{{Name: "wd", Type: "weekDay"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "isWeekend2", Params: []irpcgen.ParamDesc{{Name: "wd", Type: "weekDay2"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "containsSaturday", Params: []irpcgen.ParamDesc{{Name: "wds", Type: "[]weekDay"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "containsSaturday2", Params: []irpcgen.ParamDesc{{Name: "wds", Type: "namedWeekDaysSliceType"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "namedBytesSum", Params: []irpcgen.ParamDesc{{Name: "nb", Type: "namedByteSliceType"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "namedMapSum", Params: []irpcgen.ParamDesc{{Name: "p0", Type: "namedMap"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "float64"}}, HasContext: false},
+	},
+}
 
 // namedTestIrpcService provides [namedTest] interface over irpc
 type namedTestIrpcService struct {
@@ -27,6 +41,11 @@ func (s *namedTestIrpcService) Id() irpcgen.ServiceId {
 	return _namedTestIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *namedTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_namedTestIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *namedTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -113,7 +132,7 @@ type namedTestIrpcClient struct {
 }
 
 func newNamedTestIrpcClient(endpoint irpcgen.Endpoint) (*namedTestIrpcClient, error) {
-	if err := endpoint.RegisterClient(_namedTestIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_namedTestIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &namedTestIrpcClient{endpoint: endpoint}, nil
@@ -9,7 +9,16 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _outsideTestIrpcId = irpcgen.ServiceId(0xd7aa718a007cdb31)
+var _outsideTestIrpcId = irpcgen.ServiceId(0x1c4879f75dba59f5)
+
+var _outsideTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _outsideTestIrpcId,
+	Name:      "outsideTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.outsideTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "addUint8", Params: []irpcgen.ParamDesc{{Name: "a", Type: "out.Uint8"}, {Name: "b", Type: "out.Uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "out.Uint8"}}, HasContext: false},
+	},
+}
 
 // outsideTestIrpcService provides [outsideTest] interface over irpc
 type outsideTestIrpcService struct {
@@ -28,6 +37,11 @@ func (s *outsideTestIrpcService) Id() irpcgen.ServiceId {
 	return _outsideTestIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *outsideTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_outsideTestIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *outsideTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -54,7 +68,7 @@ type outsideTestIrpcClient struct {
 }
 
 func newOutsideTestIrpcClient(endpoint irpcgen.Endpoint) (*outsideTestIrpcClient, error) {
-	if err := endpoint.RegisterClient(_outsideTestIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_outsideTestIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &outsideTestIrpcClient{endpoint: endpoint}, nil
@@ -11,7 +11,19 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _outsidepkgaliasIrpcId = irpcgen.ServiceId(0x2289a9bc0737681c)
+var _outsidepkgaliasIrpcId = irpcgen.ServiceId(0xc4e06e4a806800ab)
+
+var _outsidepkgaliasIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _outsidepkgaliasIrpcId,
+	Name:      "outsidepkgalias",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.outsidepkgalias",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "add", Params: []irpcgen.ParamDesc{{Name: "a", Type: "out1.Uint8"}, {Name: "b", Type: "out1.Uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "add2", Params: []irpcgen.ParamDesc{{Name: "a", Type: "out1.Uint8"}, {Name: "b", Type: "out.Uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "add3", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}, {Name: "b", Type: "out.Uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "out2.Uint8"}}, HasContext: false},
+		{Name: "sum", Params: []irpcgen.ParamDesc{{Name: "inSlice", Type: "out1.AliasedByteSlice"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+	},
+}
 
 // outsidepkgaliasIrpcService provides [outsidepkgalias] interface over irpc
 type outsidepkgaliasIrpcService struct {
@@ -30,6 +42,11 @@ func (s *outsidepkgaliasIrpcService) Id() irpcgen.ServiceId {
 	return _outsidepkgaliasIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *outsidepkgaliasIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_outsidepkgaliasIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *outsidepkgaliasIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -92,7 +109,7 @@ type outsidepkgaliasIrpcClient struct {
 }
 
 func newOutsidepkgaliasIrpcClient(endpoint irpcgen.Endpoint) (*outsidepkgaliasIrpcClient, error) {
-	if err := endpoint.RegisterClient(_outsidepkgaliasIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_outsidepkgaliasIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &outsidepkgaliasIrpcClient{endpoint: endpoint}, nil
@@ -0,0 +1,94 @@
+package irpctestpkg
+
+import (
+	"context"
+	"iter"
+	"strings"
+)
+
+//go:generate go run ../
+
+type paramStreamTest interface {
+	Sum(ctx context.Context, nums iter.Seq[int]) (int, error)
+	Join(sep string, words iter.Seq[string]) string
+	ChanSum(ctx context.Context, nums <-chan int) int
+	TakeTwo(ctx context.Context, nums iter.Seq[int]) []int
+	Ignore(nums iter.Seq[int]) bool
+	Double(ctx context.Context, nums iter.Seq[int]) iter.Seq2[int, error]
+	ChanEcho(ctx context.Context, in <-chan string) <-chan string
+}
+
+var _ paramStreamTest = paramStreamTestImpl{}
+
+type paramStreamTestImpl struct{}
+
+// Sum implements paramStreamTest.
+func (paramStreamTestImpl) Sum(ctx context.Context, nums iter.Seq[int]) (int, error) {
+	sum := 0
+	for n := range nums {
+		sum += n
+	}
+	return sum, ctx.Err()
+}
+
+// Join implements paramStreamTest.
+func (paramStreamTestImpl) Join(sep string, words iter.Seq[string]) string {
+	var ws []string
+	for w := range words {
+		ws = append(ws, w)
+	}
+	return strings.Join(ws, sep)
+}
+
+// ChanSum implements paramStreamTest.
+func (paramStreamTestImpl) ChanSum(ctx context.Context, nums <-chan int) int {
+	sum := 0
+	for n := range nums {
+		sum += n
+	}
+	return sum
+}
+
+// TakeTwo implements paramStreamTest.
+func (paramStreamTestImpl) TakeTwo(ctx context.Context, nums iter.Seq[int]) []int {
+	var res []int
+	for n := range nums {
+		res = append(res, n)
+		if len(res) == 2 {
+			break
+		}
+	}
+	return res
+}
+
+// Ignore implements paramStreamTest.
+func (paramStreamTestImpl) Ignore(nums iter.Seq[int]) bool {
+	return true
+}
+
+// Double implements paramStreamTest.
+func (paramStreamTestImpl) Double(ctx context.Context, nums iter.Seq[int]) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for n := range nums {
+			if !yield(2*n, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ChanEcho implements paramStreamTest.
+func (paramStreamTestImpl) ChanEcho(ctx context.Context, in <-chan string) <-chan string {
+	out := make(chan string)
+	go func() {
+		defer close(out)
+		for s := range in {
+			select {
+			case out <- s:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return out
+}
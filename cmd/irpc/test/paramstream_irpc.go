@@ -0,0 +1,669 @@
+// Code generated by irpc (devel); DO NOT EDIT
+// Source: github.com/marben/irpc/cmd/irpc/test/paramstream.go
+package irpctestpkg
+
+import (
+	"context"
+	"fmt"
+	"github.com/marben/irpc/irpcgen"
+	"iter"
+)
+
+var _paramStreamTestIrpcId = irpcgen.ServiceId(0xd868a2f822f74a57)
+
+var _paramStreamTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _paramStreamTestIrpcId,
+	Name:      "paramStreamTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.paramStreamTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "Sum", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "nums", Type: "iter.Seq[int]"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}, {Name: "", Type: "error"}}, HasContext: true},
+		{Name: "Join", Params: []irpcgen.ParamDesc{{Name: "sep", Type: "string"}, {Name: "words", Type: "iter.Seq[string]"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}}, HasContext: false},
+		{Name: "ChanSum", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "nums", Type: "<-chan int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: true},
+		{Name: "TakeTwo", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "nums", Type: "iter.Seq[int]"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[]int"}}, HasContext: true},
+		{Name: "Ignore", Params: []irpcgen.ParamDesc{{Name: "nums", Type: "iter.Seq[int]"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "Double", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "nums", Type: "iter.Seq[int]"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "iter.Seq2[int, error]"}}, HasContext: true},
+		{Name: "ChanEcho", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "in", Type: "<-chan string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "<-chan string"}}, HasContext: true},
+	},
+}
+
+// paramStreamTestIrpcService provides [paramStreamTest] interface over irpc
+type paramStreamTestIrpcService struct {
+	impl paramStreamTest
+}
+
+// newParamStreamTestIrpcService returns new [irpcgen.Service] forwarding [paramStreamTest] network calls to impl
+func newParamStreamTestIrpcService(impl paramStreamTest) *paramStreamTestIrpcService {
+	return &paramStreamTestIrpcService{
+		impl: impl,
+	}
+}
+
+// Id implements [irpcgen.Service] interface.
+func (s *paramStreamTestIrpcService) Id() irpcgen.ServiceId {
+	return _paramStreamTestIrpcId
+}
+
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *paramStreamTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_paramStreamTestIrpcDesc
+}
+
+// GetFuncCall implements [irpcgen.Service] interface
+func (s *paramStreamTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
+	switch funcId {
+	case 0: // Sum
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_paramStreamTest_SumReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				items := irpcgen.RecvSeq(ctx, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_SumParamItem) }, func(item irpcgen.Deserializable) int { return item.(*_irpc_paramStreamTest_SumParamItem).v })
+				var resp _irpc_paramStreamTest_SumResp
+				resp.p0, resp.p1 = s.impl.Sum(ctx, items)
+				return resp
+			}, nil
+		}, nil
+	case 1: // Join
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_paramStreamTest_JoinReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				items := irpcgen.RecvSeq(ctx, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_JoinParamItem) }, func(item irpcgen.Deserializable) string { return item.(*_irpc_paramStreamTest_JoinParamItem).v })
+				var resp _irpc_paramStreamTest_JoinResp
+				resp.p0 = s.impl.Join(args.sep, items)
+				return resp
+			}, nil
+		}, nil
+	case 2: // ChanSum
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_paramStreamTest_ChanSumReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				items := irpcgen.RecvChan(ctx, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_ChanSumParamItem) }, func(item irpcgen.Deserializable) int { return item.(*_irpc_paramStreamTest_ChanSumParamItem).v })
+				var resp _irpc_paramStreamTest_ChanSumResp
+				resp.p0 = s.impl.ChanSum(ctx, items)
+				return resp
+			}, nil
+		}, nil
+	case 3: // TakeTwo
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_paramStreamTest_TakeTwoReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				items := irpcgen.RecvSeq(ctx, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_TakeTwoParamItem) }, func(item irpcgen.Deserializable) int { return item.(*_irpc_paramStreamTest_TakeTwoParamItem).v })
+				var resp _irpc_paramStreamTest_TakeTwoResp
+				resp.p0 = s.impl.TakeTwo(ctx, items)
+				return resp
+			}, nil
+		}, nil
+	case 4: // Ignore
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_paramStreamTest_IgnoreReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				items := irpcgen.RecvSeq(ctx, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_IgnoreParamItem) }, func(item irpcgen.Deserializable) int { return item.(*_irpc_paramStreamTest_IgnoreParamItem).v })
+				var resp _irpc_paramStreamTest_IgnoreResp
+				resp.p0 = s.impl.Ignore(items)
+				return resp
+			}, nil
+		}, nil
+	case 5: // Double
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_paramStreamTest_DoubleReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				items := irpcgen.RecvSeq(ctx, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_DoubleParamItem) }, func(item irpcgen.Deserializable) int { return item.(*_irpc_paramStreamTest_DoubleParamItem).v })
+				var resp _irpc_paramStreamTest_DoubleResp
+				seq := s.impl.Double(ctx, items)
+				return irpcgen.NewStreamSerializable(&resp, func(ctx context.Context, send irpcgen.ItemSender) {
+					resp.p0 = irpcgen.SendSeq2(seq, func(v int) irpcgen.Serializable { return _irpc_paramStreamTest_DoubleItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	case 6: // ChanEcho
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_paramStreamTest_ChanEchoReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				items := irpcgen.RecvChan(ctx, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_ChanEchoParamItem) }, func(item irpcgen.Deserializable) string { return item.(*_irpc_paramStreamTest_ChanEchoParamItem).v })
+				ch := s.impl.ChanEcho(ctx, items)
+				return irpcgen.NewStreamSerializable(irpcgen.EmptySerializable{}, func(ctx context.Context, send irpcgen.ItemSender) {
+					irpcgen.SendChan(ctx, ch, func(v string) irpcgen.Serializable { return _irpc_paramStreamTest_ChanEchoItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("function '%d' doesn't exist on service '%s'", funcId, s.Id())
+	}
+}
+
+// paramStreamTestIrpcClient implements [paramStreamTest] interface. It by forwards calls over network to [paramStreamTestIrpcService] that provides the implementation.
+type paramStreamTestIrpcClient struct {
+	endpoint irpcgen.Endpoint
+}
+
+func newParamStreamTestIrpcClient(endpoint irpcgen.Endpoint) (*paramStreamTestIrpcClient, error) {
+	if err := irpcgen.RegisterClient(endpoint, &_paramStreamTestIrpcDesc); err != nil {
+		return nil, fmt.Errorf("register failed: %w", err)
+	}
+	return &paramStreamTestIrpcClient{endpoint: endpoint}, nil
+}
+
+// Sum implements [paramStreamTest]
+//
+func (_c *paramStreamTestIrpcClient) Sum(ctx context.Context, nums iter.Seq[int]) (int, error) {
+	var req = _irpc_paramStreamTest_SumReq{
+		// ctx: ctx,
+		// nums: nums,
+	}
+	reqStream := irpcgen.NewStreamSerializable(req, func(ctx2 context.Context, send irpcgen.ItemSender) {
+		irpcgen.SendSeq(nums, func(v int) irpcgen.Serializable { return _irpc_paramStreamTest_SumParamItem{v: v} }, send)
+	})
+	var resp _irpc_paramStreamTest_SumResp
+	if err := _c.endpoint.CallRemoteFunc(ctx, _paramStreamTestIrpcId, 0, reqStream, &resp); err != nil {
+		var zero _irpc_paramStreamTest_SumResp
+		return zero.p0, err
+	}
+	return resp.p0, resp.p1
+}
+
+// Join implements [paramStreamTest]
+func (_c *paramStreamTestIrpcClient) Join(sep string, words iter.Seq[string]) string {
+	var req = _irpc_paramStreamTest_JoinReq{
+		sep: sep,
+		// words: words,
+	}
+	reqStream := irpcgen.NewStreamSerializable(req, func(ctx context.Context, send irpcgen.ItemSender) {
+		irpcgen.SendSeq(words, func(v string) irpcgen.Serializable { return _irpc_paramStreamTest_JoinParamItem{v: v} }, send)
+	})
+	var resp _irpc_paramStreamTest_JoinResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _paramStreamTestIrpcId, 1, reqStream, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// ChanSum implements [paramStreamTest]
+func (_c *paramStreamTestIrpcClient) ChanSum(ctx context.Context, nums <-chan int) int {
+	var req = _irpc_paramStreamTest_ChanSumReq{
+		// ctx: ctx,
+		// nums: nums,
+	}
+	reqStream := irpcgen.NewStreamSerializable(req, func(ctx2 context.Context, send irpcgen.ItemSender) {
+		irpcgen.SendChan(ctx2, nums, func(v int) irpcgen.Serializable { return _irpc_paramStreamTest_ChanSumParamItem{v: v} }, send)
+	})
+	var resp _irpc_paramStreamTest_ChanSumResp
+	if err := _c.endpoint.CallRemoteFunc(ctx, _paramStreamTestIrpcId, 2, reqStream, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// TakeTwo implements [paramStreamTest]
+func (_c *paramStreamTestIrpcClient) TakeTwo(ctx context.Context, nums iter.Seq[int]) []int {
+	var req = _irpc_paramStreamTest_TakeTwoReq{
+		// ctx: ctx,
+		// nums: nums,
+	}
+	reqStream := irpcgen.NewStreamSerializable(req, func(ctx2 context.Context, send irpcgen.ItemSender) {
+		irpcgen.SendSeq(nums, func(v int) irpcgen.Serializable { return _irpc_paramStreamTest_TakeTwoParamItem{v: v} }, send)
+	})
+	var resp _irpc_paramStreamTest_TakeTwoResp
+	if err := _c.endpoint.CallRemoteFunc(ctx, _paramStreamTestIrpcId, 3, reqStream, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// Ignore implements [paramStreamTest]
+func (_c *paramStreamTestIrpcClient) Ignore(nums iter.Seq[int]) bool {
+	var req = _irpc_paramStreamTest_IgnoreReq{
+		// nums: nums,
+	}
+	reqStream := irpcgen.NewStreamSerializable(req, func(ctx context.Context, send irpcgen.ItemSender) {
+		irpcgen.SendSeq(nums, func(v int) irpcgen.Serializable { return _irpc_paramStreamTest_IgnoreParamItem{v: v} }, send)
+	})
+	var resp _irpc_paramStreamTest_IgnoreResp
+	if err := _c.endpoint.CallRemoteFunc(context.Background(), _paramStreamTestIrpcId, 4, reqStream, &resp); err != nil {
+		panic(err) // to avoid panic, make your func return error and regenerate irpc code
+	}
+	return resp.p0
+}
+
+// Double implements [paramStreamTest]
+func (_c *paramStreamTestIrpcClient) Double(ctx context.Context, nums iter.Seq[int]) iter.Seq2[int, error] {
+	var req = _irpc_paramStreamTest_DoubleReq{
+		// ctx: ctx,
+		// nums: nums,
+	}
+	reqStream := irpcgen.NewStreamSerializable(req, func(ctx2 context.Context, send irpcgen.ItemSender) {
+		irpcgen.SendSeq(nums, func(v int) irpcgen.Serializable { return _irpc_paramStreamTest_DoubleParamItem{v: v} }, send)
+	})
+	return func(yield func(int, error) bool) {
+		var resp _irpc_paramStreamTest_DoubleResp
+		for item, err := range _c.endpoint.CallRemoteStream(ctx, _paramStreamTestIrpcId, 5, reqStream, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_DoubleItem) }, &resp) {
+			if err != nil {
+				var zero _irpc_paramStreamTest_DoubleItem
+				yield(zero.v, err)
+				return
+			}
+			if !yield(item.(*_irpc_paramStreamTest_DoubleItem).v, nil) {
+				return
+			}
+		}
+		if resp.p0 != nil {
+			var zero _irpc_paramStreamTest_DoubleItem
+			yield(zero.v, resp.p0)
+		}
+	}
+}
+
+// ChanEcho implements [paramStreamTest]
+//
+// Any error closes the returned channel early. Use [irpcgen.ContextWithStreamErrorHandler] to learn about it.
+func (_c *paramStreamTestIrpcClient) ChanEcho(ctx context.Context, in <-chan string) <-chan string {
+	var req = _irpc_paramStreamTest_ChanEchoReq{
+		// ctx: ctx,
+		// in: in,
+	}
+	reqStream := irpcgen.NewStreamSerializable(req, func(ctx2 context.Context, send irpcgen.ItemSender) {
+		irpcgen.SendChan(ctx2, in, func(v string) irpcgen.Serializable { return _irpc_paramStreamTest_ChanEchoParamItem{v: v} }, send)
+	})
+	ch := make(chan string)
+	go func() {
+		defer close(ch)
+		for item, err := range _c.endpoint.CallRemoteStream(ctx, _paramStreamTestIrpcId, 6, reqStream, func() irpcgen.Deserializable { return new(_irpc_paramStreamTest_ChanEchoItem) }, irpcgen.EmptyDeserializable{}) {
+			if err != nil {
+				irpcgen.ReportStreamError(ctx, err)
+				return
+			}
+			select {
+			case ch <- item.(*_irpc_paramStreamTest_ChanEchoItem).v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+type _irpc_paramStreamTest_SumReq struct {
+	//ctx context.Context
+	//nums iter.Seq[int]
+
+}
+
+func (s _irpc_paramStreamTest_SumReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_paramStreamTest_SumReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_paramStreamTest_SumResp struct {
+	p0 int
+	p1 error
+}
+
+func (s _irpc_paramStreamTest_SumResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type int: %w", err)
+	}
+	if err := irpcgen.EncError(e, s.p1); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_SumResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type int: %w", err)
+	}
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_paramStreamTest_SumResp) ResultError() error {
+	return s.p1
+}
+
+type _irpc_paramStreamTest_SumParamItem struct {
+	v int
+}
+
+func (s _irpc_paramStreamTest_SumParamItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_SumParamItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_JoinReq struct {
+	sep string
+	//words iter.Seq[string]
+
+}
+
+func (s _irpc_paramStreamTest_JoinReq) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncString(e, s.sep); err != nil {
+		return fmt.Errorf("serialize \"sep\" of type string: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_JoinReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecString(d, &s.sep); err != nil {
+		return fmt.Errorf("deserialize sep of type string: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_JoinResp struct {
+	p0 string
+}
+
+func (s _irpc_paramStreamTest_JoinResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncString(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type string: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_JoinResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecString(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type string: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_JoinParamItem struct {
+	v string
+}
+
+func (s _irpc_paramStreamTest_JoinParamItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncString(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type string: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_JoinParamItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecString(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type string: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_ChanSumReq struct {
+	//ctx context.Context
+	//nums <-chan int
+
+}
+
+func (s _irpc_paramStreamTest_ChanSumReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_paramStreamTest_ChanSumReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_paramStreamTest_ChanSumResp struct {
+	p0 int
+}
+
+func (s _irpc_paramStreamTest_ChanSumResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_ChanSumResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_ChanSumParamItem struct {
+	v int
+}
+
+func (s _irpc_paramStreamTest_ChanSumParamItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_ChanSumParamItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_TakeTwoReq struct {
+	//ctx context.Context
+	//nums iter.Seq[int]
+
+}
+
+func (s _irpc_paramStreamTest_TakeTwoReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_paramStreamTest_TakeTwoReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_paramStreamTest_TakeTwoResp struct {
+	p0 []int
+}
+
+func (s _irpc_paramStreamTest_TakeTwoResp) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, sl []int) error {
+		return irpcgen.EncSlice(enc, sl, "int", irpcgen.EncInt)
+	}(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type []int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_TakeTwoResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, sl *[]int) error {
+		return irpcgen.DecSlice(dec, sl, "int", irpcgen.DecInt)
+	}(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type []int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_TakeTwoParamItem struct {
+	v int
+}
+
+func (s _irpc_paramStreamTest_TakeTwoParamItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_TakeTwoParamItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_IgnoreReq struct {
+	//nums iter.Seq[int]
+
+}
+
+func (s _irpc_paramStreamTest_IgnoreReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_paramStreamTest_IgnoreReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_paramStreamTest_IgnoreResp struct {
+	p0 bool
+}
+
+func (s _irpc_paramStreamTest_IgnoreResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncBool(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type bool: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_IgnoreResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecBool(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type bool: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_IgnoreParamItem struct {
+	v int
+}
+
+func (s _irpc_paramStreamTest_IgnoreParamItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_IgnoreParamItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_DoubleReq struct {
+	//ctx context.Context
+	//nums iter.Seq[int]
+
+}
+
+func (s _irpc_paramStreamTest_DoubleReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_paramStreamTest_DoubleReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_paramStreamTest_DoubleResp struct {
+	p0 error
+}
+
+func (s _irpc_paramStreamTest_DoubleResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_DoubleResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_paramStreamTest_DoubleResp) ResultError() error {
+	return s.p0
+}
+
+type _irpc_paramStreamTest_DoubleItem struct {
+	v int
+}
+
+func (s _irpc_paramStreamTest_DoubleItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_DoubleItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_DoubleParamItem struct {
+	v int
+}
+
+func (s _irpc_paramStreamTest_DoubleParamItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_DoubleParamItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_ChanEchoReq struct {
+	//ctx context.Context
+	//in <-chan string
+
+}
+
+func (s _irpc_paramStreamTest_ChanEchoReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_paramStreamTest_ChanEchoReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_paramStreamTest_ChanEchoItem struct {
+	v string
+}
+
+func (s _irpc_paramStreamTest_ChanEchoItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncString(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type string: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_ChanEchoItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecString(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type string: %w", err)
+	}
+	return nil
+}
+
+type _irpc_paramStreamTest_ChanEchoParamItem struct {
+	v string
+}
+
+func (s _irpc_paramStreamTest_ChanEchoParamItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncString(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type string: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_paramStreamTest_ChanEchoParamItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecString(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type string: %w", err)
+	}
+	return nil
+}
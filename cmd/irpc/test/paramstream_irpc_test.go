@@ -0,0 +1,184 @@
+package irpctestpkg
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/marben/irpc"
+	"github.com/marben/irpc/cmd/irpc/test/testtools"
+)
+
+func newParamStreamTestClient(t *testing.T, opts ...irpc.EndpointOption) *paramStreamTestIrpcClient {
+	t.Helper()
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints(opts...)
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	t.Cleanup(func() {
+		localEp.Close()
+		remoteEp.Close()
+	})
+
+	remoteEp.RegisterService(newParamStreamTestIrpcService(paramStreamTestImpl{}))
+	c, err := newParamStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+	return c
+}
+
+func TestParamStream(t *testing.T) {
+	c := newParamStreamTestClient(t, irpc.WithStreamWindow(4))
+
+	// more items than is the stream window
+	sum, err := c.Sum(context.Background(), func(yield func(int) bool) {
+		for i := range 100 {
+			if !yield(i) {
+				return
+			}
+		}
+	})
+	if err != nil {
+		t.Fatalf("Sum(): %v", err)
+	}
+	if sum != 4950 {
+		t.Fatalf("Sum(): %d != 4950", sum)
+	}
+
+	// empty stream
+	sum, err = c.Sum(context.Background(), slices.Values([]int{}))
+	if err != nil || sum != 0 {
+		t.Fatalf("Sum(empty): %d, %v", sum, err)
+	}
+
+	// nil stream
+	sum, err = c.Sum(context.Background(), nil)
+	if err != nil || sum != 0 {
+		t.Fatalf("Sum(nil): %d, %v", sum, err)
+	}
+
+	if s := c.Join("-", slices.Values([]string{"a", "b", "c"})); s != "a-b-c" {
+		t.Fatalf("Join(): %q", s)
+	}
+
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := range 10 {
+			ch <- i
+		}
+	}()
+	if sum := c.ChanSum(context.Background(), ch); sum != 45 {
+		t.Fatalf("ChanSum(): %d != 45", sum)
+	}
+}
+
+func TestParamStreamPartialConsumption(t *testing.T) {
+	c := newParamStreamTestClient(t, irpc.WithStreamWindow(2))
+
+	produced := 0
+	endless := func(yield func(int) bool) {
+		for i := 0; ; i++ {
+			produced++
+			if !yield(i) {
+				return
+			}
+		}
+	}
+
+	// service stops reading after two items. the call must still end and stop our producer
+	if res := c.TakeTwo(context.Background(), endless); !slices.Equal(res, []int{0, 1}) {
+		t.Fatalf("TakeTwo(): %v", res)
+	}
+
+	// service doesn't read the stream at all
+	if !c.Ignore(endless) {
+		t.Fatalf("Ignore() returned false")
+	}
+
+	// endpoint must still be usable. request numbers were released
+	for range 10 {
+		if sum, err := c.Sum(context.Background(), slices.Values([]int{1, 2})); err != nil || sum != 3 {
+			t.Fatalf("Sum(): %d, %v", sum, err)
+		}
+	}
+}
+
+func TestParamStreamContextCancel(t *testing.T) {
+	c := newParamStreamTestClient(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	// we cancel the call while the service is still waiting for items
+	_, err := c.Sum(ctx, func(yield func(int) bool) {
+		yield(1)
+		cancel()
+		<-ctx.Done()
+	})
+	if err == nil {
+		t.Fatalf("Sum() with canceled context succeeded")
+	}
+}
+
+func TestBidiStream(t *testing.T) {
+	c := newParamStreamTestClient(t, irpc.WithStreamWindow(3))
+
+	var got []int
+	for v, err := range c.Double(context.Background(), slices.Values([]int{1, 2, 3, 4, 5, 6, 7})) {
+		if err != nil {
+			t.Fatalf("Double(): %v", err)
+		}
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{2, 4, 6, 8, 10, 12, 14}) {
+		t.Fatalf("Double(): %v", got)
+	}
+
+	// ping-pong: each item is sent only after the previous one came back
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	in := make(chan string, 1)
+	out := c.ChanEcho(ctx, in)
+	for _, s := range []string{"a", "b", "c"} {
+		in <- s
+		if echo := <-out; echo != s {
+			t.Fatalf("ChanEcho(): %q != %q", echo, s)
+		}
+	}
+	close(in)
+	if _, ok := <-out; ok {
+		t.Fatalf("ChanEcho(): channel not closed after input was closed")
+	}
+}
+
+func TestParamStreamUnregisteredService(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints(irpc.WithStreamWindow(2))
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	c, err := newParamStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	// the peer ends the streamed parameter, even though the service doesn't exist
+	if _, err := c.Sum(context.Background(), slices.Values([]int{1, 2, 3, 4, 5})); !errors.Is(err, irpc.ErrServiceNotFound) {
+		t.Fatalf("Sum(): expected ErrServiceNotFound, got: %v", err)
+	}
+	for _, err := range c.Double(context.Background(), slices.Values([]int{1})) {
+		if !errors.Is(err, irpc.ErrServiceNotFound) {
+			t.Fatalf("Double(): expected ErrServiceNotFound, got: %v", err)
+		}
+	}
+
+	remoteEp.RegisterService(newParamStreamTestIrpcService(paramStreamTestImpl{}))
+	if sum, err := c.Sum(context.Background(), slices.Values([]int{1, 2, 3})); err != nil || sum != 6 {
+		t.Fatalf("Sum(): %d, %v", sum, err)
+	}
+}
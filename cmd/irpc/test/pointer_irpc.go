@@ -9,7 +9,18 @@ import (
 	"image"
 )
 
-var _pointerTestIrpcId = irpcgen.ServiceId(0x8e026b5a67970d8f)
+var _pointerTestIrpcId = irpcgen.ServiceId(0x0ba8cd944773a747)
+
+var _pointerTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _pointerTestIrpcId,
+	Name:      "pointerTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.pointerTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "getImage", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "*image.RGBA"}}, HasContext: false},
+		{Name: "getNilImage", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "*image.RGBA"}}, HasContext: false},
+		{Name: "isNil", Params: []irpcgen.ParamDesc{{Name: "ptr", Type: "*int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+	},
+}
 
 // pointerTestIrpcService provides [pointerTest] interface over irpc
 type pointerTestIrpcService struct {
@@ -28,6 +39,11 @@ func (s *pointerTestIrpcService) Id() irpcgen.ServiceId {
 	return _pointerTestIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *pointerTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_pointerTestIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *pointerTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -70,7 +86,7 @@ type pointerTestIrpcClient struct {
 }
 
 func newPointerTestIrpcClient(endpoint irpcgen.Endpoint) (*pointerTestIrpcClient, error) {
-	if err := endpoint.RegisterClient(_pointerTestIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_pointerTestIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &pointerTestIrpcClient{endpoint: endpoint}, nil
@@ -0,0 +1,61 @@
+package irpctestpkg
+
+import (
+	"context"
+	"sync"
+)
+
+//go:generate go run ../
+
+type retryTest interface {
+	// Get returns the counter
+	//
+	//irpc:idempotent
+	Get(ctx context.Context) (int, error)
+	// Incr increments the counter
+	Incr(ctx context.Context) (int, error)
+}
+
+var _ retryTest = &retryTestImpl{}
+
+type retryTestImpl struct {
+	// called at the start of every call. it can fail the call by breaking the connection
+	onCall func(ctx context.Context)
+
+	m       sync.Mutex
+	counter int
+	calls   int
+}
+
+// Get implements retryTest.
+func (r *retryTestImpl) Get(ctx context.Context) (int, error) {
+	r.call(ctx)
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.counter, nil
+}
+
+// Incr implements retryTest.
+func (r *retryTestImpl) Incr(ctx context.Context) (int, error) {
+	r.call(ctx)
+	r.m.Lock()
+	defer r.m.Unlock()
+	r.counter++
+	return r.counter, nil
+}
+
+func (r *retryTestImpl) call(ctx context.Context) {
+	r.m.Lock()
+	r.calls++
+	r.m.Unlock()
+	if r.onCall != nil {
+		r.onCall(ctx)
+	}
+}
+
+// numCalls returns the number of calls made so far
+func (r *retryTestImpl) numCalls() int {
+	r.m.Lock()
+	defer r.m.Unlock()
+	return r.calls
+}
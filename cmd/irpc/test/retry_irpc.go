@@ -0,0 +1,195 @@
+// Code generated by irpc (devel); DO NOT EDIT
+// Source: github.com/marben/irpc/cmd/irpc/test/retry.go
+package irpctestpkg
+
+import (
+	"context"
+	"fmt"
+	"github.com/marben/irpc/irpcgen"
+)
+
+var _retryTestIrpcId = irpcgen.ServiceId(0xe045e5c64259e7d1)
+
+var _retryTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _retryTestIrpcId,
+	Name:      "retryTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.retryTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "Get", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}, {Name: "", Type: "error"}}, HasContext: true, Idempotent: true},
+		{Name: "Incr", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}, {Name: "", Type: "error"}}, HasContext: true},
+	},
+}
+
+// retryTestIrpcService provides [retryTest] interface over irpc
+type retryTestIrpcService struct {
+	impl retryTest
+}
+
+// newRetryTestIrpcService returns new [irpcgen.Service] forwarding [retryTest] network calls to impl
+func newRetryTestIrpcService(impl retryTest) *retryTestIrpcService {
+	return &retryTestIrpcService{
+		impl: impl,
+	}
+}
+
+// Id implements [irpcgen.Service] interface.
+func (s *retryTestIrpcService) Id() irpcgen.ServiceId {
+	return _retryTestIrpcId
+}
+
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *retryTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_retryTestIrpcDesc
+}
+
+// GetFuncCall implements [irpcgen.Service] interface
+func (s *retryTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
+	switch funcId {
+	case 0: // Get
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_retryTest_GetReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_retryTest_GetResp
+				resp.p0, resp.p1 = s.impl.Get(ctx)
+				return resp
+			}, nil
+		}, nil
+	case 1: // Incr
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_retryTest_IncrReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_retryTest_IncrResp
+				resp.p0, resp.p1 = s.impl.Incr(ctx)
+				return resp
+			}, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("function '%d' doesn't exist on service '%s'", funcId, s.Id())
+	}
+}
+
+// retryTestIrpcClient implements [retryTest] interface. It by forwards calls over network to [retryTestIrpcService] that provides the implementation.
+type retryTestIrpcClient struct {
+	endpoint irpcgen.Endpoint
+}
+
+func newRetryTestIrpcClient(endpoint irpcgen.Endpoint) (*retryTestIrpcClient, error) {
+	if err := irpcgen.RegisterClient(endpoint, &_retryTestIrpcDesc); err != nil {
+		return nil, fmt.Errorf("register failed: %w", err)
+	}
+	return &retryTestIrpcClient{endpoint: endpoint}, nil
+}
+
+// Get implements [retryTest]
+//
+// Get returns the counter
+func (_c *retryTestIrpcClient) Get(ctx context.Context) (int, error) {
+	var req = _irpc_retryTest_GetReq{
+		// ctx: ctx,
+	}
+	var resp _irpc_retryTest_GetResp
+	if err := _c.endpoint.CallRemoteFunc(ctx, _retryTestIrpcId, 0, req, &resp); err != nil {
+		var zero _irpc_retryTest_GetResp
+		return zero.p0, err
+	}
+	return resp.p0, resp.p1
+}
+
+// Incr implements [retryTest]
+//
+// Incr increments the counter
+func (_c *retryTestIrpcClient) Incr(ctx context.Context) (int, error) {
+	var req = _irpc_retryTest_IncrReq{
+		// ctx: ctx,
+	}
+	var resp _irpc_retryTest_IncrResp
+	if err := _c.endpoint.CallRemoteFunc(ctx, _retryTestIrpcId, 1, req, &resp); err != nil {
+		var zero _irpc_retryTest_IncrResp
+		return zero.p0, err
+	}
+	return resp.p0, resp.p1
+}
+
+type _irpc_retryTest_GetReq struct {
+	//ctx context.Context
+
+}
+
+func (s _irpc_retryTest_GetReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_retryTest_GetReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_retryTest_GetResp struct {
+	p0 int
+	p1 error
+}
+
+func (s _irpc_retryTest_GetResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type int: %w", err)
+	}
+	if err := irpcgen.EncError(e, s.p1); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_retryTest_GetResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type int: %w", err)
+	}
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_retryTest_GetResp) ResultError() error {
+	return s.p1
+}
+
+type _irpc_retryTest_IncrReq struct {
+	//ctx context.Context
+
+}
+
+func (s _irpc_retryTest_IncrReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_retryTest_IncrReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_retryTest_IncrResp struct {
+	p0 int
+	p1 error
+}
+
+func (s _irpc_retryTest_IncrResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type int: %w", err)
+	}
+	if err := irpcgen.EncError(e, s.p1); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_retryTest_IncrResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type int: %w", err)
+	}
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_retryTest_IncrResp) ResultError() error {
+	return s.p1
+}
@@ -0,0 +1,64 @@
+package irpctestpkg
+
+import (
+	"context"
+	"io"
+	"net"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marben/irpc"
+)
+
+func TestRetryIdempotentMethods(t *testing.T) {
+	impl := &retryTestImpl{}
+	service := newRetryTestIrpcService(impl)
+
+	// service's side of the current connection
+	var serviceEp *irpc.Endpoint
+	var serviceEpMux sync.Mutex
+	dial := func(ctx context.Context) (io.ReadWriteCloser, error) {
+		c1, c2 := net.Pipe()
+		serviceEpMux.Lock()
+		serviceEp = irpc.NewEndpoint(c2, irpc.WithEndpointServices(service))
+		serviceEpMux.Unlock()
+		return c1, nil
+	}
+
+	// next call breaks the connection, while it's running
+	var failNext atomic.Bool
+	impl.onCall = func(ctx context.Context) {
+		if failNext.Swap(false) {
+			serviceEpMux.Lock()
+			serviceEp.Close()
+			serviceEpMux.Unlock()
+			<-ctx.Done()
+		}
+	}
+
+	rep := irpc.NewReconnectingEndpoint(dial, irpc.WithBackoff(func(int) time.Duration { return time.Millisecond }))
+	defer rep.Close()
+	policy := irpc.RetryPolicy{MaxAttempts: 10, Backoff: func(int) time.Duration { return 5 * time.Millisecond }}
+	c, err := newRetryTestIrpcClient(irpc.NewRetryingEndpoint(rep, policy))
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	failNext.Store(true)
+	if _, err := c.Get(context.Background()); err != nil {
+		t.Fatalf("Get(): %v", err)
+	}
+	if n := impl.numCalls(); n != 2 {
+		t.Fatalf("idempotent Get() was called %d times", n)
+	}
+
+	failNext.Store(true)
+	if _, err := c.Incr(context.Background()); !irpc.IsRetryable(err) {
+		t.Fatalf("Incr(): expected retryable error, got: %v", err)
+	}
+	if n := impl.numCalls(); n != 3 {
+		t.Fatalf("non-idempotent Incr() made %d calls in total, expected 3", n)
+	}
+}
@@ -9,7 +9,30 @@ import (
 	"time"
 )
 
-var _sliceTestIrpcId = irpcgen.ServiceId(0x03d0b1055ce76468)
+var _sliceTestIrpcId = irpcgen.ServiceId(0xbac0c4eb9b17b6cb)
+
+var _sliceTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _sliceTestIrpcId,
+	Name:      "sliceTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.sliceTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "SliceSum", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[]int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "VectMult", Params: []irpcgen.ParamDesc{{Name: "vect", Type: "[]int"}, {Name: "s", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[]int"}}, HasContext: false},
+		{Name: "SliceOfFloat64Sum", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[]float64"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "float64"}}, HasContext: false},
+		{Name: "SliceOfSlicesSum", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[][]int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "SliceOfBytesSum", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[]byte"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "namedByteSlice", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "namedByteSlice"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "sliceOfBools", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[]bool"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "namedBoolSlice"}}, HasContext: false},
+		{Name: "sliceOfUint8", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[]uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[]byte"}}, HasContext: false},
+		{Name: "sliceOfMaps", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[]map[int]string"}}, Results: nil, HasContext: false},
+		{Name: "sliceOfStructs", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[]struct{A int;B string;}"}}, Results: []irpcgen.ParamDesc{{Name: "sumA", Type: "int"}}, HasContext: false},
+		{Name: "sliceOfErrors", Params: []irpcgen.ParamDesc{{Name: "slice", Type: "[]error"}}, Results: nil, HasContext: false},
+		{Name: "isNilSlice", Params: []irpcgen.ParamDesc{{Name: "s", Type: "[]string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "isNilBoolSlice", Params: []irpcgen.ParamDesc{{Name: "bs", Type: "[]bool"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "isNilByteSlice", Params: []irpcgen.ParamDesc{{Name: "bs", Type: "[]byte"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "bool"}}, HasContext: false},
+		{Name: "sliceOfTimesReverse", Params: []irpcgen.ParamDesc{{Name: "in", Type: "[]time.Time"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[]time.Time"}}, HasContext: false},
+	},
+}
 
 // sliceTestIrpcService provides [sliceTest] interface over irpc
 type sliceTestIrpcService struct {
@@ -28,6 +51,11 @@ func (s *sliceTestIrpcService) Id() irpcgen.ServiceId {
 	return _sliceTestIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *sliceTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_sliceTestIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *sliceTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -220,7 +248,7 @@ type sliceTestIrpcClient struct {
 }
 
 func newSliceTestIrpcClient(endpoint irpcgen.Endpoint) (*sliceTestIrpcClient, error) {
-	if err := endpoint.RegisterClient(_sliceTestIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_sliceTestIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &sliceTestIrpcClient{endpoint: endpoint}, nil
@@ -798,20 +826,7 @@ type _irpc_sliceTest_sliceOfErrorsReq struct {
 
 func (s _irpc_sliceTest_sliceOfErrorsReq) Serialize(e *irpcgen.Encoder) error {
 	if err := func(enc *irpcgen.Encoder, sl []error) error {
-		return irpcgen.EncSlice(enc, sl, "error", func(enc *irpcgen.Encoder, v error) error {
-			isNil := v == nil
-			if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-				return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-			}
-			if isNil {
-				return nil
-			}
-			_Error_0_ := v.Error()
-			if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-				return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-			}
-			return nil
-		})
+		return irpcgen.EncSlice(enc, sl, "error", irpcgen.EncError)
 	}(e, s.slice); err != nil {
 		return fmt.Errorf("serialize \"slice\" of type []error: %w", err)
 	}
@@ -819,35 +834,13 @@ func (s _irpc_sliceTest_sliceOfErrorsReq) Serialize(e *irpcgen.Encoder) error {
 }
 func (s *_irpc_sliceTest_sliceOfErrorsReq) Deserialize(d *irpcgen.Decoder) error {
 	if err := func(dec *irpcgen.Decoder, sl *[]error) error {
-		return irpcgen.DecSlice(dec, sl, "error", func(dec *irpcgen.Decoder, s *error) error {
-			var isNil bool
-			if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-				return fmt.Errorf("deserialize isNil: %w", err)
-			}
-			if isNil {
-				return nil
-			}
-			var impl _error_sliceTest_impl
-			if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-				return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-			}
-			*s = impl
-			return nil
-		})
+		return irpcgen.DecSlice(dec, sl, "error", irpcgen.DecError)
 	}(d, &s.slice); err != nil {
 		return fmt.Errorf("deserialize slice of type []error: %w", err)
 	}
 	return nil
 }
 
-type _error_sliceTest_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_sliceTest_impl) Error() string {
-	return i._Error_0_
-}
-
 type _irpc_sliceTest_isNilSliceReq struct {
 	s []string
 }
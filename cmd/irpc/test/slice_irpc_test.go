@@ -130,3 +130,33 @@ func TestUint8SliceCasting(t *testing.T) {
 		t.Fatalf("%v != %v", in, out)
 	}
 }
+
+// messages longer than a frame are split into frames and reassembled
+func TestSliceLongerThanFrame(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create enpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	remoteEp.RegisterService(newSliceTestIrpcService(sliceTestImpl{skew: 1}))
+	c, err := newSliceTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	bs := bytes.Repeat([]byte{1}, 100_000)
+	if sum := c.SliceOfBytesSum(bs); sum != len(bs) {
+		t.Fatalf("SliceOfBytesSum(): %d != %d", sum, len(bs))
+	}
+
+	vect := make([]int, 10_000)
+	for i := range vect {
+		vect[i] = i
+	}
+	res := c.VectMult(vect, 2)
+	if len(res) != len(vect) || res[len(res)-1] != 2*(len(vect)-1)+1 {
+		t.Fatalf("VectMult(): unexpected result of len %d", len(res))
+	}
+}
@@ -9,7 +9,19 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _sliceNamedApiIrpcId = irpcgen.ServiceId(0xb222c0c6ee417023)
+var _sliceNamedApiIrpcId = irpcgen.ServiceId(0x395bf2eba5523ff8)
+
+var _sliceNamedApiIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _sliceNamedApiIrpcId,
+	Name:      "sliceNamedApi",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.sliceNamedApi",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "sumNamedInts", Params: []irpcgen.ParamDesc{{Name: "vec", Type: "namedSliceOfInts"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "sumOutsideNamedInts", Params: []irpcgen.ParamDesc{{Name: "vec", Type: "out.AliasedByteSlice"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "sumSliceOfNamedInts", Params: []irpcgen.ParamDesc{{Name: "vec", Type: "[]out.Uint8"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "out.Uint8"}}, HasContext: false},
+		{Name: "reverseNamedSliceOfTime", Params: []irpcgen.ParamDesc{{Name: "in", Type: "namedSliceOfTime"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "namedSliceOfTime"}}, HasContext: false},
+	},
+}
 
 // sliceNamedApiIrpcService provides [sliceNamedApi] interface over irpc
 type sliceNamedApiIrpcService struct {
@@ -28,6 +40,11 @@ func (s *sliceNamedApiIrpcService) Id() irpcgen.ServiceId {
 	return _sliceNamedApiIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *sliceNamedApiIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_sliceNamedApiIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *sliceNamedApiIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -90,7 +107,7 @@ type sliceNamedApiIrpcClient struct {
 }
 
 func newSliceNamedApiIrpcClient(endpoint irpcgen.Endpoint) (*sliceNamedApiIrpcClient, error) {
-	if err := endpoint.RegisterClient(_sliceNamedApiIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_sliceNamedApiIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &sliceNamedApiIrpcClient{endpoint: endpoint}, nil
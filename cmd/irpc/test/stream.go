@@ -0,0 +1,128 @@
+package irpctestpkg
+
+import (
+	"context"
+	"errors"
+	"iter"
+)
+
+//go:generate go run ../
+
+var errStreamTestFailed = errors.New("stream failed")
+
+type streamTest interface {
+	Count(ctx context.Context, n int) iter.Seq2[int, error]
+	CountNoCtx(n int) iter.Seq2[int, error]
+	FailAfter(n int) iter.Seq2[string, error]
+	Endless(ctx context.Context) iter.Seq2[int, error]
+	Structs(ctx context.Context, names []string) iter.Seq2[struct{ Name string }, error]
+	ChanCount(ctx context.Context, n int) <-chan int
+	ChanEndless(ctx context.Context) (out <-chan []byte)
+	PanicAfter(n int) iter.Seq2[int, error]
+}
+
+var _ streamTest = &streamTestImpl{}
+
+type streamTestImpl struct {
+	// endlessDone is closed, when the Endless stream's producer stopped
+	endlessDone chan struct{}
+}
+
+func newStreamTestImpl() *streamTestImpl {
+	return &streamTestImpl{endlessDone: make(chan struct{})}
+}
+
+// Count implements streamTest.
+func (st *streamTestImpl) Count(ctx context.Context, n int) iter.Seq2[int, error] {
+	return st.CountNoCtx(n)
+}
+
+// CountNoCtx implements streamTest.
+func (st *streamTestImpl) CountNoCtx(n int) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for i := range n {
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+}
+
+// FailAfter implements streamTest.
+func (st *streamTestImpl) FailAfter(n int) iter.Seq2[string, error] {
+	return func(yield func(string, error) bool) {
+		for range n {
+			if !yield("ok", nil) {
+				return
+			}
+		}
+		yield("", errStreamTestFailed)
+	}
+}
+
+// Endless implements streamTest.
+func (st *streamTestImpl) Endless(ctx context.Context) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		defer close(st.endlessDone)
+		for i := 0; ; i++ {
+			if !yield(i, nil) {
+				return
+			}
+		}
+	}
+}
+
+// Structs implements streamTest.
+func (st *streamTestImpl) Structs(ctx context.Context, names []string) iter.Seq2[struct{ Name string }, error] {
+	return func(yield func(struct{ Name string }, error) bool) {
+		for _, n := range names {
+			if !yield(struct{ Name string }{n}, nil) {
+				return
+			}
+		}
+	}
+}
+
+// ChanCount implements streamTest.
+func (st *streamTestImpl) ChanCount(ctx context.Context, n int) <-chan int {
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for i := range n {
+			select {
+			case ch <- i:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// ChanEndless implements streamTest.
+func (st *streamTestImpl) ChanEndless(ctx context.Context) <-chan []byte {
+	ch := make(chan []byte)
+	go func() {
+		defer close(st.endlessDone)
+		for {
+			select {
+			case ch <- []byte{1, 2, 3}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// PanicAfter implements streamTest.
+func (st *streamTestImpl) PanicAfter(n int) iter.Seq2[int, error] {
+	return func(yield func(int, error) bool) {
+		for i := range n {
+			if !yield(i, nil) {
+				return
+			}
+		}
+		panic("stream panicked")
+	}
+}
@@ -0,0 +1,777 @@
+// Code generated by irpc (devel); DO NOT EDIT
+// Source: github.com/marben/irpc/cmd/irpc/test/stream.go
+package irpctestpkg
+
+import (
+	"context"
+	"fmt"
+	"github.com/marben/irpc/irpcgen"
+	"iter"
+)
+
+var _streamTestIrpcId = irpcgen.ServiceId(0x17d4ba7d0c914cc2)
+
+var _streamTestIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _streamTestIrpcId,
+	Name:      "streamTest",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.streamTest",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "Count", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "n", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "iter.Seq2[int, error]"}}, HasContext: true},
+		{Name: "CountNoCtx", Params: []irpcgen.ParamDesc{{Name: "n", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "iter.Seq2[int, error]"}}, HasContext: false},
+		{Name: "FailAfter", Params: []irpcgen.ParamDesc{{Name: "n", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "iter.Seq2[string, error]"}}, HasContext: false},
+		{Name: "Endless", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "iter.Seq2[int, error]"}}, HasContext: true},
+		{Name: "Structs", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "names", Type: "[]string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "iter.Seq2[struct{Name string;}, error]"}}, HasContext: true},
+		{Name: "ChanCount", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "n", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "<-chan int"}}, HasContext: true},
+		{Name: "ChanEndless", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}}, Results: []irpcgen.ParamDesc{{Name: "out", Type: "<-chan []byte"}}, HasContext: true},
+		{Name: "PanicAfter", Params: []irpcgen.ParamDesc{{Name: "n", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "iter.Seq2[int, error]"}}, HasContext: false},
+	},
+}
+
+// streamTestIrpcService provides [streamTest] interface over irpc
+type streamTestIrpcService struct {
+	impl streamTest
+}
+
+// newStreamTestIrpcService returns new [irpcgen.Service] forwarding [streamTest] network calls to impl
+func newStreamTestIrpcService(impl streamTest) *streamTestIrpcService {
+	return &streamTestIrpcService{
+		impl: impl,
+	}
+}
+
+// Id implements [irpcgen.Service] interface.
+func (s *streamTestIrpcService) Id() irpcgen.ServiceId {
+	return _streamTestIrpcId
+}
+
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *streamTestIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_streamTestIrpcDesc
+}
+
+// GetFuncCall implements [irpcgen.Service] interface
+func (s *streamTestIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
+	switch funcId {
+	case 0: // Count
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_streamTest_CountReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_streamTest_CountResp
+				seq := s.impl.Count(ctx, args.n)
+				return irpcgen.NewStreamSerializable(&resp, func(ctx context.Context, send irpcgen.ItemSender) {
+					resp.p0 = irpcgen.SendSeq2(seq, func(v int) irpcgen.Serializable { return _irpc_streamTest_CountItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	case 1: // CountNoCtx
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_streamTest_CountNoCtxReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_streamTest_CountNoCtxResp
+				seq := s.impl.CountNoCtx(args.n)
+				return irpcgen.NewStreamSerializable(&resp, func(ctx context.Context, send irpcgen.ItemSender) {
+					resp.p0 = irpcgen.SendSeq2(seq, func(v int) irpcgen.Serializable { return _irpc_streamTest_CountNoCtxItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	case 2: // FailAfter
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_streamTest_FailAfterReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_streamTest_FailAfterResp
+				seq := s.impl.FailAfter(args.n)
+				return irpcgen.NewStreamSerializable(&resp, func(ctx context.Context, send irpcgen.ItemSender) {
+					resp.p0 = irpcgen.SendSeq2(seq, func(v string) irpcgen.Serializable { return _irpc_streamTest_FailAfterItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	case 3: // Endless
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_streamTest_EndlessReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_streamTest_EndlessResp
+				seq := s.impl.Endless(ctx)
+				return irpcgen.NewStreamSerializable(&resp, func(ctx context.Context, send irpcgen.ItemSender) {
+					resp.p0 = irpcgen.SendSeq2(seq, func(v int) irpcgen.Serializable { return _irpc_streamTest_EndlessItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	case 4: // Structs
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_streamTest_StructsReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_streamTest_StructsResp
+				seq := s.impl.Structs(ctx, args.names)
+				return irpcgen.NewStreamSerializable(&resp, func(ctx context.Context, send irpcgen.ItemSender) {
+					resp.p0 = irpcgen.SendSeq2(seq, func(v struct{ Name string }) irpcgen.Serializable { return _irpc_streamTest_StructsItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	case 5: // ChanCount
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_streamTest_ChanCountReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				ch := s.impl.ChanCount(ctx, args.n)
+				return irpcgen.NewStreamSerializable(irpcgen.EmptySerializable{}, func(ctx context.Context, send irpcgen.ItemSender) {
+					irpcgen.SendChan(ctx, ch, func(v int) irpcgen.Serializable { return _irpc_streamTest_ChanCountItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	case 6: // ChanEndless
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_streamTest_ChanEndlessReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				ch := s.impl.ChanEndless(ctx)
+				return irpcgen.NewStreamSerializable(irpcgen.EmptySerializable{}, func(ctx context.Context, send irpcgen.ItemSender) {
+					irpcgen.SendChan(ctx, ch, func(v []byte) irpcgen.Serializable { return _irpc_streamTest_ChanEndlessItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	case 7: // PanicAfter
+		return func(d *irpcgen.Decoder) (irpcgen.FuncExecutor, error) {
+			var args _irpc_streamTest_PanicAfterReq
+			if err := args.Deserialize(d); err != nil {
+				return nil, err
+			}
+			return func(ctx context.Context) irpcgen.Serializable {
+				var resp _irpc_streamTest_PanicAfterResp
+				seq := s.impl.PanicAfter(args.n)
+				return irpcgen.NewStreamSerializable(&resp, func(ctx context.Context, send irpcgen.ItemSender) {
+					resp.p0 = irpcgen.SendSeq2(seq, func(v int) irpcgen.Serializable { return _irpc_streamTest_PanicAfterItem{v: v} }, send)
+				})
+			}, nil
+		}, nil
+	default:
+		return nil, fmt.Errorf("function '%d' doesn't exist on service '%s'", funcId, s.Id())
+	}
+}
+
+// streamTestIrpcClient implements [streamTest] interface. It by forwards calls over network to [streamTestIrpcService] that provides the implementation.
+type streamTestIrpcClient struct {
+	endpoint irpcgen.Endpoint
+}
+
+func newStreamTestIrpcClient(endpoint irpcgen.Endpoint) (*streamTestIrpcClient, error) {
+	if err := irpcgen.RegisterClient(endpoint, &_streamTestIrpcDesc); err != nil {
+		return nil, fmt.Errorf("register failed: %w", err)
+	}
+	return &streamTestIrpcClient{endpoint: endpoint}, nil
+}
+
+// Count implements [streamTest]
+//
+func (_c *streamTestIrpcClient) Count(ctx context.Context, n int) iter.Seq2[int, error] {
+	var req = _irpc_streamTest_CountReq{
+		// ctx: ctx,
+		n: n,
+	}
+	return func(yield func(int, error) bool) {
+		var resp _irpc_streamTest_CountResp
+		for item, err := range _c.endpoint.CallRemoteStream(ctx, _streamTestIrpcId, 0, req, func() irpcgen.Deserializable { return new(_irpc_streamTest_CountItem) }, &resp) {
+			if err != nil {
+				var zero _irpc_streamTest_CountItem
+				yield(zero.v, err)
+				return
+			}
+			if !yield(item.(*_irpc_streamTest_CountItem).v, nil) {
+				return
+			}
+		}
+		if resp.p0 != nil {
+			var zero _irpc_streamTest_CountItem
+			yield(zero.v, resp.p0)
+		}
+	}
+}
+
+// CountNoCtx implements [streamTest]
+func (_c *streamTestIrpcClient) CountNoCtx(n int) iter.Seq2[int, error] {
+	var req = _irpc_streamTest_CountNoCtxReq{
+		n: n,
+	}
+	return func(yield func(int, error) bool) {
+		var resp _irpc_streamTest_CountNoCtxResp
+		for item, err := range _c.endpoint.CallRemoteStream(context.Background(), _streamTestIrpcId, 1, req, func() irpcgen.Deserializable { return new(_irpc_streamTest_CountNoCtxItem) }, &resp) {
+			if err != nil {
+				var zero _irpc_streamTest_CountNoCtxItem
+				yield(zero.v, err)
+				return
+			}
+			if !yield(item.(*_irpc_streamTest_CountNoCtxItem).v, nil) {
+				return
+			}
+		}
+		if resp.p0 != nil {
+			var zero _irpc_streamTest_CountNoCtxItem
+			yield(zero.v, resp.p0)
+		}
+	}
+}
+
+// FailAfter implements [streamTest]
+func (_c *streamTestIrpcClient) FailAfter(n int) iter.Seq2[string, error] {
+	var req = _irpc_streamTest_FailAfterReq{
+		n: n,
+	}
+	return func(yield func(string, error) bool) {
+		var resp _irpc_streamTest_FailAfterResp
+		for item, err := range _c.endpoint.CallRemoteStream(context.Background(), _streamTestIrpcId, 2, req, func() irpcgen.Deserializable { return new(_irpc_streamTest_FailAfterItem) }, &resp) {
+			if err != nil {
+				var zero _irpc_streamTest_FailAfterItem
+				yield(zero.v, err)
+				return
+			}
+			if !yield(item.(*_irpc_streamTest_FailAfterItem).v, nil) {
+				return
+			}
+		}
+		if resp.p0 != nil {
+			var zero _irpc_streamTest_FailAfterItem
+			yield(zero.v, resp.p0)
+		}
+	}
+}
+
+// Endless implements [streamTest]
+func (_c *streamTestIrpcClient) Endless(ctx context.Context) iter.Seq2[int, error] {
+	var req = _irpc_streamTest_EndlessReq{
+		// ctx: ctx,
+	}
+	return func(yield func(int, error) bool) {
+		var resp _irpc_streamTest_EndlessResp
+		for item, err := range _c.endpoint.CallRemoteStream(ctx, _streamTestIrpcId, 3, req, func() irpcgen.Deserializable { return new(_irpc_streamTest_EndlessItem) }, &resp) {
+			if err != nil {
+				var zero _irpc_streamTest_EndlessItem
+				yield(zero.v, err)
+				return
+			}
+			if !yield(item.(*_irpc_streamTest_EndlessItem).v, nil) {
+				return
+			}
+		}
+		if resp.p0 != nil {
+			var zero _irpc_streamTest_EndlessItem
+			yield(zero.v, resp.p0)
+		}
+	}
+}
+
+// Structs implements [streamTest]
+func (_c *streamTestIrpcClient) Structs(ctx context.Context, names []string) iter.Seq2[struct{ Name string }, error] {
+	var req = _irpc_streamTest_StructsReq{
+		// ctx: ctx,
+		names: names,
+	}
+	return func(yield func(struct{ Name string }, error) bool) {
+		var resp _irpc_streamTest_StructsResp
+		for item, err := range _c.endpoint.CallRemoteStream(ctx, _streamTestIrpcId, 4, req, func() irpcgen.Deserializable { return new(_irpc_streamTest_StructsItem) }, &resp) {
+			if err != nil {
+				var zero _irpc_streamTest_StructsItem
+				yield(zero.v, err)
+				return
+			}
+			if !yield(item.(*_irpc_streamTest_StructsItem).v, nil) {
+				return
+			}
+		}
+		if resp.p0 != nil {
+			var zero _irpc_streamTest_StructsItem
+			yield(zero.v, resp.p0)
+		}
+	}
+}
+
+// ChanCount implements [streamTest]
+//
+// Any error closes the returned channel early. Use [irpcgen.ContextWithStreamErrorHandler] to learn about it.
+func (_c *streamTestIrpcClient) ChanCount(ctx context.Context, n int) <-chan int {
+	var req = _irpc_streamTest_ChanCountReq{
+		// ctx: ctx,
+		n: n,
+	}
+	ch := make(chan int)
+	go func() {
+		defer close(ch)
+		for item, err := range _c.endpoint.CallRemoteStream(ctx, _streamTestIrpcId, 5, req, func() irpcgen.Deserializable { return new(_irpc_streamTest_ChanCountItem) }, irpcgen.EmptyDeserializable{}) {
+			if err != nil {
+				irpcgen.ReportStreamError(ctx, err)
+				return
+			}
+			select {
+			case ch <- item.(*_irpc_streamTest_ChanCountItem).v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// ChanEndless implements [streamTest]
+//
+// Any error closes the returned channel early. Use [irpcgen.ContextWithStreamErrorHandler] to learn about it.
+func (_c *streamTestIrpcClient) ChanEndless(ctx context.Context) (out <-chan []byte) {
+	var req = _irpc_streamTest_ChanEndlessReq{
+		// ctx: ctx,
+	}
+	ch := make(chan []byte)
+	go func() {
+		defer close(ch)
+		for item, err := range _c.endpoint.CallRemoteStream(ctx, _streamTestIrpcId, 6, req, func() irpcgen.Deserializable { return new(_irpc_streamTest_ChanEndlessItem) }, irpcgen.EmptyDeserializable{}) {
+			if err != nil {
+				irpcgen.ReportStreamError(ctx, err)
+				return
+			}
+			select {
+			case ch <- item.(*_irpc_streamTest_ChanEndlessItem).v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
+
+// PanicAfter implements [streamTest]
+func (_c *streamTestIrpcClient) PanicAfter(n int) iter.Seq2[int, error] {
+	var req = _irpc_streamTest_PanicAfterReq{
+		n: n,
+	}
+	return func(yield func(int, error) bool) {
+		var resp _irpc_streamTest_PanicAfterResp
+		for item, err := range _c.endpoint.CallRemoteStream(context.Background(), _streamTestIrpcId, 7, req, func() irpcgen.Deserializable { return new(_irpc_streamTest_PanicAfterItem) }, &resp) {
+			if err != nil {
+				var zero _irpc_streamTest_PanicAfterItem
+				yield(zero.v, err)
+				return
+			}
+			if !yield(item.(*_irpc_streamTest_PanicAfterItem).v, nil) {
+				return
+			}
+		}
+		if resp.p0 != nil {
+			var zero _irpc_streamTest_PanicAfterItem
+			yield(zero.v, resp.p0)
+		}
+	}
+}
+
+type _irpc_streamTest_CountReq struct {
+	//ctx context.Context
+	n int
+}
+
+func (s _irpc_streamTest_CountReq) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.n); err != nil {
+		return fmt.Errorf("serialize \"n\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_CountReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.n); err != nil {
+		return fmt.Errorf("deserialize n of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_CountResp struct {
+	p0 error
+}
+
+func (s _irpc_streamTest_CountResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_CountResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_streamTest_CountResp) ResultError() error {
+	return s.p0
+}
+
+type _irpc_streamTest_CountItem struct {
+	v int
+}
+
+func (s _irpc_streamTest_CountItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_CountItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_CountNoCtxReq struct {
+	n int
+}
+
+func (s _irpc_streamTest_CountNoCtxReq) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.n); err != nil {
+		return fmt.Errorf("serialize \"n\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_CountNoCtxReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.n); err != nil {
+		return fmt.Errorf("deserialize n of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_CountNoCtxResp struct {
+	p0 error
+}
+
+func (s _irpc_streamTest_CountNoCtxResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_CountNoCtxResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_streamTest_CountNoCtxResp) ResultError() error {
+	return s.p0
+}
+
+type _irpc_streamTest_CountNoCtxItem struct {
+	v int
+}
+
+func (s _irpc_streamTest_CountNoCtxItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_CountNoCtxItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_FailAfterReq struct {
+	n int
+}
+
+func (s _irpc_streamTest_FailAfterReq) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.n); err != nil {
+		return fmt.Errorf("serialize \"n\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_FailAfterReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.n); err != nil {
+		return fmt.Errorf("deserialize n of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_FailAfterResp struct {
+	p0 error
+}
+
+func (s _irpc_streamTest_FailAfterResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_FailAfterResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_streamTest_FailAfterResp) ResultError() error {
+	return s.p0
+}
+
+type _irpc_streamTest_FailAfterItem struct {
+	v string
+}
+
+func (s _irpc_streamTest_FailAfterItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncString(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type string: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_FailAfterItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecString(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type string: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_EndlessReq struct {
+	//ctx context.Context
+
+}
+
+func (s _irpc_streamTest_EndlessReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_streamTest_EndlessReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_streamTest_EndlessResp struct {
+	p0 error
+}
+
+func (s _irpc_streamTest_EndlessResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_EndlessResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_streamTest_EndlessResp) ResultError() error {
+	return s.p0
+}
+
+type _irpc_streamTest_EndlessItem struct {
+	v int
+}
+
+func (s _irpc_streamTest_EndlessItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_EndlessItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_StructsReq struct {
+	//ctx context.Context
+	names []string
+}
+
+func (s _irpc_streamTest_StructsReq) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, sl []string) error {
+		return irpcgen.EncSlice(enc, sl, "string", irpcgen.EncString)
+	}(e, s.names); err != nil {
+		return fmt.Errorf("serialize \"names\" of type []string: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_StructsReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, sl *[]string) error {
+		return irpcgen.DecSlice(dec, sl, "string", irpcgen.DecString)
+	}(d, &s.names); err != nil {
+		return fmt.Errorf("deserialize names of type []string: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_StructsResp struct {
+	p0 error
+}
+
+func (s _irpc_streamTest_StructsResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_StructsResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_streamTest_StructsResp) ResultError() error {
+	return s.p0
+}
+
+type _irpc_streamTest_StructsItem struct {
+	v struct{ Name string }
+}
+
+func (s _irpc_streamTest_StructsItem) Serialize(e *irpcgen.Encoder) error {
+	if err := func(enc *irpcgen.Encoder, s struct{ Name string }) error {
+		if err := irpcgen.EncString(enc, s.Name); err != nil {
+			return fmt.Errorf("serialize s.Name of type string: %w", err)
+		}
+		return nil
+	}(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type struct{Name string;}: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_StructsItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := func(dec *irpcgen.Decoder, s *struct{ Name string }) error {
+		if err := irpcgen.DecString(dec, &s.Name); err != nil {
+			return fmt.Errorf("deserialize s.Name of type string: %w", err)
+		}
+		return nil
+	}(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type struct{Name string;}: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_ChanCountReq struct {
+	//ctx context.Context
+	n int
+}
+
+func (s _irpc_streamTest_ChanCountReq) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.n); err != nil {
+		return fmt.Errorf("serialize \"n\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_ChanCountReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.n); err != nil {
+		return fmt.Errorf("deserialize n of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_ChanCountItem struct {
+	v int
+}
+
+func (s _irpc_streamTest_ChanCountItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_ChanCountItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_ChanEndlessReq struct {
+	//ctx context.Context
+
+}
+
+func (s _irpc_streamTest_ChanEndlessReq) Serialize(e *irpcgen.Encoder) error {
+	return nil
+}
+func (s *_irpc_streamTest_ChanEndlessReq) Deserialize(d *irpcgen.Decoder) error {
+	return nil
+}
+
+type _irpc_streamTest_ChanEndlessItem struct {
+	v []byte
+}
+
+func (s _irpc_streamTest_ChanEndlessItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncByteSlice(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type []byte: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_ChanEndlessItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecByteSlice(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type []byte: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_PanicAfterReq struct {
+	n int
+}
+
+func (s _irpc_streamTest_PanicAfterReq) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.n); err != nil {
+		return fmt.Errorf("serialize \"n\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_PanicAfterReq) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.n); err != nil {
+		return fmt.Errorf("deserialize n of type int: %w", err)
+	}
+	return nil
+}
+
+type _irpc_streamTest_PanicAfterResp struct {
+	p0 error
+}
+
+func (s _irpc_streamTest_PanicAfterResp) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
+		return fmt.Errorf("serialize type error: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_PanicAfterResp) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
+		return fmt.Errorf("deserialize type error: %w", err)
+	}
+	return nil
+}
+func (s _irpc_streamTest_PanicAfterResp) ResultError() error {
+	return s.p0
+}
+
+type _irpc_streamTest_PanicAfterItem struct {
+	v int
+}
+
+func (s _irpc_streamTest_PanicAfterItem) Serialize(e *irpcgen.Encoder) error {
+	if err := irpcgen.EncInt(e, s.v); err != nil {
+		return fmt.Errorf("serialize \"v\" of type int: %w", err)
+	}
+	return nil
+}
+func (s *_irpc_streamTest_PanicAfterItem) Deserialize(d *irpcgen.Decoder) error {
+	if err := irpcgen.DecInt(d, &s.v); err != nil {
+		return fmt.Errorf("deserialize v of type int: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,378 @@
+package irpctestpkg
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marben/irpc"
+	"github.com/marben/irpc/cmd/irpc/test/testtools"
+	"github.com/marben/irpc/irpcgen"
+)
+
+func TestStream(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints(irpc.WithStreamWindow(4))
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	remoteEp.RegisterService(newStreamTestIrpcService(newStreamTestImpl()))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	// more items than is the stream window
+	var got []int
+	for v, err := range c.Count(context.Background(), 100) {
+		if err != nil {
+			t.Fatalf("Count(): %v", err)
+		}
+		got = append(got, v)
+	}
+	if len(got) != 100 || got[0] != 0 || got[99] != 99 {
+		t.Fatalf("Count(): %v", got)
+	}
+
+	got = got[:0]
+	for v, err := range c.CountNoCtx(3) {
+		if err != nil {
+			t.Fatalf("CountNoCtx(): %v", err)
+		}
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("CountNoCtx(): %v", got)
+	}
+
+	// empty stream
+	for v, err := range c.Count(context.Background(), 0) {
+		t.Fatalf("unexpected item from empty stream: %v, %v", v, err)
+	}
+
+	var names []string
+	for v, err := range c.Structs(context.Background(), []string{"a", "b"}) {
+		if err != nil {
+			t.Fatalf("Structs(): %v", err)
+		}
+		names = append(names, v.Name)
+	}
+	if !slices.Equal(names, []string{"a", "b"}) {
+		t.Fatalf("Structs(): %v", names)
+	}
+
+	// iterating the same sequence twice makes two calls
+	seq := c.CountNoCtx(2)
+	for range 2 {
+		n := 0
+		for range seq {
+			n++
+		}
+		if n != 2 {
+			t.Fatalf("unexpected number of items: %d", n)
+		}
+	}
+}
+
+func TestStreamError(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	remoteEp.RegisterService(newStreamTestIrpcService(newStreamTestImpl()))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	var oks int
+	var streamErr error
+	for v, err := range c.FailAfter(3) {
+		if err != nil {
+			streamErr = err
+			continue
+		}
+		if v != "ok" {
+			t.Fatalf("unexpected value: %q", v)
+		}
+		oks++
+	}
+	if oks != 3 {
+		t.Fatalf("unexpected number of items: %d", oks)
+	}
+	if streamErr == nil || streamErr.Error() != errStreamTestFailed.Error() {
+		t.Fatalf("unexpected stream error: %v", streamErr)
+	}
+}
+
+func TestStreamBreakStopsProducer(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints(irpc.WithStreamWindow(2))
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	impl := newStreamTestImpl()
+	remoteEp.RegisterService(newStreamTestIrpcService(impl))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	for v, err := range c.Endless(context.Background()) {
+		if err != nil {
+			t.Fatalf("Endless(): %v", err)
+		}
+		if v == 10 {
+			break
+		}
+	}
+
+	select {
+	case <-impl.endlessDone:
+	case <-time.After(time.Second):
+		t.Fatalf("producer didn't stop after client stopped iterating")
+	}
+
+	// endpoint is still usable and request numbers were recycled
+	for range irpc.DefaultParallelClientCalls + 1 {
+		n := 0
+		for range c.CountNoCtx(5) {
+			n++
+		}
+		if n != 5 {
+			t.Fatalf("unexpected number of items: %d", n)
+		}
+	}
+}
+
+func TestStreamContextCancel(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	remoteEp.RegisterService(newStreamTestIrpcService(newStreamTestImpl()))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	var streamErr error
+	for v, err := range c.Endless(ctx) {
+		if err != nil {
+			streamErr = err
+			break
+		}
+		if v == 5 {
+			cancel()
+		}
+	}
+	if streamErr == nil || streamErr.Error() != context.Canceled.Error() {
+		t.Fatalf("unexpected stream error: %v", streamErr)
+	}
+}
+
+func TestStreamEndpointClose(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+
+	remoteEp.RegisterService(newStreamTestIrpcService(newStreamTestImpl()))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	var streamErr error
+	for v, err := range c.Endless(context.Background()) {
+		if err != nil {
+			streamErr = err
+			break
+		}
+		if v == 5 {
+			remoteEp.Close()
+		}
+	}
+	// our credit write may fail on closed connection before we read peer's closing packet
+	if !errors.Is(streamErr, irpc.ErrEndpointClosedByPeer) && !errors.Is(streamErr, irpc.ErrEndpointClosed) {
+		t.Fatalf("unexpected stream error: %v", streamErr)
+	}
+}
+
+func TestChanStream(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints(irpc.WithStreamWindow(3))
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	impl := newStreamTestImpl()
+	remoteEp.RegisterService(newStreamTestIrpcService(impl))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	var got []int
+	for v := range c.ChanCount(context.Background(), 10) {
+		got = append(got, v)
+	}
+	if !slices.Equal(got, []int{0, 1, 2, 3, 4, 5, 6, 7, 8, 9}) {
+		t.Fatalf("ChanCount(): %v", got)
+	}
+
+	// cancelation stops the remote producer
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	ch := c.ChanEndless(ctx)
+	for range 5 {
+		if v := <-ch; !slices.Equal(v, []byte{1, 2, 3}) {
+			t.Fatalf("ChanEndless(): %v", v)
+		}
+	}
+	cancel()
+	select {
+	case <-impl.endlessDone:
+	case <-time.After(time.Second):
+		t.Fatalf("producer didn't stop after context cancelation")
+	}
+	for range ch {
+		// channel gets closed
+	}
+}
+
+func TestChanStreamReportsError(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+
+	remoteEp.RegisterService(newStreamTestIrpcService(newStreamTestImpl()))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	// complete stream reports nothing
+	var streamErr error
+	ctx := irpcgen.ContextWithStreamErrorHandler(context.Background(), func(err error) { streamErr = err })
+	for range c.ChanCount(ctx, 3) {
+	}
+	if streamErr != nil {
+		t.Fatalf("complete stream reported: %v", streamErr)
+	}
+
+	// truncated stream does
+	ch := c.ChanEndless(ctx)
+	<-ch
+	remoteEp.Close()
+	for range ch {
+	}
+	if !errors.Is(streamErr, irpc.ErrEndpointClosed) {
+		t.Fatalf("expected ErrEndpointClosed, got: %v", streamErr)
+	}
+}
+
+func TestStreamPanic(t *testing.T) {
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	remoteEp.RegisterService(newStreamTestIrpcService(newStreamTestImpl()))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	var got []int
+	var panicErr *irpc.RemotePanicError
+	for v, err := range c.PanicAfter(3) {
+		if err != nil {
+			if !errors.As(err, &panicErr) {
+				t.Fatalf("expected RemotePanicError, got: %v", err)
+			}
+			break
+		}
+		got = append(got, v)
+	}
+	if panicErr == nil || panicErr.Value != "stream panicked" {
+		t.Fatalf("stream didn't end with panic error: %v", panicErr)
+	}
+	if !slices.Equal(got, []int{0, 1, 2}) {
+		t.Fatalf("items before panic: %v", got)
+	}
+
+	// the connection survives
+	for range c.Count(context.Background(), 1) {
+	}
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	var serverDone atomic.Bool
+	serverInterceptor := func(ctx context.Context, call irpc.CallInfo, handler irpc.ServerHandler) (irpcgen.Serializable, error) {
+		resp, err := handler(ctx)
+		serverDone.Store(true)
+		return resp, err
+	}
+	var clientCalls int
+	consumed := 0
+	clientInterceptor := func(ctx context.Context, call irpc.CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable, invoke irpc.ClientInvoker) error {
+		clientCalls++
+		err := invoke(ctx, call, req, resp)
+		if consumed != 5 {
+			t.Errorf("invoke returned after %d consumed items", consumed)
+		}
+		return err
+	}
+
+	// with window of 1 item, the server cannot get far ahead of us
+	localEp, remoteEp, err := testtools.CreateLocalTcpEndpoints(irpc.WithStreamWindow(1), irpc.WithClientInterceptors(clientInterceptor), irpc.WithServerInterceptors(serverInterceptor))
+	if err != nil {
+		t.Fatalf("create endpoints: %v", err)
+	}
+	defer localEp.Close()
+	defer remoteEp.Close()
+
+	remoteEp.RegisterService(newStreamTestIrpcService(newStreamTestImpl()))
+	c, err := newStreamTestIrpcClient(localEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	for v, err := range c.Count(context.Background(), 5) {
+		if err != nil {
+			t.Fatalf("Count(): %v", err)
+		}
+		if v == 0 && serverDone.Load() {
+			t.Fatalf("server handler returned before the stream was consumed")
+		}
+		consumed++
+	}
+	if !serverDone.Load() {
+		t.Fatalf("server handler didn't return before the end of the stream")
+	}
+	if clientCalls != 1 {
+		t.Fatalf("client interceptor called %d times", clientCalls)
+	}
+}
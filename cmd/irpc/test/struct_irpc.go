@@ -11,7 +11,24 @@ import (
 	"image"
 )
 
-var _structAPIIrpcId = irpcgen.ServiceId(0x9189774859284f92)
+var _structAPIIrpcId = irpcgen.ServiceId(0x1ee161b28e00e726)
+
+var _structAPIIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _structAPIIrpcId,
+	Name:      "structAPI",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.structAPI",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "VectSum", Params: []irpcgen.ParamDesc{{Name: "v", Type: "vect3"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "Vect3x3Sum", Params: []irpcgen.ParamDesc{{Name: "v", Type: "vect3x3"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "vect3"}}, HasContext: false},
+		{Name: "SumSliceStruct", Params: []irpcgen.ParamDesc{{Name: "s", Type: "sliceStruct"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "InlineParams", Params: []irpcgen.ParamDesc{{Name: "s", Type: "struct{a int;}"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "InlineParamsNamed", Params: []irpcgen.ParamDesc{{Name: "s", Type: "struct{a out.Uint8;b out2.Uint8;}"}}, Results: nil, HasContext: false},
+		{Name: "InlineInlineParams", Params: []irpcgen.ParamDesc{{Name: "s", Type: "struct{a struct{b int;};}"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "InlineReturn", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "struct{b int;}"}}, HasContext: false},
+		{Name: "PointNeg", Params: []irpcgen.ParamDesc{{Name: "p", Type: "image.Point"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "image.Point"}}, HasContext: false},
+		{Name: "ReturnErr", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "error"}}, HasContext: false},
+	},
+}
 
 // structAPIIrpcService provides [structAPI] interface over irpc
 type structAPIIrpcService struct {
@@ -30,6 +47,11 @@ func (s *structAPIIrpcService) Id() irpcgen.ServiceId {
 	return _structAPIIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *structAPIIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_structAPIIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *structAPIIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -147,7 +169,7 @@ type structAPIIrpcClient struct {
 }
 
 func newStructAPIIrpcClient(endpoint irpcgen.Endpoint) (*structAPIIrpcClient, error) {
-	if err := endpoint.RegisterClient(_structAPIIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_structAPIIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &structAPIIrpcClient{endpoint: endpoint}, nil
@@ -765,49 +787,17 @@ type _irpc_structAPI_ReturnErrResp struct {
 }
 
 func (s _irpc_structAPI_ReturnErrResp) Serialize(e *irpcgen.Encoder) error {
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p0); err != nil {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
 }
 func (s *_irpc_structAPI_ReturnErrResp) Deserialize(d *irpcgen.Decoder) error {
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_structAPI_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p0); err != nil {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_structAPI_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_structAPI_impl) Error() string {
-	return i._Error_0_
+func (s _irpc_structAPI_ReturnErrResp) ResultError() error {
+	return s.p0
 }
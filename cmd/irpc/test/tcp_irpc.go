@@ -8,7 +8,16 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _tcpTestApiIrpcId = irpcgen.ServiceId(0xc9d8c7611e337738)
+var _tcpTestApiIrpcId = irpcgen.ServiceId(0xa02dc125b0ff4569)
+
+var _tcpTestApiIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _tcpTestApiIrpcId,
+	Name:      "tcpTestApi",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.tcpTestApi",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "Div", Params: []irpcgen.ParamDesc{{Name: "a", Type: "float64"}, {Name: "b", Type: "float64"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "float64"}, {Name: "", Type: "error"}}, HasContext: false},
+	},
+}
 
 // tcpTestApiIrpcService provides [tcpTestApi] interface over irpc
 type tcpTestApiIrpcService struct {
@@ -27,6 +36,11 @@ func (s *tcpTestApiIrpcService) Id() irpcgen.ServiceId {
 	return _tcpTestApiIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *tcpTestApiIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_tcpTestApiIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *tcpTestApiIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -53,7 +67,7 @@ type tcpTestApiIrpcClient struct {
 }
 
 func newTcpTestApiIrpcClient(endpoint irpcgen.Endpoint) (*tcpTestApiIrpcClient, error) {
-	if err := endpoint.RegisterClient(_tcpTestApiIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_tcpTestApiIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &tcpTestApiIrpcClient{endpoint: endpoint}, nil
@@ -107,20 +121,7 @@ func (s _irpc_tcpTestApi_DivResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncFloat64(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type float64: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -129,30 +130,11 @@ func (s *_irpc_tcpTestApi_DivResp) Deserialize(d *irpcgen.Decoder) error {
 	if err := irpcgen.DecFloat64(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type float64: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_tcpTestApi_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_tcpTestApi_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_tcpTestApi_impl) Error() string {
-	return i._Error_0_
+func (s _irpc_tcpTestApi_DivResp) ResultError() error {
+	return s.p1
 }
@@ -8,7 +8,18 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _TestServiceIrpcId = irpcgen.ServiceId(0xf07be4a7675ed89d)
+var _TestServiceIrpcId = irpcgen.ServiceId(0x1d1a039519c474c5)
+
+var _TestServiceIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _TestServiceIrpcId,
+	Name:      "TestService",
+	Interface: "github.com/marben/irpc/cmd/irpc/test/testtools.TestService",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "Div", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+		{Name: "DivErr", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}, {Name: "", Type: "error"}}, HasContext: false},
+		{Name: "DivCtxErr", Params: []irpcgen.ParamDesc{{Name: "ctx", Type: "context.Context"}, {Name: "a", Type: "int"}, {Name: "b", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}, {Name: "", Type: "error"}}, HasContext: true},
+	},
+}
 
 // TestServiceIrpcService provides [TestService] interface over irpc
 type TestServiceIrpcService struct {
@@ -27,6 +38,11 @@ func (s *TestServiceIrpcService) Id() irpcgen.ServiceId {
 	return _TestServiceIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *TestServiceIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_TestServiceIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *TestServiceIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -77,7 +93,7 @@ type TestServiceIrpcClient struct {
 }
 
 func NewTestServiceIrpcClient(endpoint irpcgen.Endpoint) (*TestServiceIrpcClient, error) {
-	if err := endpoint.RegisterClient(_TestServiceIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_TestServiceIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &TestServiceIrpcClient{endpoint: endpoint}, nil
@@ -200,20 +216,7 @@ func (s _irpc_TestService_DivErrResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncInt(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type int: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -222,36 +225,17 @@ func (s *_irpc_TestService_DivErrResp) Deserialize(d *irpcgen.Decoder) error {
 	if err := irpcgen.DecInt(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type int: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_TestService_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_TestService_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_TestService_impl) Error() string {
-	return i._Error_0_
+func (s _irpc_TestService_DivErrResp) ResultError() error {
+	return s.p1
 }
 
 type _irpc_TestService_DivCtxErrReq struct {
-	// ctx context.Context
+	//ctx context.Context
 	a int
 	b int
 }
@@ -284,20 +268,7 @@ func (s _irpc_TestService_DivCtxErrResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncInt(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type int: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -306,22 +277,11 @@ func (s *_irpc_TestService_DivCtxErrResp) Deserialize(d *irpcgen.Decoder) error
 	if err := irpcgen.DecInt(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type int: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_TestService_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_TestService_DivCtxErrResp) ResultError() error {
+	return s.p1
+}
@@ -8,7 +8,16 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _tbInterfaceAIrpcId = irpcgen.ServiceId(0x01289bec14e3501d)
+var _tbInterfaceAIrpcId = irpcgen.ServiceId(0x620cf96d36482c29)
+
+var _tbInterfaceAIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _tbInterfaceAIrpcId,
+	Name:      "tbInterfaceA",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.tbInterfaceA",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "reverse", Params: []irpcgen.ParamDesc{{Name: "p0", Type: "string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}}, HasContext: false},
+	},
+}
 
 // tbInterfaceAIrpcService provides [tbInterfaceA] interface over irpc
 type tbInterfaceAIrpcService struct {
@@ -27,6 +36,11 @@ func (s *tbInterfaceAIrpcService) Id() irpcgen.ServiceId {
 	return _tbInterfaceAIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *tbInterfaceAIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_tbInterfaceAIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *tbInterfaceAIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -55,7 +69,7 @@ type tbInterfaceAIrpcClient struct {
 }
 
 func newTbInterfaceAIrpcClient(endpoint irpcgen.Endpoint) (*tbInterfaceAIrpcClient, error) {
-	if err := endpoint.RegisterClient(_tbInterfaceAIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_tbInterfaceAIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &tbInterfaceAIrpcClient{endpoint: endpoint}, nil
@@ -108,7 +122,16 @@ func (s *_irpc_tbInterfaceA_reverseResp) Deserialize(d *irpcgen.Decoder) error {
 	return nil
 }
 
-var _tvInterfaceBIrpcId = irpcgen.ServiceId(0x219e8cf234013787)
+var _tvInterfaceBIrpcId = irpcgen.ServiceId(0x09fe62529c0f5c0c)
+
+var _tvInterfaceBIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _tvInterfaceBIrpcId,
+	Name:      "tvInterfaceB",
+	Interface: "github.com/marben/irpc/cmd/irpc/test.tvInterfaceB",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "add", Params: []irpcgen.ParamDesc{{Name: "a", Type: "int"}, {Name: "b", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "int"}}, HasContext: false},
+	},
+}
 
 // tvInterfaceBIrpcService provides [tvInterfaceB] interface over irpc
 type tvInterfaceBIrpcService struct {
@@ -127,6 +150,11 @@ func (s *tvInterfaceBIrpcService) Id() irpcgen.ServiceId {
 	return _tvInterfaceBIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *tvInterfaceBIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_tvInterfaceBIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *tvInterfaceBIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -160,7 +188,7 @@ type tvInterfaceBIrpcClient struct {
 }
 
 func newTvInterfaceBIrpcClient(endpoint irpcgen.Endpoint) (*tvInterfaceBIrpcClient, error) {
-	if err := endpoint.RegisterClient(_tvInterfaceBIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_tvInterfaceBIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &tvInterfaceBIrpcClient{endpoint: endpoint}, nil
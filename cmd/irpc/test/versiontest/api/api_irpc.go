@@ -8,7 +8,16 @@ import (
 	"github.com/marben/irpc/irpcgen"
 )
 
-var _ApiIrpcId = irpcgen.ServiceId(0xe5942f51f44a5113)
+var _ApiIrpcId = irpcgen.ServiceId(0x362a396313555a78)
+
+var _ApiIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _ApiIrpcId,
+	Name:      "Api",
+	Interface: "github.com/marben/irpc/cmd/irpc/test/versiontest/api.Api",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "ApiVersion", Params: nil, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}, {Name: "", Type: "error"}}, HasContext: false},
+	},
+}
 
 // ApiIrpcService provides [Api] interface over irpc
 type ApiIrpcService struct {
@@ -27,6 +36,11 @@ func (s *ApiIrpcService) Id() irpcgen.ServiceId {
 	return _ApiIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *ApiIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_ApiIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *ApiIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -49,7 +63,7 @@ type ApiIrpcClient struct {
 }
 
 func NewApiIrpcClient(endpoint irpcgen.Endpoint) (*ApiIrpcClient, error) {
-	if err := endpoint.RegisterClient(_ApiIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_ApiIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &ApiIrpcClient{endpoint: endpoint}, nil
@@ -75,20 +89,7 @@ func (s _irpc_Api_ApiVersionResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncString(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type string: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -97,30 +98,8 @@ func (s *_irpc_Api_ApiVersionResp) Deserialize(d *irpcgen.Decoder) error {
 	if err := irpcgen.DecString(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type string: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_Api_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_Api_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_Api_impl) Error() string {
-	return i._Error_0_
-}
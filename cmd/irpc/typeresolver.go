@@ -159,9 +159,19 @@ func (tr typeResolver) loadRpcParamList(apiName string, list []*ast.Field) ([]rp
 		if err != nil {
 			return nil, fmt.Errorf("couldn't determine ast's %T field's type and value: %w", astExpr, err)
 		}
-		t, err := tr.newType(apiName, tv.Type, astExpr)
+		// streams are only allowed at the top level of method's params/results
+		var t Type
+		st, isStream, err := tr.newStreamType(apiName, tv.Type, astExpr)
 		if err != nil {
-			return nil, fmt.Errorf("newType(): %w", err)
+			return nil, fmt.Errorf("newStreamType(): %w", err)
+		}
+		if isStream {
+			t = st
+		} else {
+			t, err = tr.newType(apiName, tv.Type, astExpr)
+			if err != nil {
+				return nil, fmt.Errorf("newType(): %w", err)
+			}
 		}
 
 		if field.Names == nil {
@@ -215,6 +225,11 @@ func (tr typeResolver) newType(apiName string, t types.Type, astExpr ast.Expr) (
 		return newContextType(*ni), nil
 	}
 
+	// errors are transported with their registered codes and wrapped chains (see irpcgen.RegisterError)
+	if types.Identical(t, types.Universe.Lookup("error").Type()) {
+		return newDirectCallType("irpcgen.EncError", "irpcgen.DecError", "error", nil), nil
+	}
+
 	if types.Implements(t, tr.binMarshaler) && types.Implements(types.NewPointer(t), tr.binUnmarshaler) {
 		return tr.newBinaryMarshalerType(ni)
 	}
@@ -224,6 +239,8 @@ func (tr typeResolver) newType(apiName string, t types.Type, astExpr ast.Expr) (
 		return tr.newBasicType(ut, ni)
 	case *types.Slice:
 		return tr.newSliceType(apiName, ni, ut, utAst)
+	case *types.Array:
+		return tr.newArrayType(apiName, ni, ut, utAst)
 	case *types.Map: // todo: test maps using http.Header named map (doesn't have ast etc..)
 		return tr.newMapType(apiName, ni, ut, utAst)
 	case *types.Struct:
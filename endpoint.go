@@ -5,8 +5,12 @@ import (
 	"errors"
 	"fmt"
 	"io"
+	"iter"
+	"log/slog"
 	"net"
 	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/marben/irpc/irpcgen"
 )
@@ -19,12 +23,20 @@ var DefaultParallelWorkers = 3
 // It can be overridden for each endpoint with [WithParallelClientCalls] option
 var DefaultParallelClientCalls = DefaultParallelWorkers + 1
 
+// DefaultRegisterClientTimeout limits, how long [Endpoint.RegisterClient] waits for the peer to negotiate the service.
+// It can be overridden for each endpoint with [WithRegisterClientTimeout] option
+var DefaultRegisterClientTimeout = 10 * time.Second
+
 // Endpoint related errors
 var (
 	ErrEndpointClosed       = errors.New("irpc: endpoint is closed")
 	ErrEndpointClosedByPeer = errors.New("irpc: endpoint closed by peer")
-	ErrServiceNotFound      = errors.New("irpc: service not found") // todo: not used?
-	errProtocolError        = errors.New("protocol error")
+	ErrServiceNotFound      = errors.New("irpc: service not found")
+	ErrFunctionNotFound     = errors.New("irpc: function not found")
+	// ErrEndpointDraining is returned by calls, that were refused, because one of the endpoints is shutting down (see [Endpoint.Shutdown]).
+	// Such calls were not executed and can be safely retried with another endpoint.
+	ErrEndpointDraining = errors.New("irpc: endpoint is shutting down")
+	errProtocolError    = errors.New("protocol error")
 )
 
 // Endpoint represents one side of an active RPC connection.
@@ -45,19 +57,49 @@ type Endpoint struct {
 	services    map[irpcgen.ServiceId]irpcgen.Service
 	servicesMux sync.Mutex
 
+	// aliases we gave our peer for our services. guarded by servicesMux
+	serviceAliases map[uint64]irpcgen.ServiceId
+	serviceIdAlias map[irpcgen.ServiceId]uint64
+
+	// aliases our peer gave us for its services
+	peerServiceAliases map[irpcgen.ServiceId]uint64
+	// descriptions of peer's services our clients call
+	peerServiceDescs map[irpcgen.ServiceId]*irpcgen.ServiceDesc
+	peerServicesMux  sync.Mutex
+
 	// localAddr and remoteAddr are nil, when not set with Option
 	localAddr  net.Addr // our network address if available
 	remoteAddr net.Addr // peer network address if available
 
 	enc    *irpcgen.Encoder // encoder for writing messages to the connection
+	frameW *frameWriter     // splits encoded packets into frames
 	encMux sync.Mutex
 
-	dec *irpcgen.Decoder // decoder for reading messages from the connection
+	dec    *irpcgen.Decoder // decoder for reading messages from the connection
+	frameR *frameReader     // reassembles incoming frames into packets
 
 	ourPendingRequests *ourPendingRequestsLog
 
+	exec *executor // runs functions our peer requested
+
+	draining     atomic.Bool // we are shutting down and refuse new requests
+	peerDraining atomic.Bool // peer sent us goaway. we don't send it new requests
+
+	// closed once we received and accepted peer's handshake
+	handshakeDone chan struct{}
+	peer          PeerInfo // what peer announced in its handshake. valid after handshakeDone is closed
+
 	connCloser io.Closer // closes our connection
 
+	receivedPackets atomic.Uint64   // number of packets read from peer. keepalive uses it to tell a busy peer from a dead one
+	pongC           chan pingPacket // pongs from readLoop to keepalive
+	rtt             atomic.Int64    // round trip time measured by the last keepalive ping
+
+	// pings from peer, that we didn't answer yet. only the latest one is answered
+	pongM       sync.Mutex
+	pendingPong *pingPacket // nil, unless there is a ping to answer
+	sendingPong bool        // a goroutine is sending our pongs
+
 	closeOnce sync.Once
 
 	// context is Done() after the endpoint is closed
@@ -67,30 +109,77 @@ type Endpoint struct {
 	// some options - possibly move to a separate struct?
 	parallelWorkers     int // number of parallel workers servicing peer's requests
 	parallelClientCalls int // number of parallel calls we allow to our peer at the same time
+	streamWindow        int // number of stream items our peer can send us before we consume them
+	maxMessageLen       int // maximum length of a message we accept
+	panicPolicy         PanicPolicy
+	serviceNegotiation  bool // RegisterClient asks peer for the service
+	registerTimeout     time.Duration
+	abortCallOnCancel   bool // CallRemoteFunc returns without waiting for response once its context ends
+	hello               []byte
+	peerCheck           func(PeerInfo) error
+	clientInterceptors  []ClientInterceptor
+	serverInterceptors  []ServerInterceptor
+	keepaliveInterval   time.Duration // 0 disables keepalive
+	keepaliveTimeout    time.Duration
+	metrics             MetricsSink // nil if metrics are off
+	logger              *slog.Logger
+	slowCallThreshold   time.Duration // calls taking longer are logged. 0 disables the logging
 }
 
 // NewEndpoint creates and runs a new Endpoint with the given connection and options.
 // It immeditely starts a go routine servicing the communication.
 // Services provided by this endpoint can be added as with [WithEndpointServices] option, or can be registered later with [Endpoint.RegisterService] call
+//
+// NewEndpoint doesn't wait for the handshake. If it fails, the endpoint closes with [ErrHandshakeFailed] in its cause
+// and calls made meanwhile fail. Use [ConnectEndpoint] to learn about the failure right away.
 func NewEndpoint(conn io.ReadWriteCloser, opts ...EndpointOption) *Endpoint {
 	epCtx, endpointContextCancel := context.WithCancelCause(context.Background())
 
 	ep := &Endpoint{
 		services:            make(map[irpcgen.ServiceId]irpcgen.Service),
-		enc:                 irpcgen.NewEncoder(conn),
-		dec:                 irpcgen.NewDecoder(conn),
+		serviceAliases:      make(map[uint64]irpcgen.ServiceId),
+		serviceIdAlias:      make(map[irpcgen.ServiceId]uint64),
+		peerServiceAliases:  make(map[irpcgen.ServiceId]uint64),
+		peerServiceDescs:    make(map[irpcgen.ServiceId]*irpcgen.ServiceDesc),
+		handshakeDone:       make(chan struct{}),
+		pongC:               make(chan pingPacket, 1),
 		connCloser:          conn,
 		ctx:                 epCtx,
 		ctxCancel:           endpointContextCancel,
 		parallelWorkers:     DefaultParallelWorkers,
 		parallelClientCalls: DefaultParallelClientCalls,
+		streamWindow:        DefaultStreamWindow,
+		maxMessageLen:       DefaultMaxMessageLen,
+		panicPolicy:         DefaultPanicPolicy,
+		registerTimeout:     DefaultRegisterClientTimeout,
+		slowCallThreshold:   DefaultSlowCallThreshold,
 	}
 
 	for _, opt := range opts {
 		opt(ep)
 	}
+	ep.initLogger()
+
+	if ep.metrics != nil {
+		conn = meteredConn{ReadWriteCloser: conn, sink: ep.metrics}
+	}
+	ep.frameW = newFrameWriter(conn, maxFrameLen)
+	ep.enc = irpcgen.NewEncoder(ep.frameW)
+	ep.frameR = newFrameReader(conn, ep.maxMessageLen)
+	ep.dec = irpcgen.NewDecoder(ep.frameR)
 
 	ep.ourPendingRequests = newOurPendingRequestsLog(ep.parallelClientCalls)
+	ep.exec = newExecutor(epCtx, ep.parallelWorkers, ep.streamWindow, ep.panicPolicy, ep, ep)
+
+	// handshake must be the first thing we send. we don't wait for it here though
+	// (with synchronous connections like net.Pipe the peer might not be reading yet)
+	ep.encMux.Lock()
+	go func() {
+		defer ep.encMux.Unlock()
+		if err := ep.sendHandshake(); err != nil {
+			ep.handleIOError(fmt.Errorf("send handshake: %w", err))
+		}
+	}()
 
 	go func() {
 		ep.serve(epCtx)
@@ -108,11 +197,14 @@ func (e *Endpoint) Context() context.Context {
 }
 
 func (e *Endpoint) serve(ctx context.Context) {
-	exec := newExecutor(ctx, e.parallelWorkers)
+	exec := e.exec
 	readC := make(chan error, 1)
 	go func() {
 		readC <- e.readLoop(exec)
 	}()
+	if e.keepaliveInterval > 0 {
+		go e.keepalive(ctx)
+	}
 
 	var err error
 	select {
@@ -124,16 +216,40 @@ func (e *Endpoint) serve(ctx context.Context) {
 		e.terminate(errors.Join(ErrEndpointClosed, err))
 	}
 
-	// following 2 calls may fail for various reasons, but we want to be sure, they were made
-	e.serializePacket(packetHeader{typ: closingNowPacketType})
-	e.connCloser.Close()
+	// connection is closed by whoever canceled the context
+	e.logClose(context.Cause(ctx))
+}
+
+// closingNowTimeout limits, how long we try to tell the peer, that we are closing
+const closingNowTimeout = 100 * time.Millisecond
+
+// closeConn tells the peer, that we are closing the connection on purpose, and closes it.
+// otherwise peer could only tell our close from a crash by the way the connection ended
+// (closing a tcp connection with unread data resets it)
+// it runs in the background, so that closing never waits for a peer, that isn't reading
+func (e *Endpoint) closeConn() {
+	defer e.connCloser.Close()
+
+	// best effort. it also unblocks writers stuck on the unresponsive peer, that hold encMux
+	if wd, ok := e.connCloser.(interface{ SetWriteDeadline(time.Time) error }); ok {
+		wd.SetWriteDeadline(time.Now().Add(closingNowTimeout))
+	}
+
+	sentC := make(chan struct{})
+	go func() {
+		defer close(sentC)
+		e.serializePacket(packetHeader{typ: closingNowPacketType})
+	}()
+	select {
+	case <-sentC:
+	case <-time.After(closingNowTimeout):
+		// peer isn't reading. closing the connection unblocks the write
+	}
 }
 
 func (e *Endpoint) terminate(cause error) {
 	e.closeOnce.Do(func() {
-		// Always close transport first to unblock goroutines
-		_ = e.connCloser.Close()
-
+		go e.closeConn()
 		e.ctxCancel(cause)
 	})
 }
@@ -170,14 +286,182 @@ func (e *Endpoint) Close() error {
 	return nil
 }
 
-// RegisterClient registers client on remote endpoint - currently a no-op
+// shutdownPollInterval is how often Shutdown checks, whether all calls have finished
+const shutdownPollInterval = 10 * time.Millisecond
+
+// Shutdown gracefully closes the endpoint.
+//
+// It tells the peer to stop sending new requests and refuses those, that are already on the way, with [ErrEndpointDraining].
+// New calls to the peer fail with [ErrEndpointDraining] as well.
+// Shutdown then waits for the functions we execute for our peer and for our own calls to finish, and closes the endpoint.
+//
+// If ctx ends first, the endpoint is closed immediately and ctx's error is returned.
+// If the endpoint gets closed otherwise in the meantime, the cause is returned.
+func (e *Endpoint) Shutdown(ctx context.Context) error {
+	if cause := context.Cause(e.ctx); cause != nil {
+		return cause
+	}
+
+	if e.draining.CompareAndSwap(false, true) {
+		e.exec.drain()
+		if err := e.serializePacket(packetHeader{typ: goawayPacketType}); err != nil {
+			e.handleIOError(fmt.Errorf("send goaway: %w", err))
+			return context.Cause(e.ctx)
+		}
+	}
+
+	ticker := time.NewTicker(shutdownPollInterval)
+	defer ticker.Stop()
+	for {
+		if e.exec.idle() && e.ourPendingRequests.outstanding() == 0 {
+			e.terminate(ErrEndpointClosed)
+			return nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			e.terminate(ErrEndpointClosed)
+			return ctx.Err()
+		case <-e.ctx.Done():
+			return context.Cause(e.ctx)
+		}
+	}
+}
+
+// OutstandingCalls returns the number of our calls to the peer, that are in progress
+func (e *Endpoint) OutstandingCalls() int {
+	return e.ourPendingRequests.outstanding()
+}
+
+// acceptsCalls returns ErrEndpointDraining, if we or our peer are shutting down
+func (e *Endpoint) acceptsCalls() error {
+	if e.draining.Load() || e.peerDraining.Load() {
+		return ErrEndpointDraining
+	}
+	return nil
+}
+
+// RegisterClient registers client on remote endpoint.
+//
+// It is a no-op, unless the endpoint was created with [WithServiceNegotiation] option and the peer announced [FeatureServiceNegotiation].
+// With negotiation, it asks peer, whether it provides the service, and returns [ErrServiceNotFound] if it doesn't.
+// Peer also assigns the service a short alias, that is then sent with each request instead of full service id.
+// If the peer doesn't answer within [WithRegisterClientTimeout], it fails with [context.DeadlineExceeded].
 func (e *Endpoint) RegisterClient(serviceId irpcgen.ServiceId) error {
-	// currently a no-op
-	// we could use this call to negotiate shortcut for serviceId to further reduce service data
-	// we could also use this to make sure service is registered on remote ep. this would increase initial latency, so perhaps make it configurable?
+	if !e.serviceNegotiation {
+		return nil
+	}
+	if err := e.acceptsCalls(); err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(e.ctx, e.registerTimeout)
+	defer cancel()
+
+	if err := e.peerSupports(ctx, FeatureServiceNegotiation); err != nil {
+		if errors.Is(err, ErrFeatureNotSupported) {
+			// calls will identify the service by its full id
+			return nil
+		}
+		if cause := context.Cause(e.ctx); cause != nil {
+			return cause
+		}
+		return fmt.Errorf("wait for handshake: %w", err)
+	}
+
+	var resp registerClientResponse
+	pr, err := e.ourPendingRequests.addPendingRequest(ctx, &resp, nil, nil)
+	if err != nil {
+		if cause := context.Cause(e.ctx); cause != nil {
+			return cause
+		}
+		return fmt.Errorf("addPendingRequest(): %w", err)
+	}
+
+	header := packetHeader{typ: registerClientPacketType}
+	if err := e.serializePacket(header, registerClientPacket{ReqNum: pr.reqNum, ServiceId: serviceId}); err != nil {
+		e.ourPendingRequests.popPendingRequest(pr.reqNum)
+		if cause := context.Cause(e.ctx); cause != nil {
+			return cause
+		}
+		return err
+	}
+
+	select {
+	case err := <-pr.deserErrC:
+		if err != nil {
+			return err
+		}
+	case <-e.ctx.Done():
+		return context.Cause(e.ctx)
+	case <-ctx.Done():
+		// readLoop discards the late answer
+		if pr.abandon() {
+			return fmt.Errorf("peer didn't negotiate service %s: %w", serviceId, ctx.Err())
+		}
+		if err := <-pr.deserErrC; err != nil {
+			return err
+		}
+	}
+
+	e.peerServicesMux.Lock()
+	defer e.peerServicesMux.Unlock()
+	e.peerServiceAliases[serviceId] = resp.Alias
+
 	return nil
 }
 
+// RegisterDescribedClient registers client of described service on remote endpoint. See [Endpoint.RegisterClient].
+// The description names the called functions in [CallInfo] passed to client interceptors.
+//
+// RegisterDescribedClient implements [irpcgen.DescribedEndpoint]
+func (e *Endpoint) RegisterDescribedClient(desc *irpcgen.ServiceDesc) error {
+	e.peerServicesMux.Lock()
+	e.peerServiceDescs[desc.Id] = desc
+	e.peerServicesMux.Unlock()
+
+	return e.RegisterClient(desc.Id)
+}
+
+// peerCallInfo describes our call of peer's function
+func (e *Endpoint) peerCallInfo(serviceId irpcgen.ServiceId, funcId irpcgen.FuncId) CallInfo {
+	e.peerServicesMux.Lock()
+	desc := e.peerServiceDescs[serviceId]
+	e.peerServicesMux.Unlock()
+
+	return newCallInfo(serviceId, funcId, desc)
+}
+
+// peerServiceAlias returns 0 if peer didn't give us alias for the service
+func (e *Endpoint) peerServiceAlias(serviceId irpcgen.ServiceId) uint64 {
+	e.peerServicesMux.Lock()
+	defer e.peerServicesMux.Unlock()
+
+	return e.peerServiceAliases[serviceId]
+}
+
+// serviceAlias returns alias of our service for our peer. assigns new alias on first call for each service
+func (e *Endpoint) serviceAlias(serviceId irpcgen.ServiceId) uint64 {
+	e.servicesMux.Lock()
+	defer e.servicesMux.Unlock()
+
+	if alias, found := e.serviceIdAlias[serviceId]; found {
+		return alias
+	}
+	alias := uint64(len(e.serviceAliases) + 1) // 0 means no alias
+	e.serviceAliases[alias] = serviceId
+	e.serviceIdAlias[serviceId] = alias
+	return alias
+}
+
+func (e *Endpoint) aliasedServiceId(alias uint64) (sid irpcgen.ServiceId, found bool) {
+	e.servicesMux.Lock()
+	defer e.servicesMux.Unlock()
+
+	sid, found = e.serviceAliases[alias]
+	return sid, found
+}
+
 // getService returns false if id was not found,
 func (e *Endpoint) getService(sid irpcgen.ServiceId) (s irpcgen.Service, found bool) {
 	e.servicesMux.Lock()
@@ -187,23 +471,54 @@ func (e *Endpoint) getService(sid irpcgen.ServiceId) (s irpcgen.Service, found b
 	return s, found
 }
 
-func (e *Endpoint) sendRpcRequest(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, respData irpcgen.Deserializable) (ourPendingRequest, error) {
-	pr, err := e.ourPendingRequests.addPendingRequest(ctx, respData)
+// if reqData is [irpcgen.StreamSerializable], its items are sent in a separate goroutine after the request
+func (e *Endpoint) sendRpcRequest(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, respData irpcgen.Deserializable, stream *itemStream) (ourPendingRequest, error) {
+	if err := e.acceptsCalls(); err != nil {
+		return ourPendingRequest{}, err
+	}
+	paramStream, withParamStream := reqData.(irpcgen.StreamSerializable)
+	if stream != nil || withParamStream {
+		if err := e.peerSupports(ctx, FeatureStreaming); err != nil {
+			return ourPendingRequest{}, err
+		}
+	}
+
+	var senderCtx context.Context
+	var stopParamStream context.CancelCauseFunc
+	if withParamStream {
+		senderCtx, stopParamStream = context.WithCancelCause(ctx)
+	}
+
+	pr, err := e.ourPendingRequests.addPendingRequest(ctx, respData, stream, stopParamStream)
 	if err != nil {
+		if stopParamStream != nil {
+			stopParamStream(nil)
+		}
 		return ourPendingRequest{}, fmt.Errorf("addPendingRequest(): %w", err)
 	}
 
 	header := packetHeader{
 		typ: rpcRequestPacketType,
 	}
+	if withParamStream {
+		header.typ = paramStreamRequestPacketType
+	}
 
 	requestDef := requestPacket{
-		ReqNum:    pr.reqNum,
-		ServiceId: serviceId,
-		FuncId:    funcId,
+		ReqNum:       pr.reqNum,
+		ServiceAlias: e.peerServiceAlias(serviceId),
+		ServiceId:    serviceId,
+		FuncId:       funcId,
+		Metadata:     outgoingMetadata(ctx),
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		requestDef.Deadline = deadline
 	}
 
 	if err := e.serializePacket(header, requestDef, reqData); err != nil {
+		if stopParamStream != nil {
+			stopParamStream(nil)
+		}
 		_, popErr := e.ourPendingRequests.popPendingRequest(pr.reqNum)
 		if popErr != nil {
 			panic(fmt.Errorf("failed to pop a just added pending request: %w", popErr))
@@ -211,9 +526,46 @@ func (e *Endpoint) sendRpcRequest(ctx context.Context, serviceId irpcgen.Service
 		return ourPendingRequest{}, err
 	}
 
+	if sent, ok := ctx.Value(requestSentKey{}).(*atomic.Bool); ok {
+		sent.Store(true)
+	}
+
+	if withParamStream {
+		go e.sendParamStream(senderCtx, pr, paramStream)
+	}
+
 	return pr, nil
 }
 
+// sendParamStream sends items of request's streamed parameter, as long as peer gives us credit.
+// it stops once the items run out, the call's context ends, or the response arrives
+func (e *Endpoint) sendParamStream(ctx context.Context, pr ourPendingRequest, stream irpcgen.StreamSerializable) {
+	defer e.ourPendingRequests.finish(pr)
+	defer pr.stopParamStream(nil)
+	stopAfterFunc := context.AfterFunc(e.ctx, func() { pr.stopParamStream(context.Cause(e.ctx)) })
+	defer stopAfterFunc()
+
+	stream.SendItems(ctx, func(item irpcgen.Serializable) error {
+		if err := pr.paramCredits.take(ctx); err != nil {
+			return err
+		}
+		header := packetHeader{typ: paramItemPacketType}
+		if err := e.serializePacket(header, paramItemPacket{ReqNum: pr.reqNum}, item); err != nil {
+			return fmt.Errorf("failed to serialize param item to connection: %w", err)
+		}
+		return nil
+	})
+
+	// peer keeps the stream open until we end it. even if it already responded
+	if e.ctx.Err() != nil {
+		return
+	}
+	header := packetHeader{typ: paramEndPacketType}
+	if err := e.serializePacket(header, paramEndPacket{ReqNum: pr.reqNum}); err != nil {
+		e.handleIOError(err)
+	}
+}
+
 func (e *Endpoint) sendRequestContextCancelation(req reqNumT, cause error) error {
 	header := packetHeader{typ: ctxEndPacketType}
 	ctxEndDef := ctxEndPacket{
@@ -228,9 +580,10 @@ func (e *Endpoint) sendRequestContextCancelation(req reqNumT, cause error) error
 	return nil
 }
 
-func (e *Endpoint) sendResponse(reqNum reqNumT, respData irpcgen.Serializable) error {
+func (e *Endpoint) sendResponse(reqNum reqNumT, respData irpcgen.Serializable, trailer Metadata) error {
 	resp := responsePacket{
-		ReqNum: reqNum,
+		ReqNum:  reqNum,
+		Trailer: trailer,
 	}
 
 	header := packetHeader{
@@ -244,28 +597,77 @@ func (e *Endpoint) sendResponse(reqNum reqNumT, respData irpcgen.Serializable) e
 	return nil
 }
 
+func (e *Endpoint) sendErrorResponse(reqNum reqNumT, errResp errorResponsePacket) error {
+	header := packetHeader{typ: errorResponsePacketType}
+	if err := e.serializePacket(header, errResp); err != nil {
+		return fmt.Errorf("failed to serialize error response to connection: %w", err)
+	}
+	return nil
+}
+
+func (e *Endpoint) sendStreamItem(reqNum reqNumT, item irpcgen.Serializable) error {
+	header := packetHeader{typ: streamItemPacketType}
+	if err := e.serializePacket(header, streamItemPacket{ReqNum: reqNum}, item); err != nil {
+		return fmt.Errorf("failed to serialize stream item to connection: %w", err)
+	}
+	return nil
+}
+
+func (e *Endpoint) sendStreamCredit(reqNum reqNumT, credit int) error {
+	header := packetHeader{typ: streamCreditPacketType}
+	return e.serializePacket(header, streamCreditPacket{ReqNum: reqNum, Credit: uint64(credit)})
+}
+
+func (e *Endpoint) sendParamCredit(reqNum reqNumT, credit int) error {
+	header := packetHeader{typ: paramCreditPacketType}
+	return e.serializePacket(header, streamCreditPacket{ReqNum: reqNum, Credit: uint64(credit)})
+}
+
 func (e *Endpoint) serializePacket(data ...irpcgen.Serializable) error {
 	e.encMux.Lock()
 	defer e.encMux.Unlock()
 
+	return writeMessage(e.enc, e.frameW, data...)
+}
+
+// writeMessage serializes data as a single message
+func writeMessage(enc *irpcgen.Encoder, frameW *frameWriter, data ...irpcgen.Serializable) error {
 	for _, d := range data {
-		if err := d.Serialize(e.enc); err != nil {
+		if err := d.Serialize(enc); err != nil {
 			return fmt.Errorf("data.Serialize(): %w", err)
 		}
 	}
 
-	if err := e.enc.Flush(); err != nil {
+	if err := enc.Flush(); err != nil {
 		return fmt.Errorf("encoder.Flush(): %w", err)
 	}
 
+	if err := frameW.endMessage(); err != nil {
+		return fmt.Errorf("frameWriter.endMessage(): %w", err)
+	}
+
 	return nil
 }
 
 // CallRemoteFunc invokes a function on the peer Endpoint.
 //
 // CallRemoteFunc implements [irpcgen.Service]
-func (e *Endpoint) CallRemoteFunc(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, respData irpcgen.Deserializable) error {
-	pendingReq, err := e.sendRpcRequest(ctx, serviceId, funcId, reqData, respData)
+func (e *Endpoint) CallRemoteFunc(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, respData irpcgen.Deserializable) (err error) {
+	if len(e.clientInterceptors) == 0 && !e.measuresCalls() {
+		return e.callRemoteFunc(ctx, CallInfo{ServiceId: serviceId, FuncId: funcId}, reqData, respData)
+	}
+	call := e.peerCallInfo(serviceId, funcId)
+	if e.measuresCalls() {
+		done := e.measureClientCall(ctx, call)
+		defer func() { done(err, respData) }()
+	}
+	invoke := chainClientInterceptors(e.clientInterceptors, e.callRemoteFunc)
+	return invoke(ctx, call, reqData, respData)
+}
+
+// callRemoteFunc makes the call without interceptors
+func (e *Endpoint) callRemoteFunc(ctx context.Context, call CallInfo, reqData irpcgen.Serializable, respData irpcgen.Deserializable) error {
+	pendingReq, err := e.sendRpcRequest(ctx, call.ServiceId, call.FuncId, reqData, respData, nil)
 	if err != nil {
 		// check if endpoint was closed
 		if cause := context.Cause(e.ctx); cause != nil {
@@ -292,6 +694,12 @@ func (e *Endpoint) CallRemoteFunc(ctx context.Context, serviceId irpcgen.Service
 			return context.Cause(e.ctx)
 		}
 
+		// readLoop discards the response once it arrives. only then is the request number reused
+		// if the response is already being received, we just wait for it
+		if e.abortOnCancel(ctx) && pendingReq.abandon() {
+			return context.Cause(ctx)
+		}
+
 		select {
 		case err := <-pendingReq.deserErrC:
 			return err
@@ -301,6 +709,122 @@ func (e *Endpoint) CallRemoteFunc(ctx context.Context, serviceId irpcgen.Service
 	}
 }
 
+// CallRemoteStream invokes a server-streaming function on the peer Endpoint.
+//
+// The call is made once the returned sequence is iterated. Items are yielded as they arrive.
+// Peer is only allowed to send as many items ahead, as is our stream window (see [WithStreamWindow]),
+// so a slow consumer slows down the producer, rather than the whole connection.
+// Stopping the iteration early cancels the call on the peer's side.
+//
+// CallRemoteStream implements [irpcgen.Endpoint]
+func (e *Endpoint) CallRemoteStream(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, newItem func() irpcgen.Deserializable, respData irpcgen.Deserializable) iter.Seq2[irpcgen.Deserializable, error] {
+	return func(yield func(irpcgen.Deserializable, error) bool) {
+		// consumer may stop iterating early. we must not yield to it afterwards
+		stopped := false
+		consume := func(item irpcgen.Deserializable) bool {
+			stopped = stopped || !yield(item, nil)
+			return !stopped
+		}
+
+		var err error
+		if len(e.clientInterceptors) == 0 && !e.measuresCalls() {
+			err = e.callRemoteStream(ctx, CallInfo{ServiceId: serviceId, FuncId: funcId}, reqData, newItem, respData, consume)
+		} else {
+			// interceptors wrap the whole stream, until its last item is consumed
+			call := e.peerCallInfo(serviceId, funcId)
+			if e.measuresCalls() {
+				done := e.measureClientCall(ctx, call)
+				defer func() { done(err, respData) }()
+			}
+			invoke := chainClientInterceptors(e.clientInterceptors, func(ctx context.Context, call CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable) error {
+				return e.callRemoteStream(ctx, call, req, newItem, resp, consume)
+			})
+			err = invoke(ctx, call, reqData, respData)
+		}
+
+		if err != nil && !stopped {
+			yield(nil, err)
+		}
+	}
+}
+
+// callRemoteStream makes the streaming call without interceptors. items are handed over to consume, until it returns false
+func (e *Endpoint) callRemoteStream(ctx context.Context, call CallInfo, reqData irpcgen.Serializable, newItem func() irpcgen.Deserializable, respData irpcgen.Deserializable, consume func(irpcgen.Deserializable) bool) error {
+	stream := newItemStream(newItem, e.streamWindow)
+	pendingReq, err := e.sendRpcRequest(ctx, call.ServiceId, call.FuncId, reqData, respData, stream)
+	if err != nil {
+		// check if endpoint was closed
+		if cause := context.Cause(e.ctx); cause != nil {
+			return cause
+		}
+		return err
+	}
+
+	streamEnded := false
+	defer func() {
+		if !streamEnded {
+			// our caller stopped iterating. we ask peer to stop producing
+			// and let the readLoop discard anything that is already on the way
+			stream.abandon()
+			if err := e.sendRequestContextCancelation(pendingReq.reqNum, errStreamAbandoned); err != nil {
+				e.handleIOError(err)
+			}
+		}
+		e.ourPendingRequests.finish(pendingReq)
+	}()
+
+	// peer can't send anything until we give it credit
+	if err := e.sendStreamCredit(pendingReq.reqNum, e.streamWindow); err != nil {
+		e.handleIOError(err)
+		streamEnded = true
+		return context.Cause(e.ctx)
+	}
+
+	// we return credit in batches to reduce the traffic
+	creditBatch := max(e.streamWindow/2, 1)
+	consumed := 0
+	ctxDone := ctx.Done()
+	for {
+		select {
+		case item, ok := <-stream.itemC:
+			if !ok {
+				// final response has arrived
+				streamEnded = true
+				return <-pendingReq.deserErrC
+			}
+
+			consumed++
+			if consumed >= creditBatch {
+				if err := e.sendStreamCredit(pendingReq.reqNum, consumed); err != nil {
+					e.handleIOError(err)
+					streamEnded = true
+					return context.Cause(e.ctx)
+				}
+				consumed = 0
+			}
+
+			if !consume(item) {
+				return nil
+			}
+
+		// global endpoint's context ended
+		case <-e.ctx.Done():
+			streamEnded = true
+			return context.Cause(e.ctx)
+
+		// the client provided context expired
+		case <-ctxDone:
+			// we notify the remote endpoint and keep receiving until the stream ends
+			if err := e.sendRequestContextCancelation(pendingReq.reqNum, context.Cause(ctx)); err != nil {
+				e.handleIOError(err)
+				streamEnded = true
+				return context.Cause(e.ctx)
+			}
+			ctxDone = nil
+		}
+	}
+}
+
 func (e *Endpoint) RegisterService(services ...irpcgen.Service) {
 	e.servicesMux.Lock()
 	defer e.servicesMux.Unlock()
@@ -320,27 +844,59 @@ func (e *Endpoint) RemoteAddr() net.Addr {
 
 // processRequest decodes the request and runs the function in separate goroutine
 // blocks until worker slot is available
-func (e *Endpoint) processRequest(dec *irpcgen.Decoder, exec *executor) error {
+// withParamStream means, that the request is followed by items of a streamed parameter
+func (e *Endpoint) processRequest(dec *irpcgen.Decoder, exec *executor, withParamStream bool) error {
 	var req requestPacket
 	if err := req.Deserialize(dec); err != nil {
 		return fmt.Errorf("read request packet:%w", err)
 	}
 
+	if req.ServiceAlias != 0 {
+		sid, found := e.aliasedServiceId(req.ServiceAlias)
+		if !found {
+			return errors.Join(errProtocolError, fmt.Errorf("unknown service alias %d", req.ServiceAlias))
+		}
+		req.ServiceId = sid
+	}
+
+	// we cannot decode arguments of unknown function. we skip them and fail just this one call
 	service, found := e.getService(req.ServiceId)
 	if !found {
-		return errors.Join(errProtocolError, fmt.Errorf("getService(): %w", ErrServiceNotFound))
+		e.frameR.discardMessage()
+		errResp := errorResponsePacket{ReqNum: req.ReqNum, Kind: serviceNotFoundErrorKind, Msg: fmt.Sprintf("service %s is not registered", req.ServiceId)}
+		if err := exec.failRequest(req.ReqNum, errResp, withParamStream); err != nil {
+			return fmt.Errorf("fail request: %w", err)
+		}
+		return nil
 	}
 
 	argDeser, err := service.GetFuncCall(irpcgen.FuncId(req.FuncId))
 	if err != nil {
-		return errors.Join(errProtocolError, fmt.Errorf("GetFuncCall() %v: %w", req, err))
+		e.frameR.discardMessage()
+		errResp := errorResponsePacket{ReqNum: req.ReqNum, Kind: funcNotFoundErrorKind, Msg: err.Error()}
+		if err := exec.failRequest(req.ReqNum, errResp, withParamStream); err != nil {
+			return fmt.Errorf("fail request: %w", err)
+		}
+		return nil
 	}
 	funcExec, err := argDeser(dec)
 	if err != nil {
 		return fmt.Errorf("argDeserialize: %w", err)
 	}
 
-	err = exec.runServiceWorker(req.ReqNum, funcExec, e.sendResponse)
+	var handler ServerHandler = func(ctx context.Context) (irpcgen.Serializable, error) {
+		return funcExec(ctx), nil
+	}
+	call := CallInfo{ServiceId: req.ServiceId, FuncId: req.FuncId}
+	if len(e.serverInterceptors) > 0 || e.measuresCalls() {
+		var desc *irpcgen.ServiceDesc
+		if ds, ok := service.(irpcgen.DescribedService); ok {
+			desc = ds.Descriptor()
+		}
+		call = newCallInfo(req.ServiceId, req.FuncId, desc)
+	}
+
+	err = exec.runServiceWorker(req, &call, handler, e.serverInterceptors, withParamStream)
 	if err != nil {
 		return fmt.Errorf("new worker: %w", err)
 	}
@@ -348,18 +904,66 @@ func (e *Endpoint) processRequest(dec *irpcgen.Decoder, exec *executor) error {
 	return nil
 }
 
+// processRegisterClient responds to peer's RegisterClient() call with an alias of our service
+func (e *Endpoint) processRegisterClient(dec *irpcgen.Decoder, exec *executor) error {
+	var reg registerClientPacket
+	if err := reg.Deserialize(dec); err != nil {
+		return fmt.Errorf("read register client packet: %w", err)
+	}
+
+	if _, found := e.getService(reg.ServiceId); !found {
+		errResp := errorResponsePacket{ReqNum: reg.ReqNum, Kind: serviceNotFoundErrorKind, Msg: fmt.Sprintf("service %s is not registered", reg.ServiceId)}
+		if err := exec.failRequest(reg.ReqNum, errResp, false); err != nil {
+			return fmt.Errorf("fail request: %w", err)
+		}
+		return nil
+	}
+
+	alias := e.serviceAlias(reg.ServiceId)
+	respond := func(context.Context) (irpcgen.Serializable, error) {
+		return registerClientResponse{Alias: alias}, nil
+	}
+	if err := exec.runServiceWorker(requestPacket{ReqNum: reg.ReqNum}, nil, respond, nil, false); err != nil {
+		return fmt.Errorf("new worker: %w", err)
+	}
+	return nil
+}
+
+// completeRequest hands the outcome of our request over to the caller
+// pr has to be already removed from pending requests
+func (e *Endpoint) completeRequest(pr ourPendingRequest, err error) {
+	pr.deserErrC <- err
+	if pr.stopParamStream != nil {
+		pr.stopParamStream(nil)
+	}
+	if pr.stream != nil {
+		close(pr.stream.itemC)
+	}
+	e.ourPendingRequests.finish(pr)
+}
+
 // readLoop is the main incoming messages processing loop
 func (e *Endpoint) readLoop(exec *executor) error {
+	if err := e.readHandshake(); err != nil {
+		return err
+	}
+
 	for {
 		var h packetHeader
 		if err := h.Deserialize(e.dec); err != nil {
 			return fmt.Errorf("read header: %w", err)
 		}
+		e.receivedPackets.Add(1)
 
 		switch h.typ {
 		// peer requested us to run a function
 		case rpcRequestPacketType:
-			if err := e.processRequest(e.dec, exec); err != nil {
+			if err := e.processRequest(e.dec, exec, false); err != nil {
+				return fmt.Errorf("processRequest: %w", err)
+			}
+		// peer requested us to run a function with streamed parameter
+		case paramStreamRequestPacketType:
+			if err := e.processRequest(e.dec, exec, true); err != nil {
 				return fmt.Errorf("processRequest: %w", err)
 			}
 			// response from a function we requested from peer
@@ -368,17 +972,139 @@ func (e *Endpoint) readLoop(exec *executor) error {
 			if err := resp.Deserialize(e.dec); err != nil {
 				return fmt.Errorf("failed to read response data:%w", err)
 			}
-			pr, err := e.ourPendingRequests.popPendingRequest(resp.ReqNum)
+			pr, err := e.ourPendingRequests.removePendingRequest(resp.ReqNum)
+			if err != nil {
+				return fmt.Errorf("request not found: %w", err)
+			}
+
+			// caller might have given up on the response
+			if !pr.answer() {
+				e.frameR.discardMessage()
+				e.completeRequest(pr, nil)
+				continue
+			}
+
+			if pr.trailer != nil {
+				*pr.trailer = resp.Trailer
+			}
+			deserErr := pr.resp.Deserialize(e.dec)
+			if deserErr != nil {
+				// thanks to framing, we can skip the broken response and carry on
+				e.frameR.discardMessage()
+			}
+			e.completeRequest(pr, deserErr)
+
+		// function we requested from peer failed without response
+		case errorResponsePacketType:
+			var errResp errorResponsePacket
+			if err := errResp.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("failed to read error response: %w", err)
+			}
+			pr, err := e.ourPendingRequests.removePendingRequest(errResp.ReqNum)
+			if err != nil {
+				return fmt.Errorf("request not found: %w", err)
+			}
+			e.completeRequest(pr, errResp.err())
+
+		// one item of a stream we requested from peer
+		case streamItemPacketType:
+			var item streamItemPacket
+			if err := item.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("failed to read stream item packet: %w", err)
+			}
+			pr, err := e.ourPendingRequests.getPendingRequest(item.ReqNum)
 			if err != nil {
 				return fmt.Errorf("request not found: %w", err)
 			}
+			if pr.stream == nil {
+				return errors.Join(errProtocolError, fmt.Errorf("stream item for non-streaming request %d", item.ReqNum))
+			}
+			if err := pr.stream.push(e.dec); err != nil {
+				return fmt.Errorf("stream item for request %d: %w", item.ReqNum, err)
+			}
+
+		// peer is ready to receive more items of a stream we are sending
+		case streamCreditPacketType:
+			var credit streamCreditPacket
+			if err := credit.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("failed to deserialize stream credit: %w", err)
+			}
+			exec.grantStreamCredit(credit.ReqNum, credit.Credit)
 
-			pr.deserErrC <- pr.resp.Deserialize(e.dec)
+		// one item of a streamed parameter of a function we execute
+		case paramItemPacketType:
+			var item paramItemPacket
+			if err := item.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("failed to read param item packet: %w", err)
+			}
+			if err := exec.pushParamItem(item.ReqNum, e.dec); err != nil {
+				return fmt.Errorf("param item for request %d: %w", item.ReqNum, err)
+			}
+
+		// peer sent all items of a streamed parameter
+		case paramEndPacketType:
+			var end paramEndPacket
+			if err := end.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("failed to read param end packet: %w", err)
+			}
+			if err := exec.endParamStream(end.ReqNum); err != nil {
+				return err
+			}
+
+		// peer is ready to receive more items of a streamed parameter we are sending
+		case paramCreditPacketType:
+			var credit streamCreditPacket
+			if err := credit.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("failed to deserialize param credit: %w", err)
+			}
+			pr, err := e.ourPendingRequests.getPendingRequest(credit.ReqNum)
+			if err != nil {
+				// the request may have already been responded
+				continue
+			}
+			if pr.paramCredits == nil {
+				return errors.Join(errProtocolError, fmt.Errorf("param credit for request %d without streamed parameter", credit.ReqNum))
+			}
+			pr.paramCredits.grant(credit.Credit)
+
+		// peer asks, whether we provide a service
+		case registerClientPacketType:
+			if err := e.processRegisterClient(e.dec, exec); err != nil {
+				return fmt.Errorf("processRegisterClient: %w", err)
+			}
+
+		// peer checks we are alive
+		case pingPacketType:
+			var ping pingPacket
+			if err := ping.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("failed to read ping: %w", err)
+			}
+			e.answerPing(ping)
+
+		// peer answered our keepalive ping
+		case pongPacketType:
+			var pong pingPacket
+			if err := pong.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("failed to read pong: %w", err)
+			}
+			e.receivedPong(pong)
+
+		// peer is shutting down. it will refuse our new requests
+		case goawayPacketType:
+			e.peerDraining.Store(true)
 
 		// peer is closing
 		case closingNowPacketType:
 			e.terminate(ErrEndpointClosedByPeer)
 
+		// server refused our connection
+		case rejectPacketType:
+			var reject rejectPacket
+			if err := reject.Deserialize(e.dec); err != nil {
+				return fmt.Errorf("read reject packet: %w", err)
+			}
+			return fmt.Errorf("%w: %s", ErrConnectionRejected, reject.Reason)
+
 		// peer informed us that context of some function it requested us to execute has expired
 		// we cancel corresponding worker's context.
 		// this doesn't mean the work will stop immediately
@@ -394,6 +1120,11 @@ func (e *Endpoint) readLoop(exec *executor) error {
 		default:
 			return fmt.Errorf("unexpected packet type: %+v", h.typ)
 		}
+
+		// each packet is a message of its own
+		if unread := e.frameR.Len(); unread != 0 {
+			return errors.Join(errProtocolError, fmt.Errorf("%d unread bytes after packet %+v", unread, h.typ))
+		}
 	}
 }
 
@@ -427,3 +1158,86 @@ func WithParallelClientCalls(parallelClientCalls int) EndpointOption {
 		ep.parallelClientCalls = parallelClientCalls
 	}
 }
+
+// WithMaxMessageLen sets the maximum length of a message (a packet with its data), that we accept from our peer.
+// Peer sending a longer message is treated as a protocol error and the endpoint is closed.
+// Messages are sent as frames of limited size, so the limit may be bigger (or smaller) than a frame.
+// Both endpoints should use the same value.
+func WithMaxMessageLen(maxMessageLen int) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.maxMessageLen = maxMessageLen
+	}
+}
+
+// WithServiceNegotiation makes [Endpoint.RegisterClient] check with the peer, that it provides the service.
+// Generated client constructors then fail with [ErrServiceNotFound] right away, rather than on the first call.
+// Negotiated services are identified by a short alias in each request, instead of the full service id.
+func WithServiceNegotiation() EndpointOption {
+	return func(ep *Endpoint) {
+		ep.serviceNegotiation = true
+	}
+}
+
+// WithRegisterClientTimeout sets, how long [Endpoint.RegisterClient] waits for the peer to negotiate the service.
+// Default is [DefaultRegisterClientTimeout].
+func WithRegisterClientTimeout(timeout time.Duration) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.registerTimeout = timeout
+	}
+}
+
+// WithAbortOnCancel makes [Endpoint.CallRemoteFunc] return [context.Cause] of its context as soon as the context ends,
+// instead of waiting for the peer's function to finish. The late response is discarded.
+// It can be overridden for individual calls with [ContextWithAbortOnCancel].
+func WithAbortOnCancel() EndpointOption {
+	return func(ep *Endpoint) {
+		ep.abortCallOnCancel = true
+	}
+}
+
+// WithHello sets an application payload sent to the peer in the connection handshake.
+// Peer can access it with [Endpoint.Peer] or check it with [WithPeerCheck] option.
+func WithHello(payload []byte) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.hello = payload
+	}
+}
+
+// WithPeerCheck sets a function checking peer's handshake.
+// If the function returns an error, the endpoint is closed with [ErrHandshakeFailed].
+// Peer's endpoint is closed with [ErrConnectionRejected], followed by the error's message.
+func WithPeerCheck(check func(PeerInfo) error) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.peerCheck = check
+	}
+}
+
+// WithPanicPolicy sets, what happens when a service function we execute for our peer panics. See [PanicPolicy].
+func WithPanicPolicy(policy PanicPolicy) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.panicPolicy = policy
+	}
+}
+
+// WithStreamWindow sets the number of stream items our peer can send us ahead, before we consume them.
+func WithStreamWindow(streamWindow int) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.streamWindow = streamWindow
+	}
+}
+
+type abortOnCancelKey struct{}
+
+// ContextWithAbortOnCancel returns a context, that determines whether calls made with it return as soon as the context ends.
+// It overrides the endpoint's [WithAbortOnCancel] option.
+func ContextWithAbortOnCancel(ctx context.Context, abort bool) context.Context {
+	return context.WithValue(ctx, abortOnCancelKey{}, abort)
+}
+
+// abortOnCancel determines, whether call with given context returns as soon as the context ends
+func (e *Endpoint) abortOnCancel(ctx context.Context) bool {
+	if abort, ok := ctx.Value(abortOnCancelKey{}).(bool); ok {
+		return abort
+	}
+	return e.abortCallOnCancel
+}
@@ -3,13 +3,18 @@ package irpc_test
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"net"
+	"slices"
+	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/marben/irpc"
 	"github.com/marben/irpc/cmd/irpc/test/testtools"
+	"github.com/marben/irpc/irpcgen"
 )
 
 func init() {
@@ -576,6 +581,39 @@ func TestClientEndpointClosingEndsRunningWorkers(t *testing.T) {
 	}
 }
 
+// peer, whose connection was reset, didn't close it on purpose
+func TestConnectionResetIsNotClosedByPeer(t *testing.T) {
+	conn1, conn2, err := testtools.CreateLocalTcpConnPipe()
+	if err != nil {
+		t.Fatalf("new tcp pipe: %+v", err)
+	}
+	ep := irpc.NewEndpoint(conn1)
+	defer ep.Close()
+
+	// peer goes away without a word
+	conn2.(*net.TCPConn).SetLinger(0)
+	conn2.Close()
+
+	<-ep.Context().Done()
+	if err := context.Cause(ep.Context()); errors.Is(err, irpc.ErrEndpointClosedByPeer) || !errors.Is(err, irpc.ErrEndpointClosed) {
+		t.Fatalf("unexpected cause: %v", err)
+	}
+}
+
+// closing must not wait for a peer, that doesn't read
+func TestCloseDoesntWaitForUnresponsivePeer(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	ep := irpc.NewEndpoint(c1)
+
+	start := time.Now()
+	ep.Close()
+	if d := time.Since(start); d > 50*time.Millisecond {
+		t.Fatalf("Close() took %v", d)
+	}
+	<-ep.Context().Done()
+}
+
 // blocks available workers and then makes one more call, waiting for mutex to write/read
 // makes sure expiration of client side context actually quits the mutex wait
 func TestWaitingClientCallGetsCanceledOnContextTimeout(t *testing.T) {
@@ -625,7 +663,8 @@ func TestWaitingClientCallGetsCanceledOnContextTimeout(t *testing.T) {
 	// do the one call, that should not get to service and should time out on our side
 	extraCallErr := make(chan struct{})
 	go func() {
-		timeoutCtx, _ := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		timeoutCtx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
 		_, err := client.DivCtxErr(timeoutCtx, 8, 2)
 		if !errors.Is(err, context.DeadlineExceeded) {
 			log.Fatalf("unexpected error from blocked function: %+v", err)
@@ -653,6 +692,174 @@ func TestWaitingClientCallGetsCanceledOnContextTimeout(t *testing.T) {
 	}
 }
 
+func TestAbortOnCancel(t *testing.T) {
+	// single request number, so that we see when it gets released
+	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints(irpc.WithAbortOnCancel(), irpc.WithParallelClientCalls(1))
+	if err != nil {
+		t.Fatalf("create local tcp endpoints: %+v", err)
+	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
+
+	service := testtools.NewTestServiceImpl(0)
+	unblockC := make(chan struct{})
+	service.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		// service ignores the cancelation
+		<-unblockC
+		return a / b, nil
+	}
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(service))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("new client: %+v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.DivCtxErr(ctx, 8, 2); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected DeadlineExceeded, got: %v", err)
+	}
+
+	// request number is only reused after the late response arrives
+	resC := make(chan int)
+	go func() {
+		res, err := client.DivCtxErr(context.Background(), 6, 2)
+		if err != nil {
+			log.Fatalf("DivCtxErr(): %v", err)
+		}
+		resC <- res
+	}()
+	select {
+	case <-resC:
+		t.Fatalf("call finished before the late response arrived")
+	case <-time.After(20 * time.Millisecond):
+	}
+	unblockC <- struct{}{} // late response of the aborted call
+	unblockC <- struct{}{}
+	if res := <-resC; res != 3 {
+		t.Fatalf("unexpected result: %d", res)
+	}
+
+	// per call override waits for the response
+	ctx, cancel = context.WithTimeout(irpc.ContextWithAbortOnCancel(context.Background(), false), 20*time.Millisecond)
+	defer cancel()
+	go func() {
+		<-ctx.Done()
+		unblockC <- struct{}{}
+	}()
+	if res, err := client.DivCtxErr(ctx, 8, 2); err != nil || res != 4 {
+		t.Fatalf("DivCtxErr(): %d, %v", res, err)
+	}
+}
+
+func TestShutdownDrainsRunningCalls(t *testing.T) {
+	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create local tcp endpoints: %+v", err)
+	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
+
+	service := testtools.NewTestServiceImpl(0)
+	startedC := make(chan struct{})
+	unblockC := make(chan struct{})
+	service.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		if a == 8 {
+			close(startedC)
+			<-unblockC
+		}
+		return a / b, nil
+	}
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(service))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("new client: %+v", err)
+	}
+
+	type result struct {
+		res int
+		err error
+	}
+	runningC := make(chan result)
+	go func() {
+		res, err := client.DivCtxErr(context.Background(), 8, 2)
+		runningC <- result{res, err}
+	}()
+	<-startedC
+
+	shutdownC := make(chan error)
+	go func() {
+		shutdownC <- serviceEp.Shutdown(context.Background())
+	}()
+
+	// new calls get refused once the shutdown starts
+	for {
+		_, err := client.DivCtxErr(context.Background(), 1, 1)
+		if errors.Is(err, irpc.ErrEndpointDraining) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("DivCtxErr(): %v", err)
+		}
+	}
+
+	select {
+	case err := <-shutdownC:
+		t.Fatalf("Shutdown() returned before the running call finished: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(unblockC)
+	if r := <-runningC; r.err != nil || r.res != 4 {
+		t.Fatalf("running call: %d, %v", r.res, r.err)
+	}
+	if err := <-shutdownC; err != nil {
+		t.Fatalf("Shutdown(): %v", err)
+	}
+	if err := context.Cause(serviceEp.Context()); !errors.Is(err, irpc.ErrEndpointClosed) {
+		t.Fatalf("unexpected cause: %v", err)
+	}
+	<-clientEp.Context().Done()
+}
+
+func TestShutdownClosesOnContextEnd(t *testing.T) {
+	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create local tcp endpoints: %+v", err)
+	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
+
+	service := testtools.NewTestServiceImpl(0)
+	startedC := make(chan struct{})
+	service.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		close(startedC)
+		<-ctx.Done()
+		return 0, context.Cause(ctx)
+	}
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(service))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("new client: %+v", err)
+	}
+
+	callErrC := make(chan error)
+	go func() {
+		_, err := client.DivCtxErr(context.Background(), 8, 2)
+		callErrC <- err
+	}()
+	<-startedC
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := serviceEp.Shutdown(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("Shutdown(): expected DeadlineExceeded, got: %v", err)
+	}
+	if err := <-callErrC; !errors.Is(err, irpc.ErrEndpointClosedByPeer) {
+		t.Fatalf("expected ErrEndpointClosedByPeer, got: %v", err)
+	}
+}
+
 // tests, whether dropped connection correctly closes both endpoints
 func TestOutsideConnectionClose(t *testing.T) {
 	c1, c2, err := testtools.CreateLocalTcpConnPipe()
@@ -747,34 +954,33 @@ func TestOutsideConnectionClose(t *testing.T) {
 }
 
 func TestCallingUnregisteredService(t *testing.T) {
-	// currently, unsuccessful call closes the service Ep, which in turn closes ours
-	// todo: figure out network errors and report ErrServiceNotFound error over network?
-
 	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints()
 	if err != nil {
 		t.Fatalf("failed to create local tcp endpoints: %+v", err)
 	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
 
 	client, err := testtools.NewTestServiceIrpcClient(clientEp)
 	if err != nil {
 		t.Fatalf("failed to create client: %+v", err)
 	}
 
-	_, err = client.DivErr(1, 2)
-	if !errors.Is(err, irpc.ErrEndpointClosedByPeer) {
-		t.Fatalf("unexpected client error: %q", err)
+	// the call fails, but the connection stays healthy
+	for range 2 {
+		_, err = client.DivErr(1, 2)
+		if !errors.Is(err, irpc.ErrServiceNotFound) {
+			t.Fatalf("unexpected client error: %q", err)
+		}
 	}
 
-	_, err = client.DivErr(1, 2)
-	if !errors.Is(err, irpc.ErrEndpointClosedByPeer) {
-		t.Fatalf("unexpected client error: %q", err)
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0)))
+	res, err := client.DivErr(6, 2)
+	if err != nil || res != 3 {
+		t.Fatalf("DivErr() after service registration: %d, %v", res, err)
 	}
-
-	<-serviceEp.Context().Done()
-	serviceCause := context.Cause(serviceEp.Context())
-	log.Println("serviceEp done. cause:", serviceCause)
-	if !errors.Is(serviceCause, irpc.ErrServiceNotFound) {
-		t.Fatalf("unexpected service endpoint cause: %v", serviceCause)
+	if serviceEp.Context().Err() != nil || clientEp.Context().Err() != nil {
+		t.Fatalf("endpoint closed after call to unregistered service")
 	}
 }
 
@@ -802,7 +1008,346 @@ func TestCallingNonexistentFunction(t *testing.T) {
 
 	t.Logf("testing non-existent function call")
 	err = clientEp.CallRemoteFunc(context.Background(), testtools.TestServiceId(), 666, testtools.DummySerializable{}, testtools.DummyDeserializable{})
-	if !errors.Is(err, irpc.ErrEndpointClosedByPeer) {
+	if !errors.Is(err, irpc.ErrFunctionNotFound) {
 		t.Fatalf("err: %+v", err)
 	}
+
+	t.Logf("testing valid client call after failed one")
+	if res := client.Div(6, 3); res != 2 {
+		t.Fatalf("unexpected result: %d", res)
+	}
+}
+
+func TestServiceNegotiation(t *testing.T) {
+	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints(irpc.WithServiceNegotiation())
+	if err != nil {
+		t.Fatalf("failed to create local tcp endpoints: %+v", err)
+	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
+
+	// service is not registered yet, so the constructor fails up front
+	if _, err := testtools.NewTestServiceIrpcClient(clientEp); !errors.Is(err, irpc.ErrServiceNotFound) {
+		t.Fatalf("expected ErrServiceNotFound, got: %v", err)
+	}
+
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0)))
+	// calls of both clients carry the same negotiated alias
+	for range 2 {
+		client, err := testtools.NewTestServiceIrpcClient(clientEp)
+		if err != nil {
+			t.Fatalf("failed to create client: %+v", err)
+		}
+		res, err := client.DivErr(6, 2)
+		if err != nil || res != 3 {
+			t.Fatalf("DivErr(): %d, %v", res, err)
+		}
+	}
+}
+
+func TestDeadlinePropagation(t *testing.T) {
+	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("failed to create local tcp endpoints: %+v", err)
+	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
+
+	deadlineC := make(chan time.Time, 1)
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		d, _ := ctx.Deadline()
+		deadlineC <- d
+		<-ctx.Done()
+		return 0, context.Cause(ctx)
+	}
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(impl))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("failed to create client: %+v", err)
+	}
+
+	deadline := time.Now().Add(200 * time.Millisecond)
+	ctx, cancel := context.WithDeadline(context.Background(), deadline)
+	defer cancel()
+	if _, err := client.DivCtxErr(ctx, 1, 2); err == nil {
+		t.Fatalf("DivCtxErr() succeeded after deadline")
+	}
+
+	if got := <-deadlineC; !got.Equal(deadline) {
+		t.Fatalf("service deadline %v differs from client deadline %v", got, deadline)
+	}
+
+	// no deadline is sent without one
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		if d, ok := ctx.Deadline(); ok {
+			return 0, fmt.Errorf("unexpected deadline %v", d)
+		}
+		return a / b, nil
+	}
+	if _, err := client.DivCtxErr(context.Background(), 4, 2); err != nil {
+		t.Fatalf("DivCtxErr(): %v", err)
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("failed to create local tcp endpoints: %+v", err)
+	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
+
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		md := irpc.MetadataFromContext(ctx)
+		if md["token"] != "secret" || md["tenant"] != "acme" {
+			return 0, fmt.Errorf("unexpected metadata: %v", md)
+		}
+		irpc.SetTrailer(ctx, irpc.Metadata{"cost": "1"})
+		irpc.SetTrailer(ctx, irpc.Metadata{"server": "s1"})
+		return a / b, nil
+	}
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(impl))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("failed to create client: %+v", err)
+	}
+
+	ctx := irpc.ContextWithMetadata(context.Background(), irpc.Metadata{"token": "secret"})
+	ctx = irpc.ContextWithMetadata(ctx, irpc.Metadata{"tenant": "acme"})
+	var trailer irpc.Metadata
+	ctx = irpc.ContextWithTrailer(ctx, &trailer)
+	if res, err := client.DivCtxErr(ctx, 6, 2); err != nil || res != 3 {
+		t.Fatalf("DivCtxErr(): %d, %v", res, err)
+	}
+	if trailer["cost"] != "1" || trailer["server"] != "s1" {
+		t.Fatalf("unexpected trailer: %v", trailer)
+	}
+
+	// outgoing metadata is not incoming metadata
+	if md := irpc.MetadataFromContext(ctx); md != nil {
+		t.Fatalf("unexpected incoming metadata on client: %v", md)
+	}
+	if _, err := client.DivCtxErr(context.Background(), 6, 2); err == nil {
+		t.Fatalf("DivCtxErr() without metadata succeeded")
+	}
+}
+
+func TestInterceptors(t *testing.T) {
+	var order []string
+	var orderMux sync.Mutex
+	logCall := func(name string) {
+		orderMux.Lock()
+		defer orderMux.Unlock()
+		order = append(order, name)
+	}
+
+	addToken := func(ctx context.Context, call irpc.CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable, invoke irpc.ClientInvoker) error {
+		logCall("client1")
+		return invoke(irpc.ContextWithMetadata(ctx, irpc.Metadata{"token": "secret"}), call, req, resp)
+	}
+	clientLog := func(ctx context.Context, call irpc.CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable, invoke irpc.ClientInvoker) error {
+		logCall("client2")
+		return invoke(ctx, call, req, resp)
+	}
+	auth := func(ctx context.Context, call irpc.CallInfo, handler irpc.ServerHandler) (irpcgen.Serializable, error) {
+		logCall("server")
+		if call.ServiceId != testtools.TestServiceId() {
+			return nil, fmt.Errorf("unexpected service id: %s", call.ServiceId)
+		}
+		if irpc.MetadataFromContext(ctx)["token"] != "secret" || call.FuncId == 0 {
+			return nil, errors.New("unauthorized")
+		}
+		return handler(ctx)
+	}
+
+	c1, c2, err := testtools.CreateLocalTcpConnPipe()
+	if err != nil {
+		t.Fatalf("create tcp pipe: %v", err)
+	}
+	serviceEp := irpc.NewEndpoint(c1, irpc.WithServerInterceptors(auth))
+	defer serviceEp.Close()
+	clientEp := irpc.NewEndpoint(c2, irpc.WithClientInterceptors(addToken, clientLog))
+	defer clientEp.Close()
+
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0)))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("failed to create client: %+v", err)
+	}
+
+	if res, err := client.DivErr(6, 2); err != nil || res != 3 {
+		t.Fatalf("DivErr(): %d, %v", res, err)
+	}
+	if !slices.Equal(order, []string{"client1", "client2", "server"}) {
+		t.Fatalf("unexpected interceptor order: %v", order)
+	}
+
+	// interceptor refuses calls of function 0 (Div)
+	defer func() {
+		r := recover()
+		if err, ok := r.(error); !ok || !errors.Is(err, irpc.ErrCallRejected) || !strings.Contains(err.Error(), "unauthorized") {
+			t.Fatalf("expected rejection panic, got: %v", r)
+		}
+	}()
+	client.Div(6, 2)
+}
+
+func TestInterceptorsGetMethodNames(t *testing.T) {
+	methodsC := make(chan string, 2)
+	clientInterceptor := func(ctx context.Context, call irpc.CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable, invoke irpc.ClientInvoker) error {
+		methodsC <- "client: " + call.Method
+		return invoke(ctx, call, req, resp)
+	}
+	serverInterceptor := func(ctx context.Context, call irpc.CallInfo, handler irpc.ServerHandler) (irpcgen.Serializable, error) {
+		if m, _ := call.Service.Method(call.FuncId); !m.HasContext {
+			return nil, fmt.Errorf("%s is expected to take context", call.Method)
+		}
+		methodsC <- "server: " + call.Method
+		return handler(ctx)
+	}
+
+	c1, c2, err := testtools.CreateLocalTcpConnPipe()
+	if err != nil {
+		t.Fatalf("create tcp pipe: %v", err)
+	}
+	serviceEp := irpc.NewEndpoint(c1, irpc.WithServerInterceptors(serverInterceptor))
+	defer serviceEp.Close()
+	clientEp := irpc.NewEndpoint(c2, irpc.WithClientInterceptors(clientInterceptor))
+	defer clientEp.Close()
+
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0)))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("failed to create client: %+v", err)
+	}
+
+	if _, err := client.DivCtxErr(context.Background(), 6, 2); err != nil {
+		t.Fatalf("DivCtxErr(): %v", err)
+	}
+	if m := <-methodsC; m != "client: TestService.DivCtxErr" {
+		t.Fatalf("unexpected client method: %q", m)
+	}
+	if m := <-methodsC; m != "server: TestService.DivCtxErr" {
+		t.Fatalf("unexpected server method: %q", m)
+	}
+}
+
+var errTestDivByZero = errors.New("division by zero")
+
+type testRangeError struct {
+	Min, Max int
+}
+
+func (e testRangeError) Error() string {
+	return fmt.Sprintf("out of range <%d, %d>", e.Min, e.Max)
+}
+
+func init() {
+	irpcgen.RegisterError("irpc.test.DivByZero", errTestDivByZero)
+	irpcgen.RegisterErrorType[testRangeError]("irpc.test.Range")
+}
+
+func TestTypedErrors(t *testing.T) {
+	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("failed to create local tcp endpoints: %+v", err)
+	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
+
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		if b == 0 {
+			return 0, fmt.Errorf("div %d: %w", a, errTestDivByZero)
+		}
+		if a > 100 {
+			return 0, testRangeError{Min: 0, Max: 100}
+		}
+		return 0, errors.New("unregistered")
+	}
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(impl))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("failed to create client: %+v", err)
+	}
+
+	_, err = client.DivCtxErr(context.Background(), 1, 0)
+	if !errors.Is(err, errTestDivByZero) || err.Error() != "div 1: division by zero" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var re testRangeError
+	if _, err := client.DivCtxErr(context.Background(), 101, 1); !errors.As(err, &re) || re.Max != 100 {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var remoteErr *irpcgen.RemoteError
+	if _, err := client.DivCtxErr(context.Background(), 1, 1); !errors.As(err, &remoteErr) || err.Error() != "unregistered" {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestPanicReturnsError(t *testing.T) {
+	serviceEp, clientEp, err := testtools.CreateLocalTcpEndpoints()
+	if err != nil {
+		t.Fatalf("create tcp: %v", err)
+	}
+	defer serviceEp.Close()
+	defer clientEp.Close()
+
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		panic("boom")
+	}
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(impl))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	_, err = client.DivCtxErr(context.Background(), 4, 2)
+	var panicErr *irpc.RemotePanicError
+	if !errors.As(err, &panicErr) {
+		t.Fatalf("expected RemotePanicError, got: %v", err)
+	}
+	if panicErr.Value != "boom" {
+		t.Fatalf("unexpected panic value: %q", panicErr.Value)
+	}
+	if !strings.Contains(panicErr.Stack, "goroutine") {
+		t.Fatalf("panic error is missing stack trace: %q", panicErr.Stack)
+	}
+
+	// the connection survives
+	if res := client.Div(4, 2); res != 2 {
+		t.Fatalf("Div(): %d", res)
+	}
+}
+
+func TestPanicClosesConnection(t *testing.T) {
+	pA, pB := testtools.NewDoubleEndedPipe()
+	serviceEp := irpc.NewEndpoint(pA, irpc.WithPanicPolicy(irpc.PanicCloseConnection))
+	clientEp := irpc.NewEndpoint(pB)
+	defer clientEp.Close()
+
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		panic("boom")
+	}
+	serviceEp.RegisterService(testtools.NewTestServiceIrpcService(impl))
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("create client: %v", err)
+	}
+
+	if _, err := client.DivCtxErr(context.Background(), 4, 2); !errors.Is(err, irpc.ErrEndpointClosedByPeer) {
+		t.Fatalf("expected ErrEndpointClosedByPeer, got: %v", err)
+	}
+
+	<-serviceEp.Context().Done()
+	var panicErr *irpc.RemotePanicError
+	if cause := context.Cause(serviceEp.Context()); !errors.As(cause, &panicErr) {
+		t.Fatalf("expected RemotePanicError as endpoint's close cause, got: %v", cause)
+	}
 }
@@ -9,7 +9,19 @@ import (
 	"time"
 )
 
-var _KVStoreIrpcId = irpcgen.ServiceId(0xe52e017562b128ac)
+var _KVStoreIrpcId = irpcgen.ServiceId(0x2ff2ff4b34880a0b)
+
+var _KVStoreIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _KVStoreIrpcId,
+	Name:      "KVStore",
+	Interface: "github.com/marben/irpc/examples/simple_kv_store.KVStore",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "Put", Params: []irpcgen.ParamDesc{{Name: "key", Type: "string"}, {Name: "value", Type: "[]byte"}, {Name: "ttl", Type: "time.Duration"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "error"}}, HasContext: false},
+		{Name: "Get", Params: []irpcgen.ParamDesc{{Name: "key", Type: "string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[]byte"}, {Name: "", Type: "error"}}, HasContext: false},
+		{Name: "Delete", Params: []irpcgen.ParamDesc{{Name: "key", Type: "string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "error"}}, HasContext: false},
+		{Name: "ModifiedSince", Params: []irpcgen.ParamDesc{{Name: "since", Type: "time.Time"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "[]string"}, {Name: "", Type: "error"}}, HasContext: false},
+	},
+}
 
 // KVStoreIrpcService provides [KVStore] interface over irpc
 type KVStoreIrpcService struct {
@@ -28,6 +40,11 @@ func (s *KVStoreIrpcService) Id() irpcgen.ServiceId {
 	return _KVStoreIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *KVStoreIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_KVStoreIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *KVStoreIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -92,7 +109,7 @@ type KVStoreIrpcClient struct {
 }
 
 func NewKVStoreIrpcClient(endpoint irpcgen.Endpoint) (*KVStoreIrpcClient, error) {
-	if err := endpoint.RegisterClient(_KVStoreIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_KVStoreIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &KVStoreIrpcClient{endpoint: endpoint}, nil
@@ -187,51 +204,19 @@ type _irpc_KVStore_PutResp struct {
 }
 
 func (s _irpc_KVStore_PutResp) Serialize(e *irpcgen.Encoder) error {
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p0); err != nil {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
 }
 func (s *_irpc_KVStore_PutResp) Deserialize(d *irpcgen.Decoder) error {
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_KVStore_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p0); err != nil {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_KVStore_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_KVStore_impl) Error() string {
-	return i._Error_0_
+func (s _irpc_KVStore_PutResp) ResultError() error {
+	return s.p0
 }
 
 type _irpc_KVStore_GetReq struct {
@@ -260,20 +245,7 @@ func (s _irpc_KVStore_GetResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncByteSlice(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type []byte: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -282,25 +254,14 @@ func (s *_irpc_KVStore_GetResp) Deserialize(d *irpcgen.Decoder) error {
 	if err := irpcgen.DecByteSlice(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type []byte: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_KVStore_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_KVStore_GetResp) ResultError() error {
+	return s.p1
+}
 
 type _irpc_KVStore_DeleteReq struct {
 	key string
@@ -324,44 +285,20 @@ type _irpc_KVStore_DeleteResp struct {
 }
 
 func (s _irpc_KVStore_DeleteResp) Serialize(e *irpcgen.Encoder) error {
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p0); err != nil {
+	if err := irpcgen.EncError(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
 }
 func (s *_irpc_KVStore_DeleteResp) Deserialize(d *irpcgen.Decoder) error {
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_KVStore_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p0); err != nil {
+	if err := irpcgen.DecError(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_KVStore_DeleteResp) ResultError() error {
+	return s.p0
+}
 
 type _irpc_KVStore_ModifiedSinceReq struct {
 	since time.Time
@@ -391,20 +328,7 @@ func (s _irpc_KVStore_ModifiedSinceResp) Serialize(e *irpcgen.Encoder) error {
 	}(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type []string: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -415,22 +339,11 @@ func (s *_irpc_KVStore_ModifiedSinceResp) Deserialize(d *irpcgen.Decoder) error
 	}(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type []string: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_KVStore_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_KVStore_ModifiedSinceResp) ResultError() error {
+	return s.p1
+}
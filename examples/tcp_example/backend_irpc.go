@@ -9,7 +9,18 @@ import (
 	"time"
 )
 
-var _BackendIrpcId = irpcgen.ServiceId(0xf840c970fd1b9bb9)
+var _BackendIrpcId = irpcgen.ServiceId(0xda30e1f4299f6695)
+
+var _BackendIrpcDesc = irpcgen.ServiceDesc{
+	Id:        _BackendIrpcId,
+	Name:      "Backend",
+	Interface: "github.com/marben/irpc/examples/tcp_example.Backend",
+	Methods: []irpcgen.MethodDesc{
+		{Name: "ReverseString", Params: []irpcgen.ParamDesc{{Name: "in", Type: "string"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}, {Name: "", Type: "error"}}, HasContext: false},
+		{Name: "RepeatString", Params: []irpcgen.ParamDesc{{Name: "in", Type: "string"}, {Name: "n", Type: "int"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}, {Name: "", Type: "error"}}, HasContext: false},
+		{Name: "TimeToString", Params: []irpcgen.ParamDesc{{Name: "t", Type: "time.Time"}}, Results: []irpcgen.ParamDesc{{Name: "", Type: "string"}, {Name: "", Type: "error"}}, HasContext: false},
+	},
+}
 
 // BackendIrpcService provides [Backend] interface over irpc
 type BackendIrpcService struct {
@@ -28,6 +39,11 @@ func (s *BackendIrpcService) Id() irpcgen.ServiceId {
 	return _BackendIrpcId
 }
 
+// Descriptor implements [irpcgen.DescribedService] interface.
+func (s *BackendIrpcService) Descriptor() *irpcgen.ServiceDesc {
+	return &_BackendIrpcDesc
+}
+
 // GetFuncCall implements [irpcgen.Service] interface
 func (s *BackendIrpcService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
 	switch funcId {
@@ -82,7 +98,7 @@ type BackendIrpcClient struct {
 }
 
 func NewBackendIrpcClient(endpoint irpcgen.Endpoint) (*BackendIrpcClient, error) {
-	if err := endpoint.RegisterClient(_BackendIrpcId); err != nil {
+	if err := irpcgen.RegisterClient(endpoint, &_BackendIrpcDesc); err != nil {
 		return nil, fmt.Errorf("register failed: %w", err)
 	}
 	return &BackendIrpcClient{endpoint: endpoint}, nil
@@ -155,20 +171,7 @@ func (s _irpc_Backend_ReverseStringResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncString(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type string: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -177,32 +180,13 @@ func (s *_irpc_Backend_ReverseStringResp) Deserialize(d *irpcgen.Decoder) error
 	if err := irpcgen.DecString(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type string: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_Backend_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
-
-type _error_Backend_impl struct {
-	_Error_0_ string
-}
-
-func (i _error_Backend_impl) Error() string {
-	return i._Error_0_
+func (s _irpc_Backend_ReverseStringResp) ResultError() error {
+	return s.p1
 }
 
 type _irpc_Backend_RepeatStringReq struct {
@@ -238,20 +222,7 @@ func (s _irpc_Backend_RepeatStringResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncString(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type string: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -260,25 +231,14 @@ func (s *_irpc_Backend_RepeatStringResp) Deserialize(d *irpcgen.Decoder) error {
 	if err := irpcgen.DecString(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type string: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_Backend_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_Backend_RepeatStringResp) ResultError() error {
+	return s.p1
+}
 
 type _irpc_Backend_TimeToStringReq struct {
 	t time.Time
@@ -306,20 +266,7 @@ func (s _irpc_Backend_TimeToStringResp) Serialize(e *irpcgen.Encoder) error {
 	if err := irpcgen.EncString(e, s.p0); err != nil {
 		return fmt.Errorf("serialize type string: %w", err)
 	}
-	if err := func(enc *irpcgen.Encoder, v error) error {
-		isNil := v == nil
-		if err := irpcgen.EncIsNil(enc, isNil); err != nil {
-			return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
-		}
-		if isNil {
-			return nil
-		}
-		_Error_0_ := v.Error()
-		if err := irpcgen.EncString(enc, _Error_0_); err != nil {
-			return fmt.Errorf("serialize \"v.Error()\" of type string: %w", err)
-		}
-		return nil
-	}(e, s.p1); err != nil {
+	if err := irpcgen.EncError(e, s.p1); err != nil {
 		return fmt.Errorf("serialize type error: %w", err)
 	}
 	return nil
@@ -328,22 +275,11 @@ func (s *_irpc_Backend_TimeToStringResp) Deserialize(d *irpcgen.Decoder) error {
 	if err := irpcgen.DecString(d, &s.p0); err != nil {
 		return fmt.Errorf("deserialize type string: %w", err)
 	}
-	if err := func(dec *irpcgen.Decoder, s *error) error {
-		var isNil bool
-		if err := irpcgen.DecIsNil(dec, &isNil); err != nil {
-			return fmt.Errorf("deserialize isNil: %w", err)
-		}
-		if isNil {
-			return nil
-		}
-		var impl _error_Backend_impl
-		if err := irpcgen.DecString(dec, &impl._Error_0_); err != nil {
-			return fmt.Errorf("deserialize \"_Error_0_\" string: %w", err)
-		}
-		*s = impl
-		return nil
-	}(d, &s.p1); err != nil {
+	if err := irpcgen.DecError(d, &s.p1); err != nil {
 		return fmt.Errorf("deserialize type error: %w", err)
 	}
 	return nil
 }
+func (s _irpc_Backend_TimeToStringResp) ResultError() error {
+	return s.p1
+}
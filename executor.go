@@ -2,8 +2,11 @@ package irpc
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"runtime/debug"
 	"sync"
+	"time"
 
 	"github.com/marben/irpc/irpcgen"
 )
@@ -16,17 +19,48 @@ type executor struct {
 
 	ctx context.Context // all workers derive their context from this context
 
+	sender       workerSender
+	streamWindow int // number of streamed parameter's items our peer can send us ahead
+	panicPolicy  PanicPolicy
+	observer     workerObserver
+
 	// active running workers
 	serviceWorkers map[reqNumT]serviceWorker
-	m              sync.Mutex
+	// streamed parameters, that peer didn't finish sending yet. they may outlive their workers
+	paramStreams map[reqNumT]*paramStream
+	// number of workers, that didn't send their response yet
+	running int
+	// draining executor refuses new requests
+	draining bool
+	m        sync.Mutex
 
 	errC chan error
 }
 
-func newExecutor(ctx context.Context, parallelWorkers int) *executor {
+// workerSender sends workers' results to our peer
+type workerSender interface {
+	sendResponse(reqNum reqNumT, respData irpcgen.Serializable, trailer Metadata) error
+	sendStreamItem(reqNum reqNumT, item irpcgen.Serializable) error
+	sendParamCredit(reqNum reqNumT, credit int) error
+	sendErrorResponse(reqNum reqNumT, errResp errorResponsePacket) error
+}
+
+// workerObserver learns about workers' progress. it feeds metrics and logs
+type workerObserver interface {
+	workerStarted()
+	// call is nil for our internal requests
+	workerFinished(reqNum reqNumT, call *CallInfo, duration time.Duration, status CallStatus, panicErr *RemotePanicError)
+}
+
+func newExecutor(ctx context.Context, parallelWorkers int, streamWindow int, panicPolicy PanicPolicy, sender workerSender, observer workerObserver) *executor {
 	return &executor{
 		wrkrQueue:      make(chan struct{}, parallelWorkers),
+		sender:         sender,
+		streamWindow:   streamWindow,
+		panicPolicy:    panicPolicy,
+		observer:       observer,
 		serviceWorkers: make(map[reqNumT]serviceWorker),
+		paramStreams:   make(map[reqNumT]*paramStream),
 		errC:           make(chan error, parallelWorkers), // maybe 1? maybe parallel workers -1?
 		ctx:            ctx,
 	}
@@ -48,7 +82,14 @@ func (e *executor) delWorker(reqNum reqNumT) {
 
 // runServiceWorker waits for worker slot and then runs rpcExecutor in a new goroutine
 // returns once work was succesfully started
-func (e *executor) runServiceWorker(reqNum reqNumT, rpcExecutor irpcgen.FuncExecutor, sendResponseF func(reqNum reqNumT, respData irpcgen.Serializable) error) error {
+// withParamStream means, that peer is going to send streamed parameter's items after the request
+// request's deadline and metadata are passed to handler in its context
+// error returned by handler is sent to peer instead of response
+// call is reported to the observer. it is nil for our internal requests
+// interceptors wrap the handler including sending of its stream's items
+func (e *executor) runServiceWorker(req requestPacket, call *CallInfo, handler ServerHandler, interceptors []ServerInterceptor, withParamStream bool) error {
+	reqNum := req.ReqNum
+
 	// waits until worker slot is available (blocks here on too many long rpcs)
 	select {
 	case e.wrkrQueue <- struct{}{}:
@@ -56,32 +97,99 @@ func (e *executor) runServiceWorker(reqNum reqNumT, rpcExecutor irpcgen.FuncExec
 		return e.ctx.Err()
 	}
 
+	if !e.startWorker() {
+		errResp := errorResponsePacket{ReqNum: reqNum, Kind: drainingErrorKind, Msg: "endpoint is shutting down"}
+		e.sendFailure(reqNum, errResp, withParamStream)
+		return nil
+	}
+
 	// workerCtx is passed to the service's actual implementation
 	// cancelling it doesn't mean end of executor
 	workerCtx, cancelWorker := context.WithCancelCause(e.ctx)
 
+	// caller's deadline, if it has one. zero deadline means there is none
+	cancelDeadline := context.CancelFunc(func() {})
+	if !req.Deadline.IsZero() {
+		workerCtx, cancelDeadline = context.WithDeadline(workerCtx, req.Deadline)
+	}
+	if req.Metadata != nil {
+		workerCtx = context.WithValue(workerCtx, incomingMetadataKey{}, req.Metadata)
+	}
+	trailer := new(trailer)
+	workerCtx = context.WithValue(workerCtx, trailerKey{}, trailer)
+
 	wrkr := serviceWorker{
-		cancel: cancelWorker,
+		cancel:  cancelWorker,
+		credits: newStreamCredits(),
+	}
+
+	// param stream needs to be registered before readLoop reads its first item
+	var ps *paramStream
+	if withParamStream {
+		ps = newParamStream(workerCtx, reqNum, e.streamWindow, e.sender.sendParamCredit)
+		e.addParamStream(reqNum, ps)
+		workerCtx = irpcgen.ContextWithParamStream(workerCtx, ps)
 	}
 
 	// a goroutine is created for each remote call
 
 	e.addWorker(reqNum, wrkr)
 	go func() {
+		// only once the response is sent
+		defer e.workerDone()
 		defer cancelWorker(nil)
+		defer cancelDeadline()
 		// release the worker queue
 		defer func() { <-e.wrkrQueue }()
 
-		defer e.delWorker(reqNum)
+		e.observer.workerStarted()
+		start := time.Now()
 
-		resp := rpcExecutor(workerCtx)
+		handler := e.streamingHandler(reqNum, handler, wrkr.credits)
+		if call != nil && len(interceptors) > 0 {
+			handler = chainServerInterceptors(interceptors, *call, handler)
+		}
+		resp, panicErr, err := e.execute(workerCtx, handler)
+
+		status := callStatus(workerCtx, err, resp)
+		if panicErr != nil {
+			status = CallFailed
+		}
+		e.observer.workerFinished(reqNum, call, time.Since(start), status, panicErr)
+
+		// once peer has our response, it may reuse the request number.
+		// we must forget the worker and never send parameter credit after the response
+		e.delWorker(reqNum)
+		if ps != nil {
+			ps.close()
+		}
 
 		// if executor's context was canceled, we don't even bother with sending response
 		if e.ctx.Err() != nil {
 			return
 		}
 
-		if err := sendResponseF(reqNum, resp); err != nil {
+		if panicErr != nil {
+			if e.panicPolicy == PanicCloseConnection {
+				e.errC <- panicErr
+				return
+			}
+			errResp := errorResponsePacket{ReqNum: reqNum, Kind: panicErrorKind, Msg: panicErr.Value, Stack: panicErr.Stack}
+			if err := e.sender.sendErrorResponse(reqNum, errResp); err != nil {
+				e.errC <- fmt.Errorf("failed to serialize error response %d to connection: %w", reqNum, err)
+			}
+			return
+		}
+
+		if err != nil {
+			errResp := errorResponsePacket{ReqNum: reqNum, Kind: rejectedErrorKind, Msg: err.Error()}
+			if err := e.sender.sendErrorResponse(reqNum, errResp); err != nil {
+				e.errC <- fmt.Errorf("failed to serialize error response %d to connection: %w", reqNum, err)
+			}
+			return
+		}
+
+		if err := e.sender.sendResponse(reqNum, resp, trailer.get()); err != nil {
 			e.errC <- fmt.Errorf("failed to serialize response %d to connection: %w", reqNum, err)
 		}
 	}()
@@ -89,6 +197,115 @@ func (e *executor) runServiceWorker(reqNum reqNumT, rpcExecutor irpcgen.FuncExec
 	return nil
 }
 
+// failRequest responds with errResp to a request, that cannot be executed
+// it occupies a worker slot just like a regular request, so that failed requests cannot pile up
+func (e *executor) failRequest(reqNum reqNumT, errResp errorResponsePacket, withParamStream bool) error {
+	select {
+	case e.wrkrQueue <- struct{}{}:
+	case <-e.ctx.Done():
+		return e.ctx.Err()
+	}
+
+	e.sendFailure(reqNum, errResp, withParamStream)
+	return nil
+}
+
+// sendFailure sends errResp from a new goroutine, that releases already taken worker slot
+func (e *executor) sendFailure(reqNum reqNumT, errResp errorResponsePacket, withParamStream bool) {
+	if withParamStream {
+		// we never give peer any credit, but it still ends the param stream
+		ps := newParamStream(e.ctx, reqNum, e.streamWindow, e.sender.sendParamCredit)
+		ps.close()
+		e.addParamStream(reqNum, ps)
+	}
+
+	go func() {
+		defer func() { <-e.wrkrQueue }()
+
+		if e.ctx.Err() != nil {
+			return
+		}
+		if err := e.sender.sendErrorResponse(reqNum, errResp); err != nil {
+			e.errC <- fmt.Errorf("failed to serialize error response %d to connection: %w", reqNum, err)
+		}
+	}()
+}
+
+// startWorker counts a new running worker. returns false if the executor is draining
+func (e *executor) startWorker() bool {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	if e.draining {
+		return false
+	}
+	e.running++
+	return true
+}
+
+func (e *executor) workerDone() {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.running--
+}
+
+// drain makes the executor refuse all new requests
+func (e *executor) drain() {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.draining = true
+}
+
+// idle reports, whether all started workers have sent their responses
+func (e *executor) idle() bool {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return e.running == 0
+}
+
+// execute runs the handler
+// err is an error returned by the handler (ie by a server interceptor)
+// unless the panic policy says otherwise, panics are recovered and returned as error
+func (e *executor) execute(workerCtx context.Context, handler ServerHandler) (resp irpcgen.Serializable, panicErr *RemotePanicError, err error) {
+	if e.panicPolicy != PanicRepanic {
+		defer func() {
+			if r := recover(); r != nil {
+				panicErr = &RemotePanicError{Value: fmt.Sprint(r), Stack: string(debug.Stack())}
+			}
+		}()
+	}
+
+	resp, err = handler(workerCtx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return resp, nil, nil
+}
+
+// streamingHandler makes handler, that also sends the items of the function's stream
+// streaming functions first send all their items. the response itself ends the stream
+func (e *executor) streamingHandler(reqNum reqNumT, handler ServerHandler, credits *streamCredits) ServerHandler {
+	return func(ctx context.Context) (irpcgen.Serializable, error) {
+		resp, err := handler(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		if stream, ok := resp.(irpcgen.StreamSerializable); ok {
+			stream.SendItems(ctx, func(item irpcgen.Serializable) error {
+				if err := credits.take(ctx); err != nil {
+					return err
+				}
+				return e.sender.sendStreamItem(reqNum, item)
+			})
+		}
+		return resp, nil
+	}
+}
+
 func (e *executor) cancelRequest(rnum reqNumT, cancelErr error) {
 	e.m.Lock()
 	defer e.m.Unlock()
@@ -103,7 +320,55 @@ func (e *executor) cancelRequest(rnum reqNumT, cancelErr error) {
 	sw.cancel(cancelErr)
 }
 
+// grantStreamCredit allows the worker to send n more stream items
+func (e *executor) grantStreamCredit(rnum reqNumT, n uint64) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	sw, found := e.serviceWorkers[rnum]
+	if !found {
+		// the stream may have ended, while the credit was on the way
+		return
+	}
+
+	sw.credits.grant(n)
+}
+
+func (e *executor) addParamStream(reqNum reqNumT, ps *paramStream) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.paramStreams[reqNum] = ps
+}
+
+// pushParamItem deserializes next item of a streamed parameter
+func (e *executor) pushParamItem(reqNum reqNumT, dec *irpcgen.Decoder) error {
+	e.m.Lock()
+	ps, found := e.paramStreams[reqNum]
+	e.m.Unlock()
+
+	if !found {
+		return errors.Join(errProtocolError, fmt.Errorf("param item for unknown request %d", reqNum))
+	}
+	return ps.stream.push(dec)
+}
+
+// endParamStream is called once peer sent all items of a streamed parameter
+func (e *executor) endParamStream(reqNum reqNumT) error {
+	e.m.Lock()
+	ps, found := e.paramStreams[reqNum]
+	delete(e.paramStreams, reqNum)
+	e.m.Unlock()
+
+	if !found {
+		return errors.Join(errProtocolError, fmt.Errorf("param end for unknown request %d", reqNum))
+	}
+	close(ps.stream.itemC)
+	return nil
+}
+
 // a request from opposing endpoint, that we are executing
 type serviceWorker struct {
-	cancel context.CancelCauseFunc // todo: we don't need this struct for just a function pointer
+	cancel  context.CancelCauseFunc
+	credits *streamCredits // number of stream items our peer is ready to receive
 }
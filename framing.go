@@ -0,0 +1,169 @@
+package irpc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"slices"
+)
+
+// DefaultMaxMessageLen is the maximum length of a message (a packet with its data), that we accept from our peer.
+// It can be overridden for each endpoint with [WithMaxMessageLen] option
+var DefaultMaxMessageLen = 4 * 1024 * 1024
+
+// maxFrameLen is the maximum payload of frames we send. longer messages are split into multiple frames
+const maxFrameLen = 64 * 1024
+
+// every packet is sent as a message of one or more frames.
+// frame is: uvarint(payloadLen << 1 | more) followed by payload
+// 'more' bit is set on all frames of the message except the last one
+const frameMoreFlag = 1
+
+// frameWriter splits messages into frames of at most maxLen bytes
+// a message is whatever was written between two endMessage() calls
+type frameWriter struct {
+	w      io.Writer
+	maxLen int
+
+	// buf starts with binary.MaxVarintLen64 bytes reserved for frame header, so that each frame is a single write
+	buf []byte
+}
+
+func newFrameWriter(w io.Writer, maxLen int) *frameWriter {
+	return &frameWriter{
+		w:      w,
+		maxLen: maxLen,
+		buf:    make([]byte, binary.MaxVarintLen64, binary.MaxVarintLen64+maxLen),
+	}
+}
+
+func (fw *frameWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		// we only send full frames here. the last frame is sent by endMessage(), as we don't know yet, whether it is the last
+		if fw.payloadLen() == fw.maxLen {
+			if err := fw.writeFrame(true); err != nil {
+				return written, err
+			}
+		}
+		n := min(fw.maxLen-fw.payloadLen(), len(p))
+		fw.buf = append(fw.buf, p[:n]...)
+		p = p[n:]
+		written += n
+	}
+	return written, nil
+}
+
+// endMessage sends everything written since the last endMessage() as the last frame of the message
+func (fw *frameWriter) endMessage() error {
+	return fw.writeFrame(false)
+}
+
+func (fw *frameWriter) payloadLen() int {
+	return len(fw.buf) - binary.MaxVarintLen64
+}
+
+// writeFrame sends all buffered data as a frame
+func (fw *frameWriter) writeFrame(more bool) error {
+	header := uint64(fw.payloadLen()) << 1
+	if more {
+		header |= frameMoreFlag
+	}
+	var hdrBuf [binary.MaxVarintLen64]byte
+	hdrLen := binary.PutUvarint(hdrBuf[:], header)
+	start := binary.MaxVarintLen64 - hdrLen
+	copy(fw.buf[start:], hdrBuf[:hdrLen])
+
+	_, err := fw.w.Write(fw.buf[start:])
+	fw.buf = fw.buf[:binary.MaxVarintLen64]
+	return err
+}
+
+// frameReader reassembles incoming frames into messages of at most maxLen bytes
+// reading continues seamlessly from one message to the next. Len() returns number of unread bytes of current message
+type frameReader struct {
+	r      *bufio.Reader
+	maxLen int
+
+	msg []byte // current message
+	pos int    // read position in msg
+}
+
+func newFrameReader(r io.Reader, maxLen int) *frameReader {
+	return &frameReader{
+		r:      bufio.NewReader(r),
+		maxLen: maxLen,
+	}
+}
+
+func (fr *frameReader) Read(p []byte) (int, error) {
+	if err := fr.ensureData(); err != nil {
+		return 0, err
+	}
+	// we never read across messages, so that Len() is always accurate
+	n := copy(p, fr.msg[fr.pos:])
+	fr.pos += n
+	return n, nil
+}
+
+func (fr *frameReader) ReadByte() (byte, error) {
+	if err := fr.ensureData(); err != nil {
+		return 0, err
+	}
+	b := fr.msg[fr.pos]
+	fr.pos++
+	return b, nil
+}
+
+// Len returns number of unread bytes of current message
+func (fr *frameReader) Len() int {
+	return len(fr.msg) - fr.pos
+}
+
+// discardMessage skips the rest of the current message
+func (fr *frameReader) discardMessage() {
+	fr.pos = len(fr.msg)
+}
+
+// ensureData reads next message, if the current one was fully read
+func (fr *frameReader) ensureData() error {
+	for fr.Len() == 0 {
+		if err := fr.readMessage(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (fr *frameReader) readMessage() error {
+	// don't hold on to memory of exceptionally long messages
+	if cap(fr.msg) > maxFrameLen {
+		fr.msg = nil
+	}
+	fr.msg = fr.msg[:0]
+	fr.pos = 0
+
+	for {
+		header, err := binary.ReadUvarint(fr.r)
+		if err != nil {
+			return err
+		}
+		payloadLen := header >> 1
+		start := len(fr.msg)
+		if payloadLen > uint64(fr.maxLen-start) {
+			return errors.Join(errProtocolError, fmt.Errorf("message of over %d bytes exceeds max message length %d", uint64(start)+payloadLen, fr.maxLen))
+		}
+
+		// messages are limited by maxLen, so we never allocate more than that
+		fr.msg = slices.Grow(fr.msg, int(payloadLen))[:start+int(payloadLen)]
+		if _, err := io.ReadFull(fr.r, fr.msg[start:]); err != nil {
+			return err
+		}
+
+		if header&frameMoreFlag == 0 {
+			return nil
+		}
+	}
+}
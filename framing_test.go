@@ -0,0 +1,108 @@
+package irpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"testing"
+)
+
+func TestFramingRoundTrip(t *testing.T) {
+	msgs := [][]byte{
+		{1},
+		bytes.Repeat([]byte{2}, 7),
+		bytes.Repeat([]byte{3}, 8),  // exactly one full frame
+		bytes.Repeat([]byte{4}, 16), // exactly two full frames
+		bytes.Repeat([]byte{5}, 1000),
+	}
+
+	for _, maxLen := range []int{1, 3, 8, 1024} {
+		buf := bytes.NewBuffer(nil)
+		fw := newFrameWriter(buf, maxLen)
+		for _, m := range msgs {
+			// split the writes, so that they don't match frame boundaries
+			for len(m) > 0 {
+				n := min(len(m), 5)
+				if _, err := fw.Write(m[:n]); err != nil {
+					t.Fatalf("maxLen %d: Write(): %v", maxLen, err)
+				}
+				m = m[n:]
+			}
+			if err := fw.endMessage(); err != nil {
+				t.Fatalf("maxLen %d: endMessage(): %v", maxLen, err)
+			}
+		}
+
+		fr := newFrameReader(buf, 1000)
+		for i, m := range msgs {
+			// first byte loads the message, so that we can check its length
+			b, err := fr.ReadByte()
+			if err != nil {
+				t.Fatalf("maxLen %d: message %d: ReadByte(): %v", maxLen, i, err)
+			}
+			if fr.Len() != len(m)-1 {
+				t.Fatalf("maxLen %d: message %d: Len(): %d != %d", maxLen, i, fr.Len(), len(m)-1)
+			}
+			got := append([]byte{b}, make([]byte, len(m)-1)...)
+			if _, err := io.ReadFull(fr, got[1:]); err != nil {
+				t.Fatalf("maxLen %d: message %d: ReadFull(): %v", maxLen, i, err)
+			}
+			if !bytes.Equal(got, m) {
+				t.Fatalf("maxLen %d: message %d: %v != %v", maxLen, i, got, m)
+			}
+		}
+		if _, err := fr.ReadByte(); err != io.EOF {
+			t.Fatalf("maxLen %d: expected EOF, got: %v", maxLen, err)
+		}
+	}
+}
+
+func TestFrameReaderRefusesOversizedMessage(t *testing.T) {
+	for _, frameLen := range []int{100, 4} {
+		buf := bytes.NewBuffer(nil)
+		fw := newFrameWriter(buf, frameLen)
+		fw.Write(bytes.Repeat([]byte{1}, 50))
+		if err := fw.endMessage(); err != nil {
+			t.Fatalf("endMessage(): %v", err)
+		}
+
+		// it doesn't matter, whether the message comes in a single frame, or in many small ones
+		fr := newFrameReader(buf, 10)
+		if _, err := fr.ReadByte(); !errors.Is(err, errProtocolError) {
+			t.Fatalf("frame len %d: expected protocol error, got: %v", frameLen, err)
+		}
+	}
+}
+
+func TestEndpointRefusesMessageOverMaxMessageLen(t *testing.T) {
+	c1, c2 := net.Pipe()
+	ep1 := NewEndpoint(c1, WithHello(bytes.Repeat([]byte{1}, 2000)))
+	defer ep1.Close()
+	ep2 := NewEndpoint(c2, WithMaxMessageLen(1000))
+	defer ep2.Close()
+
+	<-ep2.Context().Done()
+	if err := context.Cause(ep2.Context()); !errors.Is(err, errProtocolError) {
+		t.Fatalf("expected protocol error, got: %v", err)
+	}
+}
+
+func TestFrameReaderDiscardMessage(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	fw := newFrameWriter(buf, 4)
+	fw.Write([]byte{1, 2, 3, 4, 5, 6})
+	fw.endMessage()
+	fw.Write([]byte{7})
+	fw.endMessage()
+
+	fr := newFrameReader(buf, 6)
+	if b, _ := fr.ReadByte(); b != 1 {
+		t.Fatalf("unexpected first byte: %d", b)
+	}
+	fr.discardMessage()
+	if b, _ := fr.ReadByte(); b != 7 {
+		t.Fatalf("unexpected byte after discard: %d", b)
+	}
+}
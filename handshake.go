@@ -0,0 +1,203 @@
+package irpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// ProtocolVersion is the version of the wire protocol spoken by this package.
+// Endpoints only talk to peers with exactly the same version.
+const ProtocolVersion = 6
+
+// ErrHandshakeFailed is the cause (wrapped in [ErrEndpointClosed]) of an endpoint, whose peer is not compatible with us
+var ErrHandshakeFailed = errors.New("irpc: handshake failed")
+
+// ErrFeatureNotSupported is returned by calls, that need a [Features] bit, which the peer didn't announce in its handshake.
+var ErrFeatureNotSupported = errors.New("irpc: feature not supported by peer")
+
+// every connection starts with it, so that we don't mistake some other protocol for irpc
+var protocolMagic = [4]byte{'i', 'R', 'P', 'C'}
+
+// Features is a set of optional protocol capabilities of an endpoint.
+// Simpler implementations of the protocol may lack some of them. We don't use features, that our peer didn't announce.
+// Unknown bits are ignored, so that new features can be added without breaking older peers.
+type Features uint64
+
+const (
+	FeatureStreaming          Features = 1 << iota // streamed results and parameters. without it, streaming calls fail with [ErrFeatureNotSupported]
+	FeatureServiceNegotiation                      // RegisterClient can negotiate service aliases. without it, RegisterClient doesn't negotiate
+)
+
+// features we support
+const ourFeatures = FeatureStreaming | FeatureServiceNegotiation
+
+// Has reports whether all features of f2 are in f
+func (f Features) Has(f2 Features) bool {
+	return f&f2 == f2
+}
+
+// PeerInfo describes our peer, as announced in the handshake.
+type PeerInfo struct {
+	Version  uint64   // peer's [ProtocolVersion]
+	Features Features // peer's capabilities
+	Hello    []byte   // application payload set by peer with [WithHello] option. nil if there is none
+}
+
+// handshakePacket is the first message sent in each direction
+type handshakePacket struct {
+	Magic    [4]byte
+	Version  uint64
+	Features Features
+	Hello    []byte
+}
+
+func (p handshakePacket) Serialize(e *irpcgen.Encoder) error {
+	for _, b := range p.Magic {
+		if err := irpcgen.EncUint8(e, b); err != nil {
+			return err
+		}
+	}
+	if err := irpcgen.EncUint64(e, p.Version); err != nil {
+		return err
+	}
+	if err := irpcgen.EncUint64(e, p.Features); err != nil {
+		return err
+	}
+	if err := irpcgen.EncByteSlice(e, p.Hello); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *handshakePacket) Deserialize(d *irpcgen.Decoder) error {
+	for i := range p.Magic {
+		if err := irpcgen.DecUint8(d, &p.Magic[i]); err != nil {
+			return err
+		}
+	}
+	// don't bother reading the rest from whatever it is we are connected to
+	if p.Magic != protocolMagic {
+		return fmt.Errorf("unexpected magic %q", p.Magic[:])
+	}
+	if err := irpcgen.DecUint64(d, &p.Version); err != nil {
+		return err
+	}
+	if err := irpcgen.DecUint64(d, &p.Features); err != nil {
+		return err
+	}
+	if err := irpcgen.DecByteSlice(d, &p.Hello); err != nil {
+		return err
+	}
+	return nil
+}
+
+func ourHandshake(hello []byte) handshakePacket {
+	return handshakePacket{
+		Magic:    protocolMagic,
+		Version:  ProtocolVersion,
+		Features: ourFeatures,
+		Hello:    hello,
+	}
+}
+
+// sendHandshake sends our handshake. e.encMux must be locked by caller
+func (e *Endpoint) sendHandshake() error {
+	if err := writeMessage(e.enc, e.frameW, ourHandshake(e.hello)); err != nil {
+		return fmt.Errorf("handshake: %w", err)
+	}
+	return nil
+}
+
+// readHandshake reads and checks peer's handshake. it precedes any other packet
+func (e *Endpoint) readHandshake() error {
+	var hs handshakePacket
+	if err := hs.Deserialize(e.dec); err != nil {
+		return errors.Join(ErrHandshakeFailed, err)
+	}
+	if e.frameR.Len() != 0 {
+		return errors.Join(ErrHandshakeFailed, errProtocolError, fmt.Errorf("%d unread bytes after handshake", e.frameR.Len()))
+	}
+	if hs.Version != ProtocolVersion {
+		return errors.Join(ErrHandshakeFailed, fmt.Errorf("peer's protocol version %d differs from ours %d", hs.Version, ProtocolVersion))
+	}
+
+	peer := PeerInfo{Version: hs.Version, Features: hs.Features, Hello: hs.Hello}
+	if e.peerCheck != nil {
+		if err := e.peerCheck(peer); err != nil {
+			e.rejectPeer(err.Error())
+			return errors.Join(ErrHandshakeFailed, err)
+		}
+	}
+
+	e.peer = peer
+	close(e.handshakeDone)
+	return nil
+}
+
+// rejectPeer tells the peer, why we refuse to talk to it
+func (e *Endpoint) rejectPeer(reason string) {
+	if err := e.serializePacket(packetHeader{typ: rejectPacketType}, rejectPacket{Reason: reason}); err != nil {
+		return
+	}
+	// peer could get the connection reset, if we closed it with peer's data unread
+	if cw, ok := e.connCloser.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+	}
+	lingerTimer := time.AfterFunc(rejectLingerTimeout, func() { e.connCloser.Close() })
+	defer lingerTimer.Stop()
+	for e.frameR.discardMessage(); ; e.frameR.discardMessage() {
+		if err := e.frameR.ensureData(); err != nil {
+			return
+		}
+	}
+}
+
+// peerSupports waits for the handshake and returns [ErrFeatureNotSupported], unless the peer announced all of features
+func (e *Endpoint) peerSupports(ctx context.Context, features Features) error {
+	peer, err := e.Peer(ctx)
+	if err != nil {
+		return err
+	}
+	if !peer.Features.Has(features) {
+		return fmt.Errorf("%w: %b", ErrFeatureNotSupported, features&^peer.Features)
+	}
+	return nil
+}
+
+// ConnectEndpoint creates endpoint the same way [NewEndpoint] does, but waits for the handshake with the peer.
+// If the handshake fails (ie the peer speaks a different protocol version, or either side's peer check refuses the other),
+// the endpoint's cancellation cause is returned. It wraps [ErrHandshakeFailed], or [ErrConnectionRejected].
+// If ctx ends first, the endpoint is closed and ctx's cause is returned.
+func ConnectEndpoint(ctx context.Context, conn io.ReadWriteCloser, opts ...EndpointOption) (*Endpoint, error) {
+	ep := NewEndpoint(conn, opts...)
+	if _, err := ep.Peer(ctx); err != nil {
+		ep.Close()
+		return nil, err
+	}
+	return ep, nil
+}
+
+// Peer waits for the handshake with our peer and returns what the peer announced in it.
+// It returns endpoint's cancellation cause if the endpoint closed before the handshake completed (for example because the peer is not compatible with us).
+func (e *Endpoint) Peer(ctx context.Context) (PeerInfo, error) {
+	// handshake may have completed before the endpoint closed
+	select {
+	case <-e.handshakeDone:
+		return e.peer, nil
+	default:
+	}
+
+	select {
+	case <-e.handshakeDone:
+		return e.peer, nil
+	case <-e.ctx.Done():
+		return PeerInfo{}, context.Cause(e.ctx)
+	case <-ctx.Done():
+		return PeerInfo{}, context.Cause(ctx)
+	}
+}
@@ -0,0 +1,177 @@
+package irpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// sendRawHandshake writes hs to conn the way an endpoint would
+func sendRawHandshake(t *testing.T, conn io.Writer, hs handshakePacket) {
+	t.Helper()
+	fw := newFrameWriter(conn, maxFrameLen)
+	enc := irpcgen.NewEncoder(fw)
+	if err := hs.Serialize(enc); err != nil {
+		t.Errorf("Serialize(): %v", err)
+	}
+	if err := enc.Flush(); err != nil {
+		t.Errorf("Flush(): %v", err)
+	}
+	if err := fw.endMessage(); err != nil {
+		t.Errorf("endMessage(): %v", err)
+	}
+}
+
+func TestHandshakeExchangesPeerInfo(t *testing.T) {
+	c1, c2 := net.Pipe()
+	ep1 := NewEndpoint(c1, WithHello([]byte("hi from 1")))
+	defer ep1.Close()
+	ep2 := NewEndpoint(c2, WithPeerCheck(func(p PeerInfo) error {
+		if !bytes.Equal(p.Hello, []byte("hi from 1")) {
+			return errors.New("unexpected hello")
+		}
+		return nil
+	}))
+	defer ep2.Close()
+
+	peer, err := ep2.Peer(context.Background())
+	if err != nil {
+		t.Fatalf("Peer(): %v", err)
+	}
+	if peer.Version != ProtocolVersion || !peer.Features.Has(ourFeatures) || string(peer.Hello) != "hi from 1" {
+		t.Fatalf("unexpected peer info: %+v", peer)
+	}
+
+	peer, err = ep1.Peer(context.Background())
+	if err != nil {
+		t.Fatalf("Peer(): %v", err)
+	}
+	if peer.Hello != nil {
+		t.Fatalf("unexpected hello: %q", peer.Hello)
+	}
+}
+
+func TestHandshakeMismatch(t *testing.T) {
+	tests := []struct {
+		name string
+		hs   handshakePacket
+	}{
+		{"magic", handshakePacket{Magic: [4]byte{'H', 'T', 'T', 'P'}, Version: ProtocolVersion, Features: ourFeatures}},
+		{"version", handshakePacket{Magic: protocolMagic, Version: ProtocolVersion + 1, Features: ourFeatures}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c1, c2 := net.Pipe()
+			defer c2.Close()
+			ep := NewEndpoint(c1)
+			defer ep.Close()
+
+			go io.Copy(io.Discard, c2)
+			sendRawHandshake(t, c2, tt.hs)
+
+			<-ep.Context().Done()
+			if err := context.Cause(ep.Context()); !errors.Is(err, ErrHandshakeFailed) {
+				t.Fatalf("expected ErrHandshakeFailed, got: %v", err)
+			}
+			if _, err := ep.Peer(context.Background()); !errors.Is(err, ErrHandshakeFailed) {
+				t.Fatalf("Peer(): expected ErrHandshakeFailed, got: %v", err)
+			}
+		})
+	}
+}
+
+func TestConnectEndpoint(t *testing.T) {
+	c1, c2 := net.Pipe()
+	go func() {
+		ep, err := ConnectEndpoint(context.Background(), c2)
+		if err == nil {
+			defer ep.Close()
+			<-ep.Context().Done()
+		}
+	}()
+	ep, err := ConnectEndpoint(context.Background(), c1)
+	if err != nil {
+		t.Fatalf("ConnectEndpoint(): %v", err)
+	}
+	ep.Close()
+
+	// version mismatch is returned instead of the endpoint
+	c1, c2 = net.Pipe()
+	defer c2.Close()
+	go io.Copy(io.Discard, c2)
+	go sendRawHandshake(t, c2, handshakePacket{Magic: protocolMagic, Version: ProtocolVersion + 1, Features: ourFeatures})
+	if _, err := ConnectEndpoint(context.Background(), c1); !errors.Is(err, ErrHandshakeFailed) {
+		t.Fatalf("expected ErrHandshakeFailed, got: %v", err)
+	}
+}
+
+func TestHandshakeWithoutFeatures(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	ep := NewEndpoint(c1, WithServiceNegotiation())
+	defer ep.Close()
+
+	// peer reads everything, but answers nothing. it would block us, if we asked it anything
+	go io.Copy(io.Discard, c2)
+	sendRawHandshake(t, c2, handshakePacket{Magic: protocolMagic, Version: ProtocolVersion})
+
+	peer, err := ep.Peer(context.Background())
+	if err != nil || peer.Features != 0 {
+		t.Fatalf("Peer(): %+v, %v", peer, err)
+	}
+	if err := ep.RegisterClient(irpcgen.ServiceId(1)); err != nil {
+		t.Fatalf("RegisterClient(): %v", err)
+	}
+	for _, err := range ep.CallRemoteStream(context.Background(), irpcgen.ServiceId(1), 0, packetHeader{}, func() irpcgen.Deserializable { return &pingPacket{} }, &pingPacket{}) {
+		if !errors.Is(err, ErrFeatureNotSupported) {
+			t.Fatalf("CallRemoteStream(): %v", err)
+		}
+	}
+}
+
+func TestRegisterClientTimeout(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	ep := NewEndpoint(c1, WithServiceNegotiation(), WithRegisterClientTimeout(20*time.Millisecond))
+	defer ep.Close()
+
+	// peer announces negotiation, but never answers
+	go io.Copy(io.Discard, c2)
+	sendRawHandshake(t, c2, ourHandshake(nil))
+
+	if err := ep.RegisterClient(irpcgen.ServiceId(1)); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("RegisterClient(): %v", err)
+	}
+}
+
+func TestHandshakeRejectedByPeerCheck(t *testing.T) {
+	c1, c2 := net.Pipe()
+	errWrongApp := errors.New("wrong app")
+	ep1 := NewEndpoint(c1, WithHello([]byte("app A")))
+	defer ep1.Close()
+	ep2 := NewEndpoint(c2, WithPeerCheck(func(p PeerInfo) error {
+		if string(p.Hello) != "app B" {
+			return errWrongApp
+		}
+		return nil
+	}))
+	defer ep2.Close()
+
+	<-ep2.Context().Done()
+	if err := context.Cause(ep2.Context()); !errors.Is(err, ErrHandshakeFailed) || !errors.Is(err, errWrongApp) {
+		t.Fatalf("unexpected cause: %v", err)
+	}
+	// the rejected side learns why
+	<-ep1.Context().Done()
+	if err := context.Cause(ep1.Context()); !errors.Is(err, ErrConnectionRejected) || !strings.Contains(err.Error(), errWrongApp.Error()) {
+		t.Fatalf("unexpected cause of rejected endpoint: %v", err)
+	}
+}
@@ -0,0 +1,83 @@
+package irpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// ErrCallRejected is returned by calls, that were refused by a [ServerInterceptor] on the peer's side.
+var ErrCallRejected = errors.New("irpc: call rejected")
+
+// CallInfo identifies the function being called.
+type CallInfo struct {
+	ServiceId irpcgen.ServiceId
+	FuncId    irpcgen.FuncId
+	Method    string               // "Interface.Method" name of the function. empty if unknown
+	Service   *irpcgen.ServiceDesc // description of the called service. nil if unknown
+}
+
+// desc can be nil
+func newCallInfo(serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, desc *irpcgen.ServiceDesc) CallInfo {
+	call := CallInfo{ServiceId: serviceId, FuncId: funcId, Service: desc}
+	if desc != nil {
+		call.Method = desc.MethodName(funcId)
+	}
+	return call
+}
+
+// ClientInvoker performs the call. resp is filled in, if it returns nil.
+type ClientInvoker func(ctx context.Context, call CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable) error
+
+// ClientInterceptor wraps our calls to the peer. It has to call invoke to actually make the call.
+// For functions streaming their result, invoke returns once the stream ends, or its consumer stops iterating.
+// It may be called at most once for a streaming call.
+type ClientInterceptor func(ctx context.Context, call CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable, invoke ClientInvoker) error
+
+// ServerHandler executes the service function and returns its response.
+type ServerHandler func(ctx context.Context) (irpcgen.Serializable, error)
+
+// ServerInterceptor wraps execution of our service functions called by the peer.
+// It has to call handler to actually execute the function.
+// For functions streaming their result, handler returns once all the stream's items were sent.
+// If it returns an error, the caller receives [ErrCallRejected] with the error's message instead of the response.
+type ServerInterceptor func(ctx context.Context, call CallInfo, handler ServerHandler) (irpcgen.Serializable, error)
+
+// chainClientInterceptors makes invoker, that runs the interceptors in order, the first being the outermost
+func chainClientInterceptors(interceptors []ClientInterceptor, invoke ClientInvoker) ClientInvoker {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, call CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable) error {
+			return interceptor(ctx, call, req, resp, next)
+		}
+	}
+	return invoke
+}
+
+// chainServerInterceptors makes handler, that runs the interceptors in order, the first being the outermost
+func chainServerInterceptors(interceptors []ServerInterceptor, call CallInfo, handler ServerHandler) ServerHandler {
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], handler
+		handler = func(ctx context.Context) (irpcgen.Serializable, error) {
+			return interceptor(ctx, call, next)
+		}
+	}
+	return handler
+}
+
+// WithClientInterceptors adds interceptors wrapping each call we make to the peer.
+// Interceptors run in the given order, the first one being the outermost.
+func WithClientInterceptors(interceptors ...ClientInterceptor) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.clientInterceptors = append(ep.clientInterceptors, interceptors...)
+	}
+}
+
+// WithServerInterceptors adds interceptors wrapping each execution of our services' functions for the peer.
+// Interceptors run in the given order, the first one being the outermost.
+func WithServerInterceptors(interceptors ...ServerInterceptor) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.serverInterceptors = append(ep.serverInterceptors, interceptors...)
+	}
+}
@@ -8,18 +8,42 @@ import (
 	"math"
 )
 
+// MaxZeroSizeLen limits the length of decoded slices and maps, whose elements take up no data at all (ex: []struct{}).
+// Remaining data cannot limit their length, so without it, peer could make us iterate through an arbitrary number of elements.
+var MaxZeroSizeLen = 4 * 1024 * 1024
+
 // Decoder is a binary decoder that reads various data types from an io.Reader.
 //
 // It is meant to be used by generated code to decode messages in the IRPC protocol.
 type Decoder struct {
-	r   *bufio.Reader
-	buf []byte
+	r         byteReader
+	remaining lenReader // nil if we don't know, how much data is left
+	buf       []byte
+}
+
+type byteReader interface {
+	io.Reader
+	io.ByteReader
+}
+
+// lenReader is implemented by readers, that know how many bytes are left to read. (ex: [bytes.Reader], or endpoint's message reader)
+type lenReader interface {
+	Len() int
 }
 
+// NewDecoder returns a decoder reading from r.
+// If r is not an [io.ByteReader], it is buffered.
+// If r knows how many bytes it has left (implements Len() int), decoder refuses lengths of slices, maps and strings, that the remaining data couldn't possibly hold.
 func NewDecoder(r io.Reader) *Decoder {
+	br, ok := r.(byteReader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	remaining, _ := br.(lenReader)
 	return &Decoder{
-		r:   bufio.NewReader(r),
-		buf: make([]byte, binary.MaxVarintLen64),
+		r:         br,
+		remaining: remaining,
+		buf:       make([]byte, binary.MaxVarintLen64),
 	}
 }
 
@@ -81,11 +105,40 @@ func (d *Decoder) uVarInt64() (uint64, error) {
 	return binary.ReadUvarint(d.r)
 }
 
+// len reads length of a collection, whose every element is encoded with at least one byte
 func (d *Decoder) len() (int, error) {
+	return d.lenOfElems(8)
+}
+
+// bitLen reads length of a collection with elements encoded as single bits
+func (d *Decoder) bitLen() (int, error) {
+	return d.lenOfElems(1)
+}
+
+// zeroSizeLen reads length of a collection, whose elements take up no space at all. it is limited by MaxZeroSizeLen
+func (d *Decoder) zeroSizeLen() (int, error) {
+	return d.lenOfElems(0)
+}
+
+// lenOfElems reads collection length and makes sure, that the remaining data can hold that many elements of elemBits size
+func (d *Decoder) lenOfElems(elemBits uint64) (int, error) {
 	l64, err := d.uVarInt64()
 	if err != nil {
 		return 0, fmt.Errorf("slice len: %w", err)
 	}
+	if l64 > math.MaxInt {
+		return 0, fmt.Errorf("len %d is too big", l64)
+	}
+	if elemBits == 0 && l64 > uint64(MaxZeroSizeLen) {
+		return 0, fmt.Errorf("len %d of zero size elements exceeds %d", l64, MaxZeroSizeLen)
+	}
+	if d.remaining != nil && elemBits > 0 {
+		remaining := uint64(d.remaining.Len())
+		// l64*elemBits could overflow. we compare number of bytes needed instead
+		if l64/8*elemBits+(l64%8*elemBits+7)/8 > remaining {
+			return 0, fmt.Errorf("len %d exceeds remaining %d bytes of data", l64, remaining)
+		}
+	}
 	return int(l64), nil
 }
 
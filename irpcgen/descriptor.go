@@ -0,0 +1,65 @@
+package irpcgen
+
+// ServiceDesc statically describes a generated service.
+// Generated code provides it, so that interceptors, logs and other tools can name the called functions without reflection.
+type ServiceDesc struct {
+	Id        ServiceId
+	Name      string       // name of the source interface. ex: "KVStore"
+	Interface string       // fully qualified source interface. ex: "github.com/user/kv.KVStore"
+	Methods   []MethodDesc // indexed by FuncId
+}
+
+// MethodDesc describes one method of a service's interface.
+type MethodDesc struct {
+	Name       string
+	Params     []ParamDesc // all parameters, including context.Context
+	Results    []ParamDesc
+	HasContext bool // method takes context.Context parameter
+	Idempotent bool // method is marked with //irpc:idempotent directive. it is safe to be called repeatedly and can be retried
+}
+
+// ParamDesc describes a parameter or a result of a method.
+type ParamDesc struct {
+	Name string // empty for unnamed results
+	Type string // type as written in the generated code. ex: "[]byte", "time.Time"
+}
+
+// Method returns the description of the function with given id.
+func (sd *ServiceDesc) Method(funcId FuncId) (*MethodDesc, bool) {
+	if funcId >= FuncId(len(sd.Methods)) {
+		return nil, false
+	}
+	return &sd.Methods[funcId], true
+}
+
+// MethodName returns "Interface.Method" name of the function with given id, or empty string if there is no such function.
+func (sd *ServiceDesc) MethodName(funcId FuncId) string {
+	m, ok := sd.Method(funcId)
+	if !ok {
+		return ""
+	}
+	return sd.Name + "." + m.Name
+}
+
+// DescribedService is a [Service] providing its description. Generated services implement it.
+type DescribedService interface {
+	Service
+	Descriptor() *ServiceDesc
+}
+
+// DescribedEndpoint is an [Endpoint], that wants descriptions of services its clients call.
+type DescribedEndpoint interface {
+	Endpoint
+	// RegisterDescribedClient replaces [Endpoint.RegisterClient] for clients with known description.
+	RegisterDescribedClient(desc *ServiceDesc) error
+}
+
+// RegisterClient registers client of the described service with the endpoint.
+// The description is passed on, if the endpoint is a [DescribedEndpoint].
+// Generated client constructors call it.
+func RegisterClient(endpoint Endpoint, desc *ServiceDesc) error {
+	if de, ok := endpoint.(DescribedEndpoint); ok {
+		return de.RegisterDescribedClient(desc)
+	}
+	return endpoint.RegisterClient(desc.Id)
+}
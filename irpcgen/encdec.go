@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"unsafe"
 )
 
 func EncBool[T ~bool](enc *Encoder, v T) error {
@@ -221,7 +222,8 @@ func DecSlice[S ~[]E, E any](dec *Decoder, sl *S, elemType string, elemDecFnc fu
 		*sl = nil
 		return nil
 	}
-	l, err := dec.len()
+	var zero E
+	l, err := collectionLen(dec, unsafe.Sizeof(zero))
 	if err != nil {
 		return fmt.Errorf("deserialize slice len: %w", err)
 	}
@@ -235,6 +237,37 @@ func DecSlice[S ~[]E, E any](dec *Decoder, sl *S, elemType string, elemDecFnc fu
 	return nil
 }
 
+// collectionLen reads length of slice or map.
+// elements of zero size (ex: struct{}) take up no data, so their count is not limited by the remaining data
+func collectionLen(dec *Decoder, elemSize uintptr) (int, error) {
+	if elemSize == 0 {
+		return dec.zeroSizeLen()
+	}
+	return dec.len()
+}
+
+// EncArray serializes elements of a fixed-size array.
+// Array length is known statically on both sides, so no length prefix is written.
+// Generated code passes the array as a slice (arr[:]).
+func EncArray[E any](enc *Encoder, arr []E, elemType string, elemEncFnc func(enc *Encoder, v E) error) error {
+	for _, e := range arr {
+		if err := elemEncFnc(enc, e); err != nil {
+			return fmt.Errorf("serialize array element of type %q: %w", elemType, err)
+		}
+	}
+	return nil
+}
+
+// DecArray deserializes len(arr) elements into a fixed-size array passed as slice (arr[:]).
+func DecArray[E any](dec *Decoder, arr []E, elemType string, elemDecFnc func(*Decoder, *E) error) error {
+	for i := range arr {
+		if err := elemDecFnc(dec, &arr[i]); err != nil {
+			return fmt.Errorf("deserialize array element of type %q: %w", elemType, err)
+		}
+	}
+	return nil
+}
+
 func EncMap[M ~map[K]V, K comparable, V any](enc *Encoder, m M, kType string, kEncFunc func(*Encoder, K) error, vType string, vEncFunc func(*Encoder, V) error) error {
 	if m == nil {
 		if err := enc.isNil(true); err != nil {
@@ -268,7 +301,9 @@ func DecMap[M ~map[K]V, K comparable, V any](dec *Decoder, m *M, kName string, k
 		*m = nil
 		return nil
 	}
-	l, err := dec.len()
+	var zeroK K
+	var zeroV V
+	l, err := collectionLen(dec, unsafe.Sizeof(zeroK)+unsafe.Sizeof(zeroV))
 	if err != nil {
 		return fmt.Errorf("deserialize map len: %w", err)
 	}
@@ -420,7 +455,7 @@ func DecBoolSlice[T ~[]bool](dec *Decoder, v *T) error {
 		return nil
 	}
 
-	l, err := dec.len()
+	l, err := dec.bitLen()
 	if err != nil {
 		return fmt.Errorf("slice len: %w", err)
 	}
@@ -90,3 +90,90 @@ func TestEncDecPreinitializedPointer(t *testing.T) {
 	}
 	t.Logf("val == %d", val)
 }
+
+func TestEncDecArray(t *testing.T) {
+	buf := bytes.NewBuffer(nil)
+	enc := NewEncoder(buf)
+	dec := NewDecoder(buf)
+
+	a := [3]int{1, -2, 300}
+	if err := EncArray(enc, a[:], "int", EncInt); err != nil {
+		t.Fatalf("EncArray: %+v", err)
+	}
+	enc.Flush()
+
+	// no length prefix - each of our ints fits into 1 or 2 bytes
+	if buf.Len() != 1+1+2 {
+		t.Fatalf("unexpected encoded len %d: %v", buf.Len(), buf.Bytes())
+	}
+
+	var r [3]int
+	if err := DecArray(dec, r[:], "int", DecInt); err != nil {
+		t.Fatalf("DecArray: %+v", err)
+	}
+	if a != r {
+		t.Fatalf("a != r: %v != %v", a, r)
+	}
+}
+
+func TestDecoderRefusesLenBeyondData(t *testing.T) {
+	// slice announcing 2^60 elements, followed by a single byte
+	buf := bytes.NewBuffer(nil)
+	enc := NewEncoder(buf)
+	enc.isNil(false)
+	enc.uVarInt64(1 << 60)
+	enc.byte(1)
+	enc.Flush()
+	data := buf.Bytes()
+
+	var ints []int
+	if err := DecSlice(NewDecoder(bytes.NewReader(data)), &ints, "int", DecInt); err == nil {
+		t.Fatalf("DecSlice() accepted len beyond data")
+	}
+	var bs []byte
+	if err := DecByteSlice(NewDecoder(bytes.NewReader(data)), &bs); err == nil {
+		t.Fatalf("DecByteSlice() accepted len beyond data")
+	}
+	var m map[int]int
+	if err := DecMap(NewDecoder(bytes.NewReader(data)), &m, "int", DecInt, "int", DecInt); err == nil {
+		t.Fatalf("DecMap() accepted len beyond data")
+	}
+	var bools []bool
+	if err := DecBoolSlice(NewDecoder(bytes.NewReader(data)), &bools); err == nil {
+		t.Fatalf("DecBoolSlice() accepted len beyond data")
+	}
+
+	// 8 bools fit into the single byte
+	buf.Reset()
+	enc.isNil(false)
+	enc.uVarInt64(8)
+	enc.byte(0xff)
+	enc.Flush()
+	if err := DecBoolSlice(NewDecoder(bytes.NewReader(buf.Bytes())), &bools); err != nil || len(bools) != 8 {
+		t.Fatalf("DecBoolSlice(): %v, %v", bools, err)
+	}
+
+	// zero sized elements take no space at all
+	buf.Reset()
+	enc.isNil(false)
+	enc.uVarInt64(1000)
+	enc.Flush()
+	var empties []struct{}
+	if err := DecSlice(NewDecoder(bytes.NewReader(buf.Bytes())), &empties, "struct{}", func(*Decoder, *struct{}) error { return nil }); err != nil || len(empties) != 1000 {
+		t.Fatalf("DecSlice() of empty structs: %d, %v", len(empties), err)
+	}
+
+	// but not arbitrarily many of them
+	buf.Reset()
+	enc.isNil(false)
+	enc.uVarInt64(uint64(MaxZeroSizeLen) + 1)
+	enc.Flush()
+	if err := DecSlice(NewDecoder(bytes.NewReader(buf.Bytes())), &empties, "struct{}", func(*Decoder, *struct{}) error { return nil }); err == nil {
+		t.Fatalf("DecSlice() accepted %d empty structs", len(empties))
+	}
+	var set map[struct{}]struct{}
+	noop := func(*Decoder, *struct{}) error { return nil }
+	if err := DecMap(NewDecoder(bytes.NewReader(buf.Bytes())), &set, "struct{}", noop, "struct{}", noop); err == nil {
+		t.Fatalf("DecMap() accepted %d empty structs", len(set))
+	}
+}
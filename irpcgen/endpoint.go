@@ -1,6 +1,9 @@
 package irpcgen
 
-import "context"
+import (
+	"context"
+	"iter"
+)
 
 // Endpoint represents one side of an active RPC connection.
 // Each Endpoint communicates with one peer Endpoint on the other side.
@@ -8,5 +11,11 @@ type Endpoint interface {
 	// RegisterClient registers a client with the peer Endpoint.
 	RegisterClient(serviceId ServiceId) error
 	// CallRemoteFunc invokes a function on the peer Endpoint.
+	// If params is a [StreamSerializable], its items are streamed to the peer after the request.
 	CallRemoteFunc(ctx context.Context, serviceId ServiceId, funcId FuncId, params Serializable, resp Deserializable) error
+	// CallRemoteStream invokes a server-streaming function on the peer Endpoint.
+	// Streamed items are deserialized into values obtained from newItem and yielded lazily.
+	// Once the returned sequence finishes without error, resp contains the function's final response.
+	// A failed call yields a single non-nil error as its last element.
+	CallRemoteStream(ctx context.Context, serviceId ServiceId, funcId FuncId, params Serializable, newItem func() Deserializable, resp Deserializable) iter.Seq2[Deserializable, error]
 }
@@ -0,0 +1,234 @@
+package irpcgen
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// maxErrorDepth limits how deep chain of wrapped errors is transported. deeper errors are dropped
+const maxErrorDepth = 32
+
+// errorRegistration is either sentinel error value, or error type
+type errorRegistration struct {
+	sentinel error
+	typ      reflect.Type
+}
+
+var errorRegistry = struct {
+	byCode     map[string]errorRegistration
+	sentinels  map[error]string
+	errorTypes map[reflect.Type]string
+	m          sync.RWMutex
+}{
+	byCode:     make(map[string]errorRegistration),
+	sentinels:  make(map[error]string),
+	errorTypes: make(map[reflect.Type]string),
+}
+
+// RegisterError registers sentinel error under a stable code, so that [errors.Is] works with the error on the other side of connection.
+// Both sides have to register the same error with the same code. Typically, it is done in init() of the package declaring the error:
+//
+//	var ErrNotFound = errors.New("not found")
+//
+//	func init() {
+//		irpcgen.RegisterError("kv.NotFound", ErrNotFound)
+//	}
+//
+// RegisterError panics if the code is already registered, or the error is nil or not comparable.
+func RegisterError(code string, sentinel error) {
+	if sentinel == nil {
+		panic(fmt.Sprintf("irpcgen: sentinel error %q is nil", code))
+	}
+	if !reflect.TypeOf(sentinel).Comparable() {
+		panic(fmt.Sprintf("irpcgen: sentinel error %q of type %T is not comparable", code, sentinel))
+	}
+
+	errorRegistry.m.Lock()
+	defer errorRegistry.m.Unlock()
+
+	checkErrorCode(code)
+	errorRegistry.byCode[code] = errorRegistration{sentinel: sentinel}
+	errorRegistry.sentinels[sentinel] = code
+}
+
+// RegisterErrorType registers error type T under a stable code, so that [errors.As] works with the error on the other side of connection.
+// Exported fields of the error are transported in JSON form. T is usually a pointer to struct:
+//
+//	irpcgen.RegisterErrorType[*QuotaError]("kv.Quota")
+//
+// RegisterErrorType panics if the code is already registered.
+func RegisterErrorType[T error](code string) {
+	typ := reflect.TypeFor[T]()
+
+	errorRegistry.m.Lock()
+	defer errorRegistry.m.Unlock()
+
+	checkErrorCode(code)
+	errorRegistry.byCode[code] = errorRegistration{typ: typ}
+	errorRegistry.errorTypes[typ] = code
+}
+
+// checkErrorCode panics on empty or already registered code. caller holds the lock
+func checkErrorCode(code string) {
+	if code == "" {
+		panic("irpcgen: empty error code")
+	}
+	if _, found := errorRegistry.byCode[code]; found {
+		panic(fmt.Sprintf("irpcgen: error code %q is already registered", code))
+	}
+}
+
+// errorCode returns the code of a registered error or "" if it's not registered
+func errorCode(err error) string {
+	errorRegistry.m.RLock()
+	defer errorRegistry.m.RUnlock()
+
+	typ := reflect.TypeOf(err)
+	if typ.Comparable() {
+		if code, found := sentinelCode(err); found {
+			return code
+		}
+	}
+	return errorRegistry.errorTypes[typ]
+}
+
+// sentinelCode looks err up among registered sentinels. caller holds the lock
+// comparable type may still hold an unhashable value in an interface field, which panics the map lookup. such error is no sentinel
+func sentinelCode(err error) (code string, found bool) {
+	defer func() {
+		if recover() != nil {
+			code, found = "", false
+		}
+	}()
+	code, found = errorRegistry.sentinels[err]
+	return code, found
+}
+
+func errorRegistrationByCode(code string) (errorRegistration, bool) {
+	errorRegistry.m.RLock()
+	defer errorRegistry.m.RUnlock()
+
+	reg, found := errorRegistry.byCode[code]
+	return reg, found
+}
+
+// RemoteError is an error received from the peer, that wasn't registered with [RegisterError] or [RegisterErrorType].
+// It keeps the original message and the chain of wrapped errors, so that registered errors within it still match [errors.Is] and [errors.As].
+type RemoteError struct {
+	Msg     string
+	Wrapped []error
+}
+
+func (e *RemoteError) Error() string {
+	return e.Msg
+}
+
+func (e *RemoteError) Unwrap() []error {
+	return e.Wrapped
+}
+
+// EncError encodes error with its registered code, its fields and the chain of errors it wraps.
+func EncError(enc *Encoder, err error) error {
+	return encError(enc, err, 0)
+}
+
+func encError(enc *Encoder, err error, depth int) error {
+	isNil := err == nil
+	if err := EncIsNil(enc, isNil); err != nil {
+		return fmt.Errorf("serialize isNil == %t: %w", isNil, err)
+	}
+	if isNil {
+		return nil
+	}
+
+	code := errorCode(err)
+	if err := EncString(enc, code); err != nil {
+		return fmt.Errorf("serialize error code: %w", err)
+	}
+	if err := EncString(enc, err.Error()); err != nil {
+		return fmt.Errorf("serialize error message: %w", err)
+	}
+
+	var fields []byte
+	if reg, found := errorRegistrationByCode(code); found && reg.typ != nil {
+		// error, that can't be marshaled, still gets to the peer. without fields, it arrives as *RemoteError
+		if marshaled, jsonErr := json.Marshal(err); jsonErr == nil {
+			fields = marshaled
+		}
+	}
+	if err := EncByteSlice(enc, fields); err != nil {
+		return fmt.Errorf("serialize error fields: %w", err)
+	}
+
+	var wrapped []error
+	if depth < maxErrorDepth {
+		switch u := err.(type) {
+		case interface{ Unwrap() error }:
+			if w := u.Unwrap(); w != nil {
+				wrapped = []error{w}
+			}
+		case interface{ Unwrap() []error }:
+			wrapped = u.Unwrap()
+		}
+	}
+	return EncSlice(enc, wrapped, "error", func(enc *Encoder, w error) error {
+		return encError(enc, w, depth+1)
+	})
+}
+
+// DecError decodes error encoded by [EncError].
+// Registered errors are rebuilt as their original values or types. Others become [*RemoteError].
+func DecError(dec *Decoder, err *error) error {
+	return decError(dec, err, 0)
+}
+
+func decError(dec *Decoder, dst *error, depth int) error {
+	var isNil bool
+	if err := DecIsNil(dec, &isNil); err != nil {
+		return fmt.Errorf("deserialize isNil: %w", err)
+	}
+	if isNil {
+		*dst = nil
+		return nil
+	}
+
+	var code, msg string
+	if err := DecString(dec, &code); err != nil {
+		return fmt.Errorf("deserialize error code: %w", err)
+	}
+	if err := DecString(dec, &msg); err != nil {
+		return fmt.Errorf("deserialize error message: %w", err)
+	}
+	var fields []byte
+	if err := DecByteSlice(dec, &fields); err != nil {
+		return fmt.Errorf("deserialize error fields: %w", err)
+	}
+
+	var wrapped []error
+	if err := DecSlice(dec, &wrapped, "error", func(dec *Decoder, w *error) error {
+		if depth >= maxErrorDepth {
+			return fmt.Errorf("wrapped errors exceed max depth %d", maxErrorDepth)
+		}
+		return decError(dec, w, depth+1)
+	}); err != nil {
+		return err
+	}
+
+	if reg, found := errorRegistrationByCode(code); found {
+		if reg.sentinel != nil {
+			*dst = reg.sentinel
+			return nil
+		}
+		// fields that don't fit the type (ie peer's type differs from ours) degrade the error to *RemoteError
+		v := reflect.New(reg.typ)
+		if err := json.Unmarshal(fields, v.Interface()); err == nil {
+			*dst = v.Elem().Interface().(error)
+			return nil
+		}
+	}
+
+	*dst = &RemoteError{Msg: msg, Wrapped: wrapped}
+	return nil
+}
@@ -0,0 +1,151 @@
+package irpcgen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+var errTestNotFound = errors.New("not found")
+
+type testQuotaError struct {
+	Limit int
+}
+
+func (e *testQuotaError) Error() string {
+	return fmt.Sprintf("quota %d exceeded", e.Limit)
+}
+
+// testChanError cannot be marshaled to JSON
+type testChanError struct {
+	C chan int
+}
+
+func (e *testChanError) Error() string {
+	return "chan error"
+}
+
+// testAnyError is comparable, but its field may hold unhashable value
+type testAnyError struct {
+	v any
+}
+
+func (e testAnyError) Error() string {
+	return fmt.Sprintf("any error %v", e.v)
+}
+
+func init() {
+	RegisterError("irpcgen.test.NotFound", errTestNotFound)
+	RegisterErrorType[*testQuotaError]("irpcgen.test.Quota")
+	RegisterErrorType[*testChanError]("irpcgen.test.Chan")
+}
+
+func encDecError(t *testing.T, err error) error {
+	t.Helper()
+	buf := bytes.NewBuffer(nil)
+	enc := NewEncoder(buf)
+	dec := NewDecoder(buf)
+
+	if err := EncError(enc, err); err != nil {
+		t.Fatalf("EncError(): %+v", err)
+	}
+	enc.Flush()
+
+	var res error
+	if err := DecError(dec, &res); err != nil {
+		t.Fatalf("DecError(): %+v", err)
+	}
+	return res
+}
+
+func TestEncDecError(t *testing.T) {
+	if res := encDecError(t, nil); res != nil {
+		t.Fatalf("nil error decoded as %v", res)
+	}
+
+	// sentinel is decoded as itself
+	if res := encDecError(t, errTestNotFound); res != errTestNotFound {
+		t.Fatalf("unexpected sentinel: %v", res)
+	}
+
+	// registered type with its fields
+	var qe *testQuotaError
+	if res := encDecError(t, &testQuotaError{Limit: 5}); !errors.As(res, &qe) || qe.Limit != 5 {
+		t.Fatalf("unexpected quota error: %#v", res)
+	}
+
+	// unregistered errors keep their messages and wrapped chains
+	orig := fmt.Errorf("get %q: %w", "key", errors.Join(errTestNotFound, &testQuotaError{Limit: 7}))
+	res := encDecError(t, orig)
+	if res.Error() != orig.Error() {
+		t.Fatalf("message %q differs from %q", res.Error(), orig.Error())
+	}
+	if !errors.Is(res, errTestNotFound) {
+		t.Fatalf("errors.Is(%v, errTestNotFound) == false", res)
+	}
+	if !errors.As(res, &qe) || qe.Limit != 7 {
+		t.Fatalf("errors.As(%v, *testQuotaError) failed", res)
+	}
+	var re *RemoteError
+	if !errors.As(res, &re) {
+		t.Fatalf("unregistered error is %T, not *RemoteError", res)
+	}
+}
+
+func TestRegisterErrorDuplicateCode(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Fatalf("duplicate code registration didn't panic")
+		}
+	}()
+	RegisterError("irpcgen.test.NotFound", errors.New("other"))
+}
+
+func TestErrorWithBadFieldsDegradesToRemoteError(t *testing.T) {
+	var re *RemoteError
+
+	// fields can't be marshaled
+	res := encDecError(t, fmt.Errorf("wrapped: %w", &testChanError{C: make(chan int)}))
+	if !errors.As(res, &re) || len(re.Wrapped) != 1 || re.Wrapped[0].Error() != "chan error" {
+		t.Fatalf("unexpected error: %#v", res)
+	}
+	if _, ok := re.Wrapped[0].(*RemoteError); !ok {
+		t.Fatalf("error without fields is %T, not *RemoteError", re.Wrapped[0])
+	}
+
+	// peer's fields don't fit our type
+	buf := bytes.NewBuffer(nil)
+	enc := NewEncoder(buf)
+	EncIsNil(enc, false)
+	EncString(enc, "irpcgen.test.Quota")
+	EncString(enc, "quota exceeded")
+	EncByteSlice(enc, []byte(`{"Limit":"five"}`))
+	EncSlice(enc, []error(nil), "error", func(*Encoder, error) error { return nil })
+	enc.Flush()
+
+	if err := DecError(NewDecoder(buf), &res); err != nil {
+		t.Fatalf("DecError(): %v", err)
+	}
+	if !errors.As(res, &re) || res.Error() != "quota exceeded" {
+		t.Fatalf("unexpected error: %#v", res)
+	}
+}
+
+func TestRegisterNilError(t *testing.T) {
+	defer func() {
+		if r := recover(); r == nil || !strings.Contains(fmt.Sprint(r), "is nil") {
+			t.Fatalf("unexpected panic: %v", r)
+		}
+	}()
+	RegisterError("irpcgen.test.Nil", nil)
+}
+
+func TestUnhashableErrorDegradesToRemoteError(t *testing.T) {
+	res := encDecError(t, testAnyError{[]int{1}})
+	var re *RemoteError
+	if !errors.As(res, &re) || res.Error() != "any error [1]" {
+		t.Fatalf("unexpected error: %#v", res)
+	}
+}
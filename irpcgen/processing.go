@@ -44,6 +44,12 @@ type Deserializable interface {
 	Deserialize(d *Decoder) error
 }
 
+// ErrorResult is implemented by generated responses of functions returning error.
+// The function's error travels within the response, rather than as the call's error, so interceptors use ErrorResult to learn about it.
+type ErrorResult interface {
+	ResultError() error
+}
+
 // FuncExecutor wraps a function call and returns its result as Serializable.
 type FuncExecutor func(ctx context.Context) Serializable
 
@@ -0,0 +1,163 @@
+package irpcgen
+
+import (
+	"context"
+	"iter"
+)
+
+// ItemSender sends one streamed item to the peer.
+// It returns an error if the item cannot be sent, or if the call's context ended.
+type ItemSender func(item Serializable) error
+
+// StreamSerializable is a Serializable with attached stream of items.
+//
+// As a response of a server-streaming function, the endpoint first streams all the items and serializes the response itself afterwards.
+// As a request of a function with streamed parameter, the endpoint serializes the request first and streams the items afterwards.
+type StreamSerializable interface {
+	Serializable
+	SendItems(ctx context.Context, send ItemSender)
+}
+
+// NewStreamSerializable returns [StreamSerializable] that serializes data and streams items with sendItems.
+func NewStreamSerializable(data Serializable, sendItems func(ctx context.Context, send ItemSender)) StreamSerializable {
+	return streamSerializable{data: data, sendItems: sendItems}
+}
+
+type streamSerializable struct {
+	data      Serializable
+	sendItems func(ctx context.Context, send ItemSender)
+}
+
+func (s streamSerializable) Serialize(e *Encoder) error { return s.data.Serialize(e) }
+func (s streamSerializable) SendItems(ctx context.Context, send ItemSender) {
+	s.sendItems(ctx, send)
+}
+
+// ResultError returns error of the data, once the items were sent. See [ErrorResult].
+func (s streamSerializable) ResultError() error {
+	if r, ok := s.data.(ErrorResult); ok {
+		return r.ResultError()
+	}
+	return nil
+}
+
+// SendSeq sends all values produced by seq, each wrapped with newItem.
+// It stops at the first send error and returns it.
+func SendSeq[T any](seq iter.Seq[T], newItem func(T) Serializable, send ItemSender) error {
+	if seq == nil {
+		return nil
+	}
+	for v := range seq {
+		if err := send(newItem(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendSeq2 sends all values produced by seq, each wrapped with newItem.
+// It stops at the first error produced either by seq or by send and returns it.
+func SendSeq2[T any](seq iter.Seq2[T, error], newItem func(T) Serializable, send ItemSender) error {
+	if seq == nil {
+		return nil
+	}
+	for v, err := range seq {
+		if err != nil {
+			return err
+		}
+		if err := send(newItem(v)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SendChan sends all values received from ch, each wrapped with newItem, until ch is closed.
+// It stops early if ctx ends, or if send fails.
+func SendChan[T any](ctx context.Context, ch <-chan T, newItem func(T) Serializable, send ItemSender) error {
+	if ch == nil {
+		return nil
+	}
+	for {
+		select {
+		case v, ok := <-ch:
+			if !ok {
+				return nil
+			}
+			if err := send(newItem(v)); err != nil {
+				return err
+			}
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+// ParamStream provides items of a streamed parameter, that the peer sends after the request.
+type ParamStream interface {
+	// Items yields items as they arrive, deserialized into values obtained from newItem.
+	// Items can only be consumed once. The sequence ends when the peer stops sending, or when the call's context ends.
+	Items(newItem func() Deserializable) iter.Seq[Deserializable]
+}
+
+type streamErrorHandlerCtxKey struct{}
+
+// ContextWithStreamErrorHandler returns a copy of ctx, that makes generated clients report errors of streamed results of type <-chan T to handler.
+// Such channel is closed early on any error (ie lost connection), so without the handler, a truncated stream looks like a complete one.
+// The handler is called at most once per call, before the channel is closed.
+func ContextWithStreamErrorHandler(ctx context.Context, handler func(error)) context.Context {
+	return context.WithValue(ctx, streamErrorHandlerCtxKey{}, handler)
+}
+
+// ReportStreamError passes err to the handler set by [ContextWithStreamErrorHandler], if there is any.
+// It is used by generated clients.
+func ReportStreamError(ctx context.Context, err error) {
+	if handler, ok := ctx.Value(streamErrorHandlerCtxKey{}).(func(error)); ok {
+		handler(err)
+	}
+}
+
+type paramStreamCtxKey struct{}
+
+// ContextWithParamStream returns a copy of ctx carrying ps. It is used by the endpoint executing the function.
+func ContextWithParamStream(ctx context.Context, ps ParamStream) context.Context {
+	return context.WithValue(ctx, paramStreamCtxKey{}, ps)
+}
+
+// ParamStreamFromContext returns the [ParamStream] of the call executed with ctx, or nil if there is none.
+func ParamStreamFromContext(ctx context.Context) ParamStream {
+	ps, _ := ctx.Value(paramStreamCtxKey{}).(ParamStream)
+	return ps
+}
+
+// RecvSeq returns the call's streamed parameter as a sequence of values extracted from items by value.
+func RecvSeq[T any](ctx context.Context, newItem func() Deserializable, value func(Deserializable) T) iter.Seq[T] {
+	ps := ParamStreamFromContext(ctx)
+	return func(yield func(T) bool) {
+		if ps == nil {
+			return
+		}
+		for item := range ps.Items(newItem) {
+			if !yield(value(item)) {
+				return
+			}
+		}
+	}
+}
+
+// RecvChan returns the call's streamed parameter as a channel of values extracted from items by value.
+// The channel is closed once the stream ends, or when ctx ends.
+func RecvChan[T any](ctx context.Context, newItem func() Deserializable, value func(Deserializable) T) <-chan T {
+	ch := make(chan T)
+	go func() {
+		defer close(ch)
+		for v := range RecvSeq(ctx, newItem, value) {
+			select {
+			case ch <- v:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+	return ch
+}
@@ -0,0 +1,125 @@
+package irpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrPeerUnresponsive is the cause (wrapped in [ErrEndpointClosed]) of an endpoint, whose peer didn't answer our keepalive ping in time.
+var ErrPeerUnresponsive = errors.New("irpc: peer is unresponsive")
+
+// WithKeepalive makes the endpoint ping its peer every interval.
+// If the peer neither answers, nor sends us anything else within timeout, the endpoint is closed with [ErrPeerUnresponsive].
+// Measured round trip time is available through [Endpoint.RTT].
+func WithKeepalive(interval, timeout time.Duration) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.keepaliveInterval = interval
+		ep.keepaliveTimeout = timeout
+	}
+}
+
+// RTT returns round trip time measured by the last answered keepalive ping.
+// It is 0, until the first ping is answered, or if keepalive is not enabled (see [WithKeepalive]).
+func (e *Endpoint) RTT() time.Duration {
+	return time.Duration(e.rtt.Load())
+}
+
+// keepalive pings our peer until ctx ends
+func (e *Endpoint) keepalive(ctx context.Context) {
+	ticker := time.NewTicker(e.keepaliveInterval)
+	defer ticker.Stop()
+
+	for seq := uint64(1); ; seq++ {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return
+		}
+
+		if !e.ping(ctx, seq) {
+			e.terminate(errors.Join(ErrEndpointClosed, ErrPeerUnresponsive))
+			return
+		}
+	}
+}
+
+// ping sends a ping and waits for pong. returns false if peer is unresponsive
+func (e *Endpoint) ping(ctx context.Context, seq uint64) bool {
+	sent := time.Now()
+	received := e.receivedPackets.Load()
+
+	// on a dead connection, even the write may block, so we don't wait for it
+	errC := make(chan error, 1)
+	go func() {
+		errC <- e.serializePacket(packetHeader{typ: pingPacketType}, pingPacket{Seq: seq})
+	}()
+
+	timer := time.NewTimer(e.keepaliveTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case err := <-errC:
+			if err != nil {
+				e.handleIOError(fmt.Errorf("send ping: %w", err))
+				return true
+			}
+		case pong := <-e.pongC:
+			if pong.Seq != seq {
+				// late pong of previous ping
+				continue
+			}
+			e.rtt.Store(int64(time.Since(sent)))
+			return true
+		case <-timer.C:
+			// peer whose readLoop is blocked (ie by busy workers) may still be sending us responses
+			return e.receivedPackets.Load() != received
+		case <-ctx.Done():
+			return true
+		}
+	}
+}
+
+// answerPing is called by readLoop. it doesn't block the readLoop with writing
+// pings arriving faster, than we can answer them, are coalesced. peer only waits for the latest one anyway
+func (e *Endpoint) answerPing(ping pingPacket) {
+	e.pongM.Lock()
+	defer e.pongM.Unlock()
+
+	e.pendingPong = &ping
+	if !e.sendingPong {
+		e.sendingPong = true
+		go e.sendPongs()
+	}
+}
+
+// sendPongs answers pending pings until there are none
+func (e *Endpoint) sendPongs() {
+	for {
+		e.pongM.Lock()
+		ping := e.pendingPong
+		e.pendingPong = nil
+		if ping == nil {
+			e.sendingPong = false
+		}
+		e.pongM.Unlock()
+
+		if ping == nil {
+			return
+		}
+		if err := e.serializePacket(packetHeader{typ: pongPacketType}, *ping); err != nil {
+			e.handleIOError(fmt.Errorf("send pong: %w", err))
+		}
+	}
+}
+
+// receivedPong is called by readLoop
+func (e *Endpoint) receivedPong(pong pingPacket) {
+	select {
+	case e.pongC <- pong:
+	default:
+		// nobody waits for it
+	}
+}
@@ -0,0 +1,99 @@
+package irpc
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+func TestKeepaliveMeasuresRTT(t *testing.T) {
+	c1, c2 := net.Pipe()
+	ep1 := NewEndpoint(c1, WithKeepalive(10*time.Millisecond, time.Second))
+	defer ep1.Close()
+	ep2 := NewEndpoint(c2)
+	defer ep2.Close()
+
+	if rtt := ep2.RTT(); rtt != 0 {
+		t.Fatalf("RTT() without keepalive: %v", rtt)
+	}
+
+	deadline := time.Now().Add(5 * time.Second)
+	for ep1.RTT() == 0 {
+		if time.Now().After(deadline) {
+			t.Fatalf("RTT was not measured")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	if err := ep1.Context().Err(); err != nil {
+		t.Fatalf("endpoint closed: %v", context.Cause(ep1.Context()))
+	}
+}
+
+func TestKeepaliveDetectsUnresponsivePeer(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	ep := NewEndpoint(c1, WithKeepalive(10*time.Millisecond, 50*time.Millisecond))
+	defer ep.Close()
+
+	// peer handshakes, then reads everything, but never answers
+	go io.Copy(io.Discard, c2)
+	sendRawHandshake(t, c2, handshakePacket{Magic: protocolMagic, Version: ProtocolVersion, Features: ourFeatures})
+
+	select {
+	case <-ep.Context().Done():
+	case <-time.After(5 * time.Second):
+		t.Fatalf("endpoint was not closed")
+	}
+	if err := context.Cause(ep.Context()); !errors.Is(err, ErrPeerUnresponsive) || !errors.Is(err, ErrEndpointClosed) {
+		t.Fatalf("unexpected cause: %v", err)
+	}
+}
+
+func TestPingsAreCoalesced(t *testing.T) {
+	c1, c2 := net.Pipe()
+	defer c2.Close()
+	ep := NewEndpoint(c1)
+	defer ep.Close()
+
+	// we don't read from c2 yet, so the endpoint cannot send anything
+	goroutines := runtime.NumGoroutine()
+	for seq := range uint64(1000) {
+		ep.answerPing(pingPacket{Seq: seq + 1})
+	}
+	if n := runtime.NumGoroutine() - goroutines; n > 10 {
+		t.Fatalf("%d new goroutines answering pings", n)
+	}
+
+	dec := irpcgen.NewDecoder(newFrameReader(c2, DefaultMaxMessageLen))
+	var hs handshakePacket
+	if err := hs.Deserialize(dec); err != nil {
+		t.Fatalf("read handshake: %v", err)
+	}
+	// pong of the first ping may have been on the way already. the rest is answered by a single pong
+	for {
+		var h packetHeader
+		if err := h.Deserialize(dec); err != nil {
+			t.Fatalf("read header: %v", err)
+		}
+		if h.typ != pongPacketType {
+			t.Fatalf("unexpected packet type: %v", h.typ)
+		}
+		var pong pingPacket
+		if err := pong.Deserialize(dec); err != nil {
+			t.Fatalf("read pong: %v", err)
+		}
+		if pong.Seq == 1000 {
+			break
+		}
+		if pong.Seq != 1 {
+			t.Fatalf("unexpected pong: %d", pong.Seq)
+		}
+	}
+}
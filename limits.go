@@ -0,0 +1,219 @@
+package irpc
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+	"time"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// ErrConnectionRejected is the cause (wrapped in [ErrEndpointClosed]) of an endpoint, whose connection was refused by the server.
+// The server's reason follows it in the error's message.
+var ErrConnectionRejected = errors.New("irpc: connection rejected")
+
+// AcceptFilter decides, whether the server accepts a new connection, before an endpoint is created for it.
+// Returned error rejects the connection. Its message is sent to the peer as the reason.
+type AcceptFilter func(conn net.Conn) error
+
+// OverLimitPolicy determines, what the server does with connections over its limits.
+type OverLimitPolicy int
+
+const (
+	// OverLimitReject refuses the connection. Peer's endpoint closes with [ErrConnectionRejected].
+	OverLimitReject OverLimitPolicy = iota
+	// OverLimitQueue keeps the connection waiting, until another connection closes.
+	// Peer's calls wait meanwhile. Connections, that don't fit in the queue, or wait too long, are rejected (see [WithQueueLimits]).
+	OverLimitQueue
+)
+
+// DefaultMaxQueuedConnections is the number of connections, that can wait in the queue of [OverLimitQueue] policy.
+// It can be overridden for each server with [WithQueueLimits] option
+var DefaultMaxQueuedConnections = 1024
+
+// DefaultQueueTimeout is how long a connection waits in the queue of [OverLimitQueue] policy, before it is rejected.
+// It can be overridden for each server with [WithQueueLimits] option
+var DefaultQueueTimeout = 30 * time.Second
+
+// rejectLingerTimeout limits, how long we wait for rejected peer to read our reject packet
+const rejectLingerTimeout = time.Second
+
+// WithAcceptFilter makes the server consult filter for each accepted connection.
+func WithAcceptFilter(filter AcceptFilter) ServerOption {
+	return func(s *Server) {
+		s.acceptFilter = filter
+	}
+}
+
+// WithMaxConnections limits the number of connections the server serves at once. 0 means no limit.
+// Connections over the limit are handled according to [WithOverLimitPolicy].
+func WithMaxConnections(n int) ServerOption {
+	return func(s *Server) {
+		s.limiter.max = n
+	}
+}
+
+// WithMaxConnectionsPerIP limits the number of connections the server serves at once from a single remote IP address. 0 means no limit.
+// Connections over the limit are handled according to [WithOverLimitPolicy].
+func WithMaxConnectionsPerIP(n int) ServerOption {
+	return func(s *Server) {
+		s.limiter.maxPerIP = n
+	}
+}
+
+// WithOverLimitPolicy sets, what the server does with connections over its limits. Default is [OverLimitReject].
+func WithOverLimitPolicy(policy OverLimitPolicy) ServerOption {
+	return func(s *Server) {
+		s.overLimit = policy
+	}
+}
+
+// WithQueueLimits bounds the queue of connections over the limits with [OverLimitQueue] policy.
+// Connections over maxQueued are rejected right away, those waiting longer than timeout are rejected once it expires. 0 means no limit.
+// Defaults are [DefaultMaxQueuedConnections] and [DefaultQueueTimeout].
+func WithQueueLimits(maxQueued int, timeout time.Duration) ServerOption {
+	return func(s *Server) {
+		s.limiter.maxQueued = maxQueued
+		s.limiter.queueTimeout = timeout
+	}
+}
+
+// connLimiter counts connections served by the server
+type connLimiter struct {
+	max          int           // 0 means no limit
+	maxPerIP     int           // 0 means no limit
+	maxQueued    int           // 0 means no limit
+	queueTimeout time.Duration // 0 means no limit
+
+	conns  int
+	queued int // connections waiting in acquire()
+	perIP  map[string]int
+	closed bool // server is shutting down. nobody is admitted anymore
+	m      sync.Mutex
+	freed  *sync.Cond // signaled when a connection is released, or the limiter is closed
+}
+
+func newConnLimiter() *connLimiter {
+	l := &connLimiter{
+		maxQueued:    DefaultMaxQueuedConnections,
+		queueTimeout: DefaultQueueTimeout,
+		perIP:        make(map[string]int),
+	}
+	l.freed = sync.NewCond(&l.m)
+	return l
+}
+
+// tryAcquire admits a connection from ip if it's within the limits. otherwise it returns the reason
+func (l *connLimiter) tryAcquire(ip string) (string, bool) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	return l.acquireLocked(ip)
+}
+
+func (l *connLimiter) acquireLocked(ip string) (string, bool) {
+	if l.max > 0 && l.conns >= l.max {
+		return "server connection limit reached", false
+	}
+	if l.maxPerIP > 0 && l.perIP[ip] >= l.maxPerIP {
+		return "connection limit for " + ip + " reached", false
+	}
+	l.conns++
+	l.perIP[ip]++
+	return "", true
+}
+
+// acquire waits, until connection from ip can be admitted. otherwise it returns the reason
+// the reason is empty, if the limiter was closed
+func (l *connLimiter) acquire(ip string) (string, bool) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	if l.maxQueued > 0 && l.queued >= l.maxQueued {
+		return "connection queue is full", false
+	}
+	l.queued++
+	defer func() { l.queued-- }()
+
+	expired := false
+	if l.queueTimeout > 0 {
+		timer := time.AfterFunc(l.queueTimeout, func() {
+			l.m.Lock()
+			defer l.m.Unlock()
+
+			expired = true
+			l.freed.Broadcast()
+		})
+		defer timer.Stop()
+	}
+
+	for !l.closed {
+		if _, ok := l.acquireLocked(ip); ok {
+			return "", true
+		}
+		if expired {
+			return "timed out in connection queue", false
+		}
+		l.freed.Wait()
+	}
+	return "", false
+}
+
+func (l *connLimiter) release(ip string) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.conns--
+	if l.perIP[ip]--; l.perIP[ip] == 0 {
+		delete(l.perIP, ip)
+	}
+	l.freed.Broadcast()
+}
+
+// close wakes up all waiting connections and makes them give up
+func (l *connLimiter) close() {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	l.closed = true
+	l.freed.Broadcast()
+}
+
+// remoteIP returns the ip address of the connection's peer, or the whole address if it has no ip
+func remoteIP(conn net.Conn) string {
+	addr := conn.RemoteAddr()
+	if addr == nil {
+		return ""
+	}
+	if tcpAddr, ok := addr.(*net.TCPAddr); ok {
+		return tcpAddr.IP.String()
+	}
+	if host, _, err := net.SplitHostPort(addr.String()); err == nil {
+		return host
+	}
+	return addr.String()
+}
+
+// rejectConn sends peer our handshake followed by the reason of rejection and closes the connection
+func rejectConn(conn net.Conn, reason string) {
+	defer conn.Close()
+
+	conn.SetDeadline(time.Now().Add(rejectLingerTimeout))
+	frameW := newFrameWriter(conn, maxFrameLen)
+	enc := irpcgen.NewEncoder(frameW)
+	if err := writeMessage(enc, frameW, ourHandshake(nil)); err != nil {
+		return
+	}
+	if err := writeMessage(enc, frameW, packetHeader{typ: rejectPacketType}, rejectPacket{Reason: reason}); err != nil {
+		return
+	}
+
+	// peer may be sending us its handshake and calls. if we closed the connection with them unread,
+	// peer could get the connection reset, before reading our reject packet
+	if cw, ok := conn.(interface{ CloseWrite() error }); ok {
+		cw.CloseWrite()
+	}
+	io.Copy(io.Discard, conn)
+}
@@ -0,0 +1,76 @@
+package irpc
+
+import (
+	"errors"
+	"log/slog"
+	"time"
+)
+
+// DefaultSlowCallThreshold is the duration, after which calls are logged as slow. See [WithSlowCallThreshold].
+// It is 0, so slow calls are not logged by default. Long-lived streams would be reported as slow on every connection.
+var DefaultSlowCallThreshold time.Duration
+
+// WithLogger makes the endpoint log its life: termination and its cause, protocol errors, panics of service functions and slow calls.
+// Records carry attributes "remote_addr", "service", "method" and "req_num", where applicable.
+// Without a logger, endpoint logs nothing.
+func WithLogger(logger *slog.Logger) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.logger = logger
+	}
+}
+
+// WithSlowCallThreshold sets the duration, after which our calls and executions of our service functions are logged as slow.
+// 0 disables the logging. Default is [DefaultSlowCallThreshold].
+// Streaming calls are measured until their last item.
+func WithSlowCallThreshold(threshold time.Duration) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.slowCallThreshold = threshold
+	}
+}
+
+func (e *Endpoint) initLogger() {
+	if e.logger == nil {
+		// nobody is listening. we don't even measure the calls
+		e.logger = slog.New(slog.DiscardHandler)
+		e.slowCallThreshold = 0
+		return
+	}
+	if e.remoteAddr != nil {
+		e.logger = e.logger.With("remote_addr", e.remoteAddr.String())
+	}
+}
+
+// callLogAttrs returns attributes describing the call. reqNum is nil if unknown
+func callLogAttrs(call CallInfo, reqNum *reqNumT) []any {
+	service, method := call.ServiceId.String(), any(call.FuncId)
+	if call.Service != nil {
+		service = call.Service.Name
+		if m, ok := call.Service.Method(call.FuncId); ok {
+			method = m.Name
+		}
+	}
+	attrs := []any{"service", service, "method", method}
+	if reqNum != nil {
+		attrs = append(attrs, "req_num", *reqNum)
+	}
+	return attrs
+}
+
+func (e *Endpoint) logSlowCall(side CallSide, call CallInfo, reqNum *reqNumT, duration time.Duration) {
+	if e.slowCallThreshold <= 0 || duration < e.slowCallThreshold {
+		return
+	}
+	e.logger.Warn("slow call", append(callLogAttrs(call, reqNum), "side", side.String(), "duration", duration)...)
+}
+
+// logClose logs the endpoint's termination
+func (e *Endpoint) logClose(cause error) {
+	switch {
+	case errors.Is(cause, errProtocolError):
+		e.logger.Error("connection closed on protocol error", "cause", cause)
+	case cause == ErrEndpointClosed, cause == ErrEndpointClosedByPeer:
+		e.logger.Info("connection closed", "cause", cause)
+	default:
+		e.logger.Warn("connection closed", "cause", cause)
+	}
+}
@@ -0,0 +1,139 @@
+package irpc_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/marben/irpc"
+	"github.com/marben/irpc/cmd/irpc/test/testtools"
+)
+
+// logBuffer collects json log records
+type logBuffer struct {
+	buf bytes.Buffer
+	m   sync.Mutex
+}
+
+func (b *logBuffer) Write(p []byte) (int, error) {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.buf.Write(p)
+}
+
+func (b *logBuffer) String() string {
+	b.m.Lock()
+	defer b.m.Unlock()
+	return b.buf.String()
+}
+
+// find waits for the first record with the message
+func (b *logBuffer) find(t *testing.T, msg string) map[string]any {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for {
+		for _, line := range strings.Split(strings.TrimSpace(b.String()), "\n") {
+			var rec map[string]any
+			if err := json.Unmarshal([]byte(line), &rec); err == nil && rec["msg"] == msg {
+				return rec
+			}
+		}
+		if time.Now().After(deadline) {
+			t.Fatalf("record %q not found in:\n%s", msg, b.String())
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestLogger(t *testing.T) {
+	logs := &logBuffer{}
+	logger := slog.New(slog.NewJSONHandler(logs, nil))
+
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		if b == 0 {
+			panic("boom")
+		}
+		time.Sleep(2 * time.Millisecond)
+		return a / b, nil
+	}
+
+	c1, c2 := net.Pipe()
+	addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, 1), Port: 1000}
+	serviceEp := irpc.NewEndpoint(c2,
+		irpc.WithLogger(logger),
+		irpc.WithRemoteAddress(addr),
+		irpc.WithSlowCallThreshold(time.Millisecond),
+		irpc.WithEndpointServices(testtools.NewTestServiceIrpcService(impl)),
+	)
+	clientEp := irpc.NewEndpoint(c1)
+	defer clientEp.Close()
+
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	if _, err := client.DivCtxErr(context.Background(), 4, 2); err != nil {
+		t.Fatalf("DivCtxErr(): %v", err)
+	}
+	if _, err := client.DivCtxErr(context.Background(), 4, 0); err == nil {
+		t.Fatalf("DivCtxErr() didn't return panic error")
+	}
+	serviceEp.Close()
+	<-serviceEp.Context().Done()
+
+	slow := logs.find(t, "slow call")
+	if slow["level"] != "WARN" || slow["service"] != "TestService" || slow["method"] != "DivCtxErr" || slow["side"] != "server" || slow["remote_addr"] != addr.String() {
+		t.Fatalf("slow call record: %v", slow)
+	}
+	if _, found := slow["req_num"]; !found {
+		t.Fatalf("slow call record is missing request number: %v", slow)
+	}
+
+	panicked := logs.find(t, "service function panicked")
+	if panicked["level"] != "ERROR" || panicked["panic"] != "boom" || panicked["method"] != "DivCtxErr" {
+		t.Fatalf("panic record: %v", panicked)
+	}
+
+	closed := logs.find(t, "connection closed")
+	if closed["cause"] != irpc.ErrEndpointClosed.Error() || closed["remote_addr"] != addr.String() {
+		t.Fatalf("close record: %v", closed)
+	}
+}
+
+func TestLoggerDoesntReportSlowCallsByDefault(t *testing.T) {
+	logs := &logBuffer{}
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		time.Sleep(2 * time.Millisecond)
+		return a / b, nil
+	}
+
+	c1, c2 := net.Pipe()
+	serviceEp := irpc.NewEndpoint(c2,
+		irpc.WithLogger(slog.New(slog.NewJSONHandler(logs, nil))),
+		irpc.WithEndpointServices(testtools.NewTestServiceIrpcService(impl)),
+	)
+	clientEp := irpc.NewEndpoint(c1)
+	defer clientEp.Close()
+
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	if _, err := client.DivCtxErr(context.Background(), 4, 2); err != nil {
+		t.Fatalf("DivCtxErr(): %v", err)
+	}
+	serviceEp.Close()
+	logs.find(t, "connection closed")
+
+	if strings.Contains(logs.String(), "slow call") {
+		t.Fatalf("slow call was logged:\n%s", logs.String())
+	}
+}
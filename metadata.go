@@ -0,0 +1,95 @@
+package irpc
+
+import (
+	"context"
+	"maps"
+	"sync"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// Metadata is a set of key/value pairs sent along with a call (for example auth tokens or trace ids),
+// or sent back with its response as a trailer.
+type Metadata map[string]string
+
+func (md Metadata) Serialize(e *irpcgen.Encoder) error {
+	return irpcgen.EncMap(e, md, "string", irpcgen.EncString[string], "string", irpcgen.EncString[string])
+}
+
+func (md *Metadata) Deserialize(d *irpcgen.Decoder) error {
+	return irpcgen.DecMap(d, md, "string", irpcgen.DecString[string], "string", irpcgen.DecString[string])
+}
+
+type (
+	outgoingMetadataKey struct{}
+	incomingMetadataKey struct{}
+	trailerKey          struct{} // trailer being collected by the service function
+	trailerReceiverKey  struct{} // where the caller wants the received trailer
+)
+
+// ContextWithMetadata returns a context, whose calls send md to the peer.
+// md is added to metadata already attached to ctx. Service functions read it with [MetadataFromContext].
+func ContextWithMetadata(ctx context.Context, md Metadata) context.Context {
+	merged := maps.Clone(outgoingMetadata(ctx))
+	if merged == nil {
+		merged = make(Metadata, len(md))
+	}
+	maps.Copy(merged, md)
+	return context.WithValue(ctx, outgoingMetadataKey{}, merged)
+}
+
+func outgoingMetadata(ctx context.Context) Metadata {
+	md, _ := ctx.Value(outgoingMetadataKey{}).(Metadata)
+	return md
+}
+
+// MetadataFromContext returns metadata the caller sent along with the call.
+// It is meant for the context passed to a service function. It returns nil if there is no metadata.
+// The incoming metadata is not forwarded to calls made with the same context.
+func MetadataFromContext(ctx context.Context) Metadata {
+	md, _ := ctx.Value(incomingMetadataKey{}).(Metadata)
+	return md
+}
+
+// trailer collects metadata a service function wants to send back with its response
+type trailer struct {
+	md  Metadata
+	mux sync.Mutex
+}
+
+func (t *trailer) set(md Metadata) {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	if t.md == nil {
+		t.md = make(Metadata, len(md))
+	}
+	maps.Copy(t.md, md)
+}
+
+func (t *trailer) get() Metadata {
+	t.mux.Lock()
+	defer t.mux.Unlock()
+
+	return t.md
+}
+
+// SetTrailer adds md to the trailer sent back to the caller with the response.
+// ctx must be the context passed to a service function. Otherwise SetTrailer does nothing.
+// Caller receives the trailer with [ContextWithTrailer].
+func SetTrailer(ctx context.Context, md Metadata) {
+	if t, ok := ctx.Value(trailerKey{}).(*trailer); ok {
+		t.set(md)
+	}
+}
+
+// ContextWithTrailer returns a context, that makes calls store trailer of their response into *md.
+// *md is set once the call returns (or once a streamed result ends).
+func ContextWithTrailer(ctx context.Context, md *Metadata) context.Context {
+	return context.WithValue(ctx, trailerReceiverKey{}, md)
+}
+
+func trailerReceiver(ctx context.Context) *Metadata {
+	md, _ := ctx.Value(trailerReceiverKey{}).(*Metadata)
+	return md
+}
@@ -0,0 +1,158 @@
+package irpc
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// CallSide tells, which side of a call is being measured.
+type CallSide int
+
+const (
+	ClientSide CallSide = iota // our call to the peer
+	ServerSide                 // execution of our service function for the peer
+)
+
+func (s CallSide) String() string {
+	switch s {
+	case ClientSide:
+		return "client"
+	case ServerSide:
+		return "server"
+	default:
+		return "unknown"
+	}
+}
+
+// CallStatus is the outcome of a finished call.
+type CallStatus int
+
+const (
+	CallOK       CallStatus = iota
+	CallFailed              // call didn't deliver response
+	CallCanceled            // call's context ended before the call finished
+	CallError               // service function returned error (see [irpcgen.ErrorResult])
+)
+
+func (s CallStatus) String() string {
+	switch s {
+	case CallOK:
+		return "ok"
+	case CallFailed:
+		return "failed"
+	case CallCanceled:
+		return "canceled"
+	case CallError:
+		return "error"
+	default:
+		return "unknown"
+	}
+}
+
+// MetricsSink receives measurements of endpoints. The same sink can be shared by many endpoints.
+// Its methods are called from the endpoints' goroutines and must be safe for concurrent use.
+type MetricsSink interface {
+	// CallFinished is called once our call to the peer, or the execution of our service function for the peer ends.
+	// Streaming calls finish after their last item.
+	CallFinished(side CallSide, call CallInfo, duration time.Duration, status CallStatus)
+	// ClientCallsInFlight changes the number of our calls waiting for the peer by delta.
+	ClientCallsInFlight(delta int)
+	// BusyWorkers changes the number of workers executing the peer's calls by delta.
+	BusyWorkers(delta int)
+	// BytesSent is called with the number of bytes written to the connection.
+	// It counts all the traffic, including framing, handshake and keepalive pings, not just the calls' payload.
+	BytesSent(n int)
+	// BytesReceived is called with the number of bytes read from the connection. Like BytesSent, it counts all the traffic.
+	BytesReceived(n int)
+}
+
+// WithMetrics makes the endpoint report its calls, workers and traffic to sink.
+func WithMetrics(sink MetricsSink) EndpointOption {
+	return func(ep *Endpoint) {
+		ep.metrics = sink
+	}
+}
+
+// measuresCalls reports, whether calls need to be timed for metrics or slow call logging
+func (e *Endpoint) measuresCalls() bool {
+	return e.metrics != nil || e.slowCallThreshold > 0
+}
+
+// measureClientCall counts the call in flight. returned function records the call's end
+func (e *Endpoint) measureClientCall(ctx context.Context, call CallInfo) func(err error, resp any) {
+	if e.metrics != nil {
+		e.metrics.ClientCallsInFlight(1)
+	}
+	start := time.Now()
+	return func(err error, resp any) {
+		duration := time.Since(start)
+		if e.metrics != nil {
+			e.metrics.ClientCallsInFlight(-1)
+			e.metrics.CallFinished(ClientSide, call, duration, callStatus(ctx, err, resp))
+		}
+		e.logSlowCall(ClientSide, call, nil, duration)
+	}
+}
+
+// workerStarted implements workerObserver
+func (e *Endpoint) workerStarted() {
+	if e.metrics != nil {
+		e.metrics.BusyWorkers(1)
+	}
+}
+
+// workerFinished implements workerObserver
+func (e *Endpoint) workerFinished(reqNum reqNumT, call *CallInfo, duration time.Duration, status CallStatus, panicErr *RemotePanicError) {
+	if e.metrics != nil {
+		e.metrics.BusyWorkers(-1)
+		if call != nil {
+			e.metrics.CallFinished(ServerSide, *call, duration, status)
+		}
+	}
+	if call == nil {
+		return
+	}
+	if panicErr != nil {
+		e.logger.Error("service function panicked", append(callLogAttrs(*call, &reqNum), "panic", panicErr.Value, "stack", panicErr.Stack)...)
+	}
+	e.logSlowCall(ServerSide, *call, &reqNum, duration)
+}
+
+// callStatus determines the outcome of a call made with ctx, that ended with err and resp
+func callStatus(ctx context.Context, err error, resp any) CallStatus {
+	switch {
+	case ctx.Err() != nil:
+		return CallCanceled
+	case err != nil:
+		return CallFailed
+	}
+	if r, ok := resp.(irpcgen.ErrorResult); ok && r.ResultError() != nil {
+		return CallError
+	}
+	return CallOK
+}
+
+// meteredConn reports bytes going through the connection
+type meteredConn struct {
+	io.ReadWriteCloser
+	sink MetricsSink
+}
+
+func (c meteredConn) Read(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Read(p)
+	if n > 0 {
+		c.sink.BytesReceived(n)
+	}
+	return n, err
+}
+
+func (c meteredConn) Write(p []byte) (int, error) {
+	n, err := c.ReadWriteCloser.Write(p)
+	if n > 0 {
+		c.sink.BytesSent(n)
+	}
+	return n, err
+}
@@ -0,0 +1,217 @@
+// Package metrics collects measurements of irpc endpoints and exports them in Prometheus text format.
+//
+// The package only depends on irpc and the standard library:
+//
+//	m := metrics.NewPrometheus()
+//	ep := irpc.NewEndpoint(conn, irpc.WithMetrics(m))
+//	http.Handle("/metrics", m)
+package metrics
+
+import (
+	"cmp"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marben/irpc"
+)
+
+// DefaultBuckets are upper bounds (in seconds) of the call latency histogram buckets.
+var DefaultBuckets = []float64{0.0005, 0.001, 0.0025, 0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// callKey identifies a time series of call metrics
+type callKey struct {
+	side    irpc.CallSide
+	service string
+	method  string
+}
+
+type callStats struct {
+	calls    uint64
+	failed   uint64
+	canceled uint64
+	errors   uint64
+	buckets  []uint64 // not cumulative. the last one counts calls over the highest bound
+	sum      float64  // seconds
+}
+
+// Prometheus is an [irpc.MetricsSink], that serves collected metrics in Prometheus text format.
+// One Prometheus is usually shared by all endpoints of the process.
+//
+// It serves following metrics. Call metrics are labeled with side ("client" or "server"), service and method:
+//
+//	irpc_calls_total                  counter   finished calls
+//	irpc_call_failures_total          counter   calls, that didn't deliver response
+//	irpc_call_cancellations_total     counter   calls, whose context ended first
+//	irpc_call_errors_total            counter   calls, whose service function returned error
+//	irpc_call_duration_seconds        histogram call latency
+//	irpc_client_calls_in_flight       gauge     our calls waiting for the peer
+//	irpc_busy_workers                 gauge     workers executing the peer's calls
+//	irpc_sent_bytes_total             counter   bytes written to connections, including protocol overhead
+//	irpc_received_bytes_total         counter   bytes read from connections, including protocol overhead
+type Prometheus struct {
+	buckets []float64
+
+	calls map[callKey]*callStats
+	m     sync.Mutex
+
+	inFlight      atomic.Int64
+	busyWorkers   atomic.Int64
+	bytesSent     atomic.Uint64
+	bytesReceived atomic.Uint64
+}
+
+var _ irpc.MetricsSink = (*Prometheus)(nil)
+var _ http.Handler = (*Prometheus)(nil)
+
+// PrometheusOption configures [Prometheus].
+type PrometheusOption func(*Prometheus)
+
+// WithBuckets sets upper bounds (in seconds) of the latency histogram buckets. Default is [DefaultBuckets].
+func WithBuckets(buckets ...float64) PrometheusOption {
+	return func(p *Prometheus) {
+		p.buckets = slices.Sorted(slices.Values(buckets))
+	}
+}
+
+// NewPrometheus creates an empty metrics sink.
+func NewPrometheus(opts ...PrometheusOption) *Prometheus {
+	p := &Prometheus{
+		buckets: DefaultBuckets,
+		calls:   make(map[callKey]*callStats),
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// callNames returns service and method name of the call
+func callNames(call irpc.CallInfo) (service, method string) {
+	service = call.ServiceId.String()
+	method = strconv.FormatUint(uint64(call.FuncId), 10)
+	if call.Service != nil {
+		service = call.Service.Name
+		if m, ok := call.Service.Method(call.FuncId); ok {
+			method = m.Name
+		}
+	}
+	return service, method
+}
+
+// CallFinished implements [irpc.MetricsSink]
+func (p *Prometheus) CallFinished(side irpc.CallSide, call irpc.CallInfo, duration time.Duration, status irpc.CallStatus) {
+	service, method := callNames(call)
+	key := callKey{side: side, service: service, method: method}
+	seconds := duration.Seconds()
+
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	stats, found := p.calls[key]
+	if !found {
+		stats = &callStats{buckets: make([]uint64, len(p.buckets)+1)}
+		p.calls[key] = stats
+	}
+	stats.calls++
+	switch status {
+	case irpc.CallFailed:
+		stats.failed++
+	case irpc.CallCanceled:
+		stats.canceled++
+	case irpc.CallError:
+		stats.errors++
+	}
+	i, _ := slices.BinarySearch(p.buckets, seconds)
+	stats.buckets[i]++
+	stats.sum += seconds
+}
+
+// ClientCallsInFlight implements [irpc.MetricsSink]
+func (p *Prometheus) ClientCallsInFlight(delta int) {
+	p.inFlight.Add(int64(delta))
+}
+
+// BusyWorkers implements [irpc.MetricsSink]
+func (p *Prometheus) BusyWorkers(delta int) {
+	p.busyWorkers.Add(int64(delta))
+}
+
+// BytesSent implements [irpc.MetricsSink]
+func (p *Prometheus) BytesSent(n int) {
+	p.bytesSent.Add(uint64(n))
+}
+
+// BytesReceived implements [irpc.MetricsSink]
+func (p *Prometheus) BytesReceived(n int) {
+	p.bytesReceived.Add(uint64(n))
+}
+
+// ServeHTTP serves the metrics in Prometheus text format.
+func (p *Prometheus) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	p.WriteTo(w)
+}
+
+// WriteTo writes the metrics in Prometheus text format to w.
+func (p *Prometheus) WriteTo(w io.Writer) (int64, error) {
+	var b strings.Builder
+
+	p.m.Lock()
+	keys := slices.SortedFunc(maps.Keys(p.calls), func(a, b callKey) int {
+		return cmp.Or(cmp.Compare(a.side, b.side), cmp.Compare(a.service, b.service), cmp.Compare(a.method, b.method))
+	})
+	stats := make([]callStats, len(keys))
+	for i, k := range keys {
+		stats[i] = *p.calls[k]
+		stats[i].buckets = slices.Clone(stats[i].buckets)
+	}
+	p.m.Unlock()
+
+	counter := func(name, help string, value func(s callStats) uint64) {
+		fmt.Fprintf(&b, "# HELP %s %s\n# TYPE %s counter\n", name, help, name)
+		for i, k := range keys {
+			fmt.Fprintf(&b, "%s{%s} %d\n", name, labels(k), value(stats[i]))
+		}
+	}
+	counter("irpc_calls_total", "Finished calls.", func(s callStats) uint64 { return s.calls })
+	counter("irpc_call_failures_total", "Calls, that didn't deliver response.", func(s callStats) uint64 { return s.failed })
+	counter("irpc_call_cancellations_total", "Calls, whose context ended before they finished.", func(s callStats) uint64 { return s.canceled })
+	counter("irpc_call_errors_total", "Calls, whose service function returned error.", func(s callStats) uint64 { return s.errors })
+
+	const hist = "irpc_call_duration_seconds"
+	fmt.Fprintf(&b, "# HELP %s Call latency.\n# TYPE %s histogram\n", hist, hist)
+	for i, k := range keys {
+		l := labels(k)
+		var cumulative uint64
+		for j, bound := range p.buckets {
+			cumulative += stats[i].buckets[j]
+			fmt.Fprintf(&b, "%s_bucket{%s,le=\"%s\"} %d\n", hist, l, strconv.FormatFloat(bound, 'g', -1, 64), cumulative)
+		}
+		fmt.Fprintf(&b, "%s_bucket{%s,le=\"+Inf\"} %d\n", hist, l, stats[i].calls)
+		fmt.Fprintf(&b, "%s_sum{%s} %s\n", hist, l, strconv.FormatFloat(stats[i].sum, 'g', -1, 64))
+		fmt.Fprintf(&b, "%s_count{%s} %d\n", hist, l, stats[i].calls)
+	}
+
+	fmt.Fprintf(&b, "# HELP irpc_client_calls_in_flight Our calls waiting for the peer.\n# TYPE irpc_client_calls_in_flight gauge\nirpc_client_calls_in_flight %d\n", p.inFlight.Load())
+	fmt.Fprintf(&b, "# HELP irpc_busy_workers Workers executing the peer's calls.\n# TYPE irpc_busy_workers gauge\nirpc_busy_workers %d\n", p.busyWorkers.Load())
+	fmt.Fprintf(&b, "# HELP irpc_sent_bytes_total Bytes written to connections.\n# TYPE irpc_sent_bytes_total counter\nirpc_sent_bytes_total %d\n", p.bytesSent.Load())
+	fmt.Fprintf(&b, "# HELP irpc_received_bytes_total Bytes read from connections.\n# TYPE irpc_received_bytes_total counter\nirpc_received_bytes_total %d\n", p.bytesReceived.Load())
+
+	n, err := io.WriteString(w, b.String())
+	return int64(n), err
+}
+
+func labels(k callKey) string {
+	return fmt.Sprintf(`side="%s",service="%s",method="%s"`, k.side, labelEscaper.Replace(k.service), labelEscaper.Replace(k.method))
+}
+
+// labelEscaper escapes label values the way prometheus text format wants
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
@@ -0,0 +1,184 @@
+package metrics_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http/httptest"
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/marben/irpc"
+	"github.com/marben/irpc/cmd/irpc/test/testtools"
+	"github.com/marben/irpc/metrics"
+)
+
+// scrape returns the value of the metric line, that starts with series
+func scrape(t *testing.T, m *metrics.Prometheus, series string) int {
+	t.Helper()
+	var b strings.Builder
+	if _, err := m.WriteTo(&b); err != nil {
+		t.Fatalf("WriteTo(): %v", err)
+	}
+	match := regexp.MustCompile(`(?m)^` + regexp.QuoteMeta(series) + ` (\d+)$`).FindStringSubmatch(b.String())
+	if match == nil {
+		t.Fatalf("series %s not found in:\n%s", series, b.String())
+	}
+	v, _ := strconv.Atoi(match[1])
+	return v
+}
+
+// waitForValue waits until the series has the value. gauges are updated only after the response is sent
+func waitForValue(t *testing.T, m *metrics.Prometheus, series string, value int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for scrape(t, m, series) != value {
+		if time.Now().After(deadline) {
+			t.Fatalf("%s is %d, want %d", series, scrape(t, m, series), value)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestPrometheusMetrics(t *testing.T) {
+	clientMetrics, serviceMetrics := metrics.NewPrometheus(), metrics.NewPrometheus()
+
+	startedC := make(chan struct{})
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		startedC <- struct{}{}
+		<-ctx.Done()
+		return 0, ctx.Err()
+	}
+
+	c1, c2 := net.Pipe()
+	serviceEp := irpc.NewEndpoint(c2, irpc.WithMetrics(serviceMetrics), irpc.WithEndpointServices(testtools.NewTestServiceIrpcService(impl)))
+	defer serviceEp.Close()
+	clientEp := irpc.NewEndpoint(c1, irpc.WithMetrics(clientMetrics))
+	defer clientEp.Close()
+
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	for range 2 {
+		if res, err := client.DivErr(6, 3); err != nil || res != 2 {
+			t.Fatalf("DivErr(): %d, %v", res, err)
+		}
+	}
+
+	const divErr = `service="TestService",method="DivErr"`
+	if n := scrape(t, clientMetrics, `irpc_calls_total{side="client",`+divErr+`}`); n != 2 {
+		t.Fatalf("client calls: %d", n)
+	}
+	if n := scrape(t, serviceMetrics, `irpc_calls_total{side="server",`+divErr+`}`); n != 2 {
+		t.Fatalf("server calls: %d", n)
+	}
+	if n := scrape(t, clientMetrics, `irpc_call_duration_seconds_count{side="client",`+divErr+`}`); n != 2 {
+		t.Fatalf("client latency count: %d", n)
+	}
+	if n := scrape(t, clientMetrics, `irpc_call_failures_total{side="client",`+divErr+`}`); n != 0 {
+		t.Fatalf("client failures: %d", n)
+	}
+	sent, received := scrape(t, clientMetrics, "irpc_sent_bytes_total"), scrape(t, serviceMetrics, "irpc_received_bytes_total")
+	if sent == 0 || sent != received {
+		t.Fatalf("client sent %d bytes, service received %d bytes", sent, received)
+	}
+
+	waitForValue(t, serviceMetrics, "irpc_busy_workers", 0)
+
+	// blocked call is in flight, until we cancel it
+	ctx, cancel := context.WithCancel(context.Background())
+	errC := make(chan error, 1)
+	go func() {
+		_, err := client.DivCtxErr(ctx, 1, 1)
+		errC <- err
+	}()
+	<-startedC
+	if n := scrape(t, clientMetrics, "irpc_client_calls_in_flight"); n != 1 {
+		t.Fatalf("client calls in flight: %d", n)
+	}
+	if n := scrape(t, serviceMetrics, "irpc_busy_workers"); n != 1 {
+		t.Fatalf("busy workers: %d", n)
+	}
+	cancel()
+	<-errC
+
+	const divCtxErr = `service="TestService",method="DivCtxErr"`
+	if n := scrape(t, clientMetrics, `irpc_call_cancellations_total{side="client",`+divCtxErr+`}`); n != 1 {
+		t.Fatalf("client cancellations: %d", n)
+	}
+	if n := scrape(t, clientMetrics, "irpc_client_calls_in_flight"); n != 0 {
+		t.Fatalf("client calls in flight: %d", n)
+	}
+	waitForValue(t, serviceMetrics, "irpc_busy_workers", 0)
+	if n := scrape(t, serviceMetrics, `irpc_call_cancellations_total{side="server",`+divCtxErr+`}`); n != 1 {
+		t.Fatalf("server cancellations: %d", n)
+	}
+}
+
+func TestPrometheusCountsFunctionErrors(t *testing.T) {
+	clientMetrics, serviceMetrics := metrics.NewPrometheus(), metrics.NewPrometheus()
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivErrFunc = func(a, b int) (int, error) {
+		return 0, errors.New("division by zero")
+	}
+
+	c1, c2 := net.Pipe()
+	serviceEp := irpc.NewEndpoint(c2, irpc.WithMetrics(serviceMetrics), irpc.WithEndpointServices(testtools.NewTestServiceIrpcService(impl)))
+	defer serviceEp.Close()
+	clientEp := irpc.NewEndpoint(c1, irpc.WithMetrics(clientMetrics))
+	defer clientEp.Close()
+
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	if _, err := client.DivErr(1, 0); err == nil {
+		t.Fatalf("DivErr() didn't fail")
+	}
+
+	const divErr = `service="TestService",method="DivErr"`
+	for _, s := range []struct {
+		m    *metrics.Prometheus
+		side string
+	}{{clientMetrics, "client"}, {serviceMetrics, "server"}} {
+		if n := scrape(t, s.m, `irpc_call_errors_total{side="`+s.side+`",`+divErr+`}`); n != 1 {
+			t.Fatalf("%s errors: %d", s.side, n)
+		}
+		if n := scrape(t, s.m, `irpc_call_failures_total{side="`+s.side+`",`+divErr+`}`); n != 0 {
+			t.Fatalf("%s failures: %d", s.side, n)
+		}
+	}
+}
+
+func TestPrometheusHandler(t *testing.T) {
+	m := metrics.NewPrometheus(metrics.WithBuckets(1, 0.1))
+	m.CallFinished(irpc.ClientSide, irpc.CallInfo{ServiceId: 0xab, FuncId: 3}, 50*time.Millisecond, irpc.CallFailed)
+	m.CallFinished(irpc.ClientSide, irpc.CallInfo{ServiceId: 0xab, FuncId: 3}, 2*time.Second, irpc.CallOK)
+
+	rec := httptest.NewRecorder()
+	m.ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	if ct := rec.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain; version=0.0.4") {
+		t.Fatalf("content type: %q", ct)
+	}
+	body, _ := io.ReadAll(rec.Body)
+
+	const labels = `side="client",service="0x00000000000000ab",method="3"`
+	for _, want := range []string{
+		`irpc_calls_total{` + labels + `} 2`,
+		`irpc_call_failures_total{` + labels + `} 1`,
+		`irpc_call_duration_seconds_bucket{` + labels + `,le="0.1"} 1`,
+		`irpc_call_duration_seconds_bucket{` + labels + `,le="1"} 1`,
+		`irpc_call_duration_seconds_bucket{` + labels + `,le="+Inf"} 2`,
+		`irpc_call_duration_seconds_sum{` + labels + `} 2.05`,
+	} {
+		if !strings.Contains(string(body), want+"\n") {
+			t.Fatalf("missing %q in:\n%s", want, body)
+		}
+	}
+}
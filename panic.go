@@ -0,0 +1,46 @@
+package irpc
+
+import "fmt"
+
+// PanicPolicy determines, what happens when a service function we execute for our peer panics.
+type PanicPolicy int
+
+const (
+	// PanicReturnError recovers the panic and returns [*RemotePanicError] to the caller.
+	// The connection and other calls on it are not affected.
+	PanicReturnError PanicPolicy = iota
+
+	// PanicCloseConnection recovers the panic and closes the connection it happened on.
+	// The panic's [*RemotePanicError] becomes the cause of endpoint's context.
+	PanicCloseConnection
+
+	// PanicRepanic doesn't recover the panic, so it crashes the whole process, as if the function was called locally.
+	PanicRepanic
+)
+
+// DefaultPanicPolicy is used by endpoints, unless overridden with [WithPanicPolicy] or [WithServerPanicPolicy] option.
+var DefaultPanicPolicy = PanicReturnError
+
+func (p PanicPolicy) String() string {
+	switch p {
+	case PanicReturnError:
+		return "PanicReturnError"
+	case PanicCloseConnection:
+		return "PanicCloseConnection"
+	case PanicRepanic:
+		return "PanicRepanic"
+	default:
+		return fmt.Sprintf("PanicPolicy(%d)", int(p))
+	}
+}
+
+// RemotePanicError describes a panic of a service function.
+// It is returned to the caller with [PanicReturnError] policy.
+type RemotePanicError struct {
+	Value string // value passed to panic() formatted with fmt.Sprint
+	Stack string // stack trace of the panicking goroutine
+}
+
+func (e *RemotePanicError) Error() string {
+	return "irpc: remote function panicked: " + e.Value
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"sync"
+	"sync/atomic"
 
 	"github.com/marben/irpc/irpcgen"
 )
@@ -13,7 +14,37 @@ type ourPendingRequest struct {
 	reqNum    reqNumT
 	resp      irpcgen.Deserializable
 	deserErrC chan error
+	stream    *itemStream // nil, unless the function streams its result
+	trailer   *Metadata   // where the caller wants response's trailer. nil if it doesn't
+
+	paramCredits    *streamCredits          // nil, unless the function has a streamed parameter
+	stopParamStream context.CancelCauseFunc // stops sending of streamed parameter once the response arrives
+
+	// request number can only be reused after everybody holding it is finished.
+	// that is the readLoop, the result stream's consumer and the param stream's sender
+	refs *atomic.Int32
+
+	// decides, whether response is delivered to resp, or discarded because the caller no longer waits for it
+	state *atomic.Int32
+}
+
+// states of ourPendingRequest
+const (
+	requestPending   int32 = iota
+	requestAnswered        // readLoop is receiving the response
+	requestAbandoned       // caller returned without the response. it is discarded once it arrives
+)
+
+// abandon tells the readLoop to discard the response. returns false if the response is already being received
+func (pr ourPendingRequest) abandon() bool {
+	return pr.state.CompareAndSwap(requestPending, requestAbandoned)
+}
+
+// answer claims the response for the caller. returns false if the caller abandoned the request
+func (pr ourPendingRequest) answer() bool {
+	return pr.state.CompareAndSwap(requestPending, requestAnswered)
 }
+
 type ourPendingRequestsLog struct {
 	reqNumsC        chan reqNumT
 	m               sync.Mutex
@@ -40,16 +71,31 @@ func (l *ourPendingRequestsLog) newRequestNumber(ctx context.Context) (reqNumT,
 	}
 }
 
-func (l *ourPendingRequestsLog) addPendingRequest(ctx context.Context, resp irpcgen.Deserializable) (ourPendingRequest, error) {
+// stopParamStream is nil for requests without streamed parameter
+// ctx is the call's context. it determines, where the response's trailer goes
+func (l *ourPendingRequestsLog) addPendingRequest(ctx context.Context, resp irpcgen.Deserializable, stream *itemStream, stopParamStream context.CancelCauseFunc) (ourPendingRequest, error) {
 	reqNum, err := l.newRequestNumber(ctx)
 	if err != nil {
 		return ourPendingRequest{}, fmt.Errorf("newRequestNumber: %w", err)
 	}
 
 	pr := ourPendingRequest{
-		reqNum:    reqNum,
-		resp:      resp,
-		deserErrC: make(chan error, 1),
+		reqNum:          reqNum,
+		resp:            resp,
+		deserErrC:       make(chan error, 1),
+		stream:          stream,
+		trailer:         trailerReceiver(ctx),
+		stopParamStream: stopParamStream,
+		refs:            new(atomic.Int32),
+		state:           new(atomic.Int32),
+	}
+	pr.refs.Store(1)
+	if stream != nil {
+		pr.refs.Add(1)
+	}
+	if stopParamStream != nil {
+		pr.paramCredits = newStreamCredits()
+		pr.refs.Add(1)
 	}
 
 	l.m.Lock()
@@ -59,7 +105,29 @@ func (l *ourPendingRequestsLog) addPendingRequest(ctx context.Context, resp irpc
 	return pr, nil
 }
 
+func (l *ourPendingRequestsLog) getPendingRequest(reqNum reqNumT) (ourPendingRequest, error) {
+	l.m.Lock()
+	defer l.m.Unlock()
+
+	pr, found := l.pendingRequests[reqNum]
+	if !found {
+		return ourPendingRequest{}, fmt.Errorf("pending request %d not found", reqNum)
+	}
+	return pr, nil
+}
+
 func (l *ourPendingRequestsLog) popPendingRequest(reqNum reqNumT) (ourPendingRequest, error) {
+	pr, err := l.removePendingRequest(reqNum)
+	if err != nil {
+		return ourPendingRequest{}, err
+	}
+	l.releaseRequestNumber(reqNum)
+	return pr, nil
+}
+
+// removePendingRequest removes request from the log, but doesn't return its number for reuse.
+// caller is responsible for calling releaseRequestNumber
+func (l *ourPendingRequestsLog) removePendingRequest(reqNum reqNumT) (ourPendingRequest, error) {
 	l.m.Lock()
 	defer l.m.Unlock()
 
@@ -68,6 +136,22 @@ func (l *ourPendingRequestsLog) popPendingRequest(reqNum reqNumT) (ourPendingReq
 		return ourPendingRequest{}, fmt.Errorf("pending request %d not found", reqNum)
 	}
 	delete(l.pendingRequests, reqNum)
-	l.reqNumsC <- reqNum
 	return pr, nil
 }
+
+func (l *ourPendingRequestsLog) releaseRequestNumber(reqNum reqNumT) {
+	l.reqNumsC <- reqNum
+}
+
+// finish is called by each holder of the request, once it is done with it.
+// the last one releases the request number
+func (l *ourPendingRequestsLog) finish(pr ourPendingRequest) {
+	if pr.refs.Add(-1) == 0 {
+		l.releaseRequestNumber(pr.reqNum)
+	}
+}
+
+// outstanding returns number of request numbers in use, ie our requests in progress
+func (l *ourPendingRequestsLog) outstanding() int {
+	return cap(l.reqNumsC) - len(l.reqNumsC)
+}
@@ -0,0 +1,291 @@
+package irpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"iter"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// ErrNoEndpoints is returned by calls of [Pool] without any live endpoint.
+var ErrNoEndpoints = errors.New("irpc: no endpoints in pool")
+
+// Balancer picks one of the pool's endpoints for a call. endpoints is never empty.
+type Balancer func(ctx context.Context, call CallInfo, endpoints []*Endpoint) *Endpoint
+
+// RoundRobin balancer uses the endpoints in turns.
+func RoundRobin() Balancer {
+	var next atomic.Uint64
+	return func(ctx context.Context, call CallInfo, endpoints []*Endpoint) *Endpoint {
+		return endpoints[(next.Add(1)-1)%uint64(len(endpoints))]
+	}
+}
+
+// LeastOutstanding balancer picks the endpoint with the fewest calls in progress (see [Endpoint.OutstandingCalls]).
+// Ties are broken in round robin fashion.
+func LeastOutstanding() Balancer {
+	var next atomic.Uint64
+	return func(ctx context.Context, call CallInfo, endpoints []*Endpoint) *Endpoint {
+		start := int(next.Add(1) - 1)
+		var best *Endpoint
+		bestCalls := 0
+		for i := range endpoints {
+			ep := endpoints[(start+i)%len(endpoints)]
+			if calls := ep.OutstandingCalls(); best == nil || calls < bestCalls {
+				best, bestCalls = ep, calls
+			}
+		}
+		return best
+	}
+}
+
+// ConsistentHash balancer sends calls with the same value of outgoing metadata key (see [ContextWithMetadata]) to the same endpoint.
+// When an endpoint is added or ejected, only calls hashed to that endpoint move elsewhere.
+// Calls without the key are balanced in round robin fashion.
+//
+// Endpoints are identified by their remote address (see [WithRemoteAddress]).
+func ConsistentHash(key string) Balancer {
+	roundRobin := RoundRobin()
+	return func(ctx context.Context, call CallInfo, endpoints []*Endpoint) *Endpoint {
+		value, found := outgoingMetadata(ctx)[key]
+		if !found {
+			return roundRobin(ctx, call, endpoints)
+		}
+
+		// rendezvous hashing: the endpoint with the highest hash of the value wins
+		var best *Endpoint
+		var bestHash uint64
+		for _, ep := range endpoints {
+			h := fnv.New64a()
+			h.Write([]byte(value))
+			h.Write([]byte{0})
+			h.Write([]byte(endpointId(ep)))
+			if sum := h.Sum64(); best == nil || sum > bestHash {
+				best, bestHash = ep, sum
+			}
+		}
+		return best
+	}
+}
+
+// endpointId identifies endpoint for consistent hashing
+func endpointId(ep *Endpoint) string {
+	if addr := ep.RemoteAddr(); addr != nil {
+		return addr.Network() + "://" + addr.String()
+	}
+	return fmt.Sprintf("%p", ep)
+}
+
+// Pool spreads calls over multiple endpoints, typically connected to identical backends.
+// Generated clients can use it in place of [Endpoint].
+//
+// Endpoints are ejected from the pool, once their context ends, or when they fail to register a client.
+//
+// Pool implements [irpcgen.Endpoint].
+type Pool struct {
+	balancer Balancer
+
+	// replaced, never modified, so that balancer can use it without locking
+	endpoints []*Endpoint
+	// registered with every added endpoint
+	clients map[irpcgen.ServiceId]*irpcgen.ServiceDesc // description is nil if unknown
+	m       sync.Mutex
+}
+
+// NewPool creates a pool of endpoints, that uses balancer to pick an endpoint for each call.
+// nil balancer means [RoundRobin].
+func NewPool(balancer Balancer, endpoints ...*Endpoint) *Pool {
+	if balancer == nil {
+		balancer = RoundRobin()
+	}
+	p := &Pool{
+		balancer: balancer,
+		clients:  make(map[irpcgen.ServiceId]*irpcgen.ServiceDesc),
+	}
+	// there are no clients to register with the endpoints yet
+	for _, ep := range endpoints {
+		if !slices.Contains(p.endpoints, ep) {
+			p.endpoints = append(p.endpoints, ep)
+			go p.ejectOnClose(ep)
+		}
+	}
+	return p
+}
+
+// Add adds endpoint to the pool and registers all clients, that were already registered with the pool, with it.
+// If the registration fails, the endpoint is not added. Adding endpoint, that is already in the pool, does nothing.
+func (p *Pool) Add(ep *Endpoint) error {
+	// clients may be registered with the pool, while we register them with ep.
+	// we only add ep once there is nothing left to register
+	registered := make(map[irpcgen.ServiceId]*irpcgen.ServiceDesc)
+	for {
+		p.m.Lock()
+		missed := make(map[irpcgen.ServiceId]*irpcgen.ServiceDesc)
+		for sid, desc := range p.clients {
+			if reg, found := registered[sid]; !found || (reg == nil && desc != nil) {
+				missed[sid] = desc
+			}
+		}
+		if slices.Contains(p.endpoints, ep) {
+			p.m.Unlock()
+			return nil
+		}
+		if len(missed) == 0 {
+			p.endpoints = append(slices.Clip(p.endpoints), ep)
+			p.m.Unlock()
+			break
+		}
+		p.m.Unlock()
+
+		for sid, desc := range missed {
+			if err := registerWith(ep, sid, desc); err != nil {
+				return err
+			}
+			registered[sid] = desc
+		}
+	}
+
+	go p.ejectOnClose(ep)
+
+	return nil
+}
+
+// ejectOnClose removes ep from the pool, once its context ends
+func (p *Pool) ejectOnClose(ep *Endpoint) {
+	<-ep.Context().Done()
+	p.remove(ep)
+}
+
+func (p *Pool) remove(ep *Endpoint) {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	p.endpoints = slices.DeleteFunc(slices.Clone(p.endpoints), func(e *Endpoint) bool { return e == ep })
+}
+
+// Endpoints returns the endpoints currently in the pool
+func (p *Pool) Endpoints() []*Endpoint {
+	p.m.Lock()
+	defer p.m.Unlock()
+
+	return slices.Clone(p.endpoints)
+}
+
+// pick returns endpoint for the call
+func (p *Pool) pick(ctx context.Context, call CallInfo) (*Endpoint, error) {
+	p.m.Lock()
+	endpoints := p.endpoints
+	desc := p.clients[call.ServiceId]
+	p.m.Unlock()
+
+	// draining endpoints would refuse the call
+	if slices.ContainsFunc(endpoints, isDraining) {
+		endpoints = slices.DeleteFunc(slices.Clone(endpoints), isDraining)
+	}
+	if len(endpoints) == 0 {
+		return nil, ErrNoEndpoints
+	}
+	if desc != nil {
+		call = newCallInfo(call.ServiceId, call.FuncId, desc)
+	}
+	return p.balancer(ctx, call, endpoints), nil
+}
+
+// isDraining reports, whether we or our peer are shutting down the endpoint
+func isDraining(ep *Endpoint) bool {
+	return ep.acceptsCalls() != nil
+}
+
+// RegisterClient registers client with every endpoint of the pool, including those added later.
+//
+// RegisterClient implements [irpcgen.Endpoint]
+func (p *Pool) RegisterClient(serviceId irpcgen.ServiceId) error {
+	return p.registerClient(serviceId, nil)
+}
+
+// RegisterDescribedClient is [Pool.RegisterClient] for clients with known description.
+//
+// RegisterDescribedClient implements [irpcgen.DescribedEndpoint]
+func (p *Pool) RegisterDescribedClient(desc *irpcgen.ServiceDesc) error {
+	return p.registerClient(desc.Id, desc)
+}
+
+// registerClient ejects endpoints, that fail the registration.
+// it only fails if there were live endpoints and none of them registered the client
+func (p *Pool) registerClient(serviceId irpcgen.ServiceId, desc *irpcgen.ServiceDesc) error {
+	p.m.Lock()
+	_, known := p.clients[serviceId]
+	if desc != nil || p.clients[serviceId] == nil {
+		p.clients[serviceId] = desc
+	}
+	endpoints := p.endpoints
+	p.m.Unlock()
+
+	accepted := 0
+	var errs error
+	for _, ep := range endpoints {
+		if ep.Context().Err() != nil {
+			// being ejected
+			continue
+		}
+		if err := registerWith(ep, serviceId, desc); err != nil {
+			errs = errors.Join(errs, fmt.Errorf("%s: %w", endpointId(ep), err))
+			p.remove(ep)
+			continue
+		}
+		accepted++
+	}
+	if accepted == 0 && errs != nil {
+		if !known {
+			// caller learns about it now. it mustn't fail endpoints added later
+			p.m.Lock()
+			delete(p.clients, serviceId)
+			p.m.Unlock()
+		}
+		return errs
+	}
+	return nil
+}
+
+// CallRemoteFunc invokes a function on the endpoint picked by the pool's balancer.
+//
+// CallRemoteFunc implements [irpcgen.Endpoint]
+func (p *Pool) CallRemoteFunc(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, respData irpcgen.Deserializable) error {
+	ep, err := p.pick(ctx, CallInfo{ServiceId: serviceId, FuncId: funcId})
+	if err != nil {
+		return err
+	}
+	return ep.CallRemoteFunc(ctx, serviceId, funcId, reqData, respData)
+}
+
+// CallRemoteStream invokes a server-streaming function on the endpoint picked by the pool's balancer.
+//
+// CallRemoteStream implements [irpcgen.Endpoint]
+func (p *Pool) CallRemoteStream(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, newItem func() irpcgen.Deserializable, respData irpcgen.Deserializable) iter.Seq2[irpcgen.Deserializable, error] {
+	return func(yield func(irpcgen.Deserializable, error) bool) {
+		ep, err := p.pick(ctx, CallInfo{ServiceId: serviceId, FuncId: funcId})
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for item, err := range ep.CallRemoteStream(ctx, serviceId, funcId, reqData, newItem, respData) {
+			if !yield(item, err) {
+				return
+			}
+		}
+	}
+}
+
+// Close closes all endpoints of the pool.
+func (p *Pool) Close() error {
+	for _, ep := range p.Endpoints() {
+		ep.Close()
+	}
+	return nil
+}
@@ -0,0 +1,266 @@
+package irpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/marben/irpc"
+	"github.com/marben/irpc/cmd/irpc/test/testtools"
+	"github.com/marben/irpc/irpcgen"
+)
+
+// newTestPoolBackends creates n backends running test service with skew equal to their index
+// returns endpoints connected to them and the backend impls
+func newTestPoolBackends(t *testing.T, n int) ([]*irpc.Endpoint, []*testtools.TestServiceImpl) {
+	t.Helper()
+	var endpoints []*irpc.Endpoint
+	var impls []*testtools.TestServiceImpl
+	for i := range n {
+		c1, c2 := net.Pipe()
+		impl := testtools.NewTestServiceImpl(i)
+		backend := irpc.NewEndpoint(c2, irpc.WithEndpointServices(testtools.NewTestServiceIrpcService(impl)))
+		t.Cleanup(func() { backend.Close() })
+		addr := &net.TCPAddr{IP: net.IPv4(10, 0, 0, byte(i)), Port: 1000}
+		endpoints = append(endpoints, irpc.NewEndpoint(c1, irpc.WithRemoteAddress(addr)))
+		impls = append(impls, impl)
+	}
+	return endpoints, impls
+}
+
+func TestPoolRoundRobin(t *testing.T) {
+	endpoints, _ := newTestPoolBackends(t, 3)
+	pool := irpc.NewPool(irpc.RoundRobin(), endpoints...)
+	defer pool.Close()
+	client, err := testtools.NewTestServiceIrpcClient(pool)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+
+	// backend's skew tells us, which one responded
+	for i := range 6 {
+		if res, err := client.DivErr(0, 1); err != nil || res != i%3 {
+			t.Fatalf("DivErr(): %d, %v", res, err)
+		}
+	}
+}
+
+func TestPoolLeastOutstanding(t *testing.T) {
+	endpoints, impls := newTestPoolBackends(t, 2)
+	pool := irpc.NewPool(irpc.LeastOutstanding(), endpoints...)
+	defer pool.Close()
+	client, err := testtools.NewTestServiceIrpcClient(pool)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+
+	startedC := make(chan struct{})
+	unblockC := make(chan struct{})
+	impls[0].DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		close(startedC)
+		<-unblockC
+		return a / b, nil
+	}
+	doneC := make(chan error)
+	go func() {
+		_, err := client.DivCtxErr(context.Background(), 0, 1)
+		doneC <- err
+	}()
+	<-startedC
+
+	// backend 0 is busy
+	for range 3 {
+		if res, err := client.DivErr(0, 1); err != nil || res != 1 {
+			t.Fatalf("DivErr(): %d, %v", res, err)
+		}
+	}
+	close(unblockC)
+	if err := <-doneC; err != nil {
+		t.Fatalf("DivCtxErr(): %v", err)
+	}
+}
+
+func TestPoolConsistentHash(t *testing.T) {
+	endpoints, _ := newTestPoolBackends(t, 3)
+	pool := irpc.NewPool(irpc.ConsistentHash("user"), endpoints...)
+	defer pool.Close()
+	client, err := testtools.NewTestServiceIrpcClient(pool)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+
+	backendOf := func(user string) int {
+		t.Helper()
+		ctx := irpc.ContextWithMetadata(context.Background(), irpc.Metadata{"user": user})
+		res, err := client.DivCtxErr(ctx, 0, 1)
+		if err != nil {
+			t.Fatalf("DivCtxErr(): %v", err)
+		}
+		return res
+	}
+
+	users := make(map[string]int)
+	for i := range 20 {
+		user := fmt.Sprintf("user%d", i)
+		users[user] = backendOf(user)
+		if again := backendOf(user); again != users[user] {
+			t.Fatalf("%s moved from backend %d to %d", user, users[user], again)
+		}
+	}
+
+	// ejecting a backend only moves its users
+	endpoints[1].Close()
+	for len(pool.Endpoints()) != 2 {
+		time.Sleep(time.Millisecond)
+	}
+	for user, backend := range users {
+		if got := backendOf(user); backend != 1 && got != backend {
+			t.Fatalf("%s moved from backend %d to %d", user, backend, got)
+		}
+	}
+}
+
+func TestPoolEjectsClosedEndpoints(t *testing.T) {
+	endpoints, _ := newTestPoolBackends(t, 2)
+	pool := irpc.NewPool(nil, endpoints...)
+	client, err := testtools.NewTestServiceIrpcClient(pool)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+
+	endpoints[0].Close()
+	for len(pool.Endpoints()) != 1 {
+		time.Sleep(time.Millisecond)
+	}
+	for range 3 {
+		if res, err := client.DivErr(0, 1); err != nil || res != 1 {
+			t.Fatalf("DivErr(): %d, %v", res, err)
+		}
+	}
+
+	endpoints[1].Close()
+	for len(pool.Endpoints()) != 0 {
+		time.Sleep(time.Millisecond)
+	}
+	if _, err := client.DivErr(0, 1); !errors.Is(err, irpc.ErrNoEndpoints) {
+		t.Fatalf("expected ErrNoEndpoints, got: %v", err)
+	}
+}
+
+func TestPoolSkipsDrainingEndpoints(t *testing.T) {
+	endpoints, impls := newTestPoolBackends(t, 2)
+	startedC := make(chan struct{})
+	unblockC := make(chan struct{})
+	impls[0].DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		close(startedC)
+		<-unblockC
+		return a / b, nil
+	}
+	pool := irpc.NewPool(irpc.RoundRobin(), endpoints...)
+	defer pool.Close()
+	client, err := testtools.NewTestServiceIrpcClient(pool)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+
+	// call in progress keeps the first endpoint draining, instead of closed
+	go client.DivCtxErr(context.Background(), 0, 1)
+	<-startedC
+	go endpoints[0].Shutdown(context.Background())
+	defer close(unblockC)
+
+	direct, err := testtools.NewTestServiceIrpcClient(endpoints[0])
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	for {
+		if _, err := direct.DivErr(0, 1); errors.Is(err, irpc.ErrEndpointDraining) {
+			break
+		}
+	}
+
+	for range 3 {
+		if res, err := client.DivErr(0, 1); err != nil || res != 1 {
+			t.Fatalf("DivErr(): %d, %v", res, err)
+		}
+	}
+}
+
+func TestPoolAddRegistersClientsAddedMeanwhile(t *testing.T) {
+	methodC := make(chan string, 1)
+	interceptor := func(ctx context.Context, call irpc.CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable, invoke irpc.ClientInvoker) error {
+		methodC <- call.Method
+		return invoke(ctx, call, req, resp)
+	}
+	c1, c2 := net.Pipe()
+	ep := irpc.NewEndpoint(c1, irpc.WithServiceNegotiation(), irpc.WithClientInterceptors(interceptor))
+	pool := irpc.NewPool(nil)
+	defer pool.Close()
+
+	// registration of the first client waits for the peer's handshake
+	if err := pool.RegisterClient(emptyService{}.Id()); err != nil {
+		t.Fatalf("RegisterClient(): %v", err)
+	}
+	addErrC := make(chan error)
+	go func() { addErrC <- pool.Add(ep) }()
+	time.Sleep(20 * time.Millisecond)
+
+	client, err := testtools.NewTestServiceIrpcClient(pool)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	peer := irpc.NewEndpoint(c2, irpc.WithServiceNegotiation(), irpc.WithEndpointServices(emptyService{}, testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0))))
+	defer peer.Close()
+	if err := <-addErrC; err != nil {
+		t.Fatalf("Add(): %v", err)
+	}
+
+	if res, err := client.DivErr(6, 2); err != nil || res != 3 {
+		t.Fatalf("DivErr(): %d, %v", res, err)
+	}
+	if m := <-methodC; m != "TestService.DivErr" {
+		t.Fatalf("client was not registered with the added endpoint. called method: %q", m)
+	}
+}
+
+func TestPoolEjectsEndpointsRefusingClient(t *testing.T) {
+	newEndpoint := func(services ...irpcgen.Service) *irpc.Endpoint {
+		c1, c2 := net.Pipe()
+		peer := irpc.NewEndpoint(c2, irpc.WithServiceNegotiation(), irpc.WithEndpointServices(services...))
+		t.Cleanup(func() { peer.Close() })
+		ep := irpc.NewEndpoint(c1, irpc.WithServiceNegotiation())
+		t.Cleanup(func() { ep.Close() })
+		return ep
+	}
+	refusing := newEndpoint(emptyService{})
+	accepting := newEndpoint(testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0)))
+	pool := irpc.NewPool(nil, refusing, accepting, accepting)
+	if n := len(pool.Endpoints()); n != 2 {
+		t.Fatalf("endpoint added twice. pool has %d endpoints", n)
+	}
+
+	client, err := testtools.NewTestServiceIrpcClient(pool)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	if eps := pool.Endpoints(); len(eps) != 1 || eps[0] != accepting {
+		t.Fatalf("refusing endpoint was not ejected: %v", eps)
+	}
+	for range 3 {
+		if res, err := client.DivErr(6, 2); err != nil || res != 3 {
+			t.Fatalf("DivErr(): %d, %v", res, err)
+		}
+	}
+	if err := pool.Add(accepting); err != nil || len(pool.Endpoints()) != 1 {
+		t.Fatalf("Add() of endpoint in pool: %v, %d endpoints", err, len(pool.Endpoints()))
+	}
+
+	// no endpoint accepts
+	pool = irpc.NewPool(nil, newEndpoint(emptyService{}))
+	if _, err := testtools.NewTestServiceIrpcClient(pool); !errors.Is(err, irpc.ErrServiceNotFound) {
+		t.Fatalf("expected ErrServiceNotFound, got: %v", err)
+	}
+}
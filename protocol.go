@@ -1,14 +1,31 @@
 package irpc
 
-import "github.com/marben/irpc/irpcgen"
+import (
+	"fmt"
+	"time"
+
+	"github.com/marben/irpc/irpcgen"
+)
 
 // todo: serialization/deserialization code should be generated, not hand written
 
 const (
 	rpcRequestPacketType packetType = iota
 	rpcResponsePacketType
-	closingNowPacketType // informs peer that we will immediately close the connection
-	ctxEndPacketType     // informs service runner that the provided function context expired
+	closingNowPacketType         // informs peer that we will immediately close the connection
+	ctxEndPacketType             // informs service runner that the provided function context expired
+	streamItemPacketType         // one item of a server-streaming function's result
+	streamCreditPacketType       // allows the peer to send us more stream items
+	paramStreamRequestPacketType // request, whose streamed parameter's items follow as paramItem packets
+	paramItemPacketType          // one item of a streamed parameter
+	paramEndPacketType           // no more items of a streamed parameter will follow
+	paramCreditPacketType        // allows the peer to send us more items of a streamed parameter
+	errorResponsePacketType      // the request failed before producing a regular response
+	registerClientPacketType     // asks peer, whether it provides a service. peer responds with an alias for it
+	pingPacketType               // keepalive ping. peer answers with pong
+	pongPacketType               // answer to keepalive ping
+	goawayPacketType             // we are shutting down. peer shouldn't send us new requests
+	rejectPacketType             // server refuses the connection. it follows the handshake and the connection is closed
 )
 
 type packetType uint8
@@ -46,21 +63,39 @@ func (rn reqNumT) Serialize(e *irpcgen.Encoder) error    { return irpcgen.EncUin
 func (rn *reqNumT) Deserialize(d *irpcgen.Decoder) error { return irpcgen.DecUint16(d, (*uint16)(rn)) }
 
 type requestPacket struct {
-	ReqNum    reqNumT
-	ServiceId irpcgen.ServiceId
-	FuncId    irpcgen.FuncId
+	ReqNum       reqNumT
+	ServiceAlias uint64            // alias negotiated by RegisterClient. 0 if there is none
+	ServiceId    irpcgen.ServiceId // only sent, if there is no ServiceAlias
+	FuncId       irpcgen.FuncId
+	Deadline     time.Time // caller's deadline. zero if there is none
+	Metadata     Metadata
 }
 
 func (rp requestPacket) Serialize(e *irpcgen.Encoder) error {
 	if err := rp.ReqNum.Serialize(e); err != nil {
 		return err
 	}
-	if err := rp.ServiceId.Serialize(e); err != nil {
+	if err := irpcgen.EncUint64(e, rp.ServiceAlias); err != nil {
 		return err
 	}
+	if rp.ServiceAlias == 0 {
+		if err := rp.ServiceId.Serialize(e); err != nil {
+			return err
+		}
+	}
 	if err := irpcgen.EncUint64(e, uint64(rp.FuncId)); err != nil {
 		return err
 	}
+	var deadline int64 // unix nanoseconds. 0 means no deadline
+	if !rp.Deadline.IsZero() {
+		deadline = rp.Deadline.UnixNano()
+	}
+	if err := irpcgen.EncInt64(e, deadline); err != nil {
+		return err
+	}
+	if err := rp.Metadata.Serialize(e); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -68,23 +103,42 @@ func (rp *requestPacket) Deserialize(d *irpcgen.Decoder) error {
 	if err := rp.ReqNum.Deserialize(d); err != nil {
 		return err
 	}
-	if err := rp.ServiceId.Deserialize(d); err != nil {
+	if err := irpcgen.DecUint64(d, &rp.ServiceAlias); err != nil {
 		return err
 	}
+	if rp.ServiceAlias == 0 {
+		if err := rp.ServiceId.Deserialize(d); err != nil {
+			return err
+		}
+	}
 	if err := irpcgen.DecUint64(d, (*uint64)(&rp.FuncId)); err != nil {
 		return err
 	}
+	var deadline int64
+	if err := irpcgen.DecInt64(d, &deadline); err != nil {
+		return err
+	}
+	if deadline != 0 {
+		rp.Deadline = time.Unix(0, deadline)
+	}
+	if err := rp.Metadata.Deserialize(d); err != nil {
+		return err
+	}
 	return nil
 }
 
 type responsePacket struct {
-	ReqNum reqNumT // request number that initiated this response
+	ReqNum  reqNumT  // request number that initiated this response
+	Trailer Metadata // set by service function with SetTrailer()
 }
 
 func (rp responsePacket) Serialize(e *irpcgen.Encoder) error {
 	if err := rp.ReqNum.Serialize(e); err != nil {
 		return err
 	}
+	if err := rp.Trailer.Serialize(e); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -92,6 +146,9 @@ func (rp *responsePacket) Deserialize(d *irpcgen.Decoder) error {
 	if err := rp.ReqNum.Deserialize(d); err != nil {
 		return err
 	}
+	if err := rp.Trailer.Deserialize(d); err != nil {
+		return err
+	}
 	return nil
 }
 
@@ -119,3 +176,222 @@ func (p *ctxEndPacket) Deserialize(d *irpcgen.Decoder) error {
 	}
 	return nil
 }
+
+// streamItemPacket precedes one serialized item of a stream
+type streamItemPacket struct {
+	ReqNum reqNumT // request number that initiated the stream
+}
+
+func (p streamItemPacket) Serialize(e *irpcgen.Encoder) error {
+	if err := p.ReqNum.Serialize(e); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *streamItemPacket) Deserialize(d *irpcgen.Decoder) error {
+	if err := p.ReqNum.Deserialize(d); err != nil {
+		return err
+	}
+	return nil
+}
+
+// streamCreditPacket allows the peer to send Credit more items of the stream
+type streamCreditPacket struct {
+	ReqNum reqNumT
+	Credit uint64
+}
+
+func (p streamCreditPacket) Serialize(e *irpcgen.Encoder) error {
+	if err := p.ReqNum.Serialize(e); err != nil {
+		return err
+	}
+	if err := irpcgen.EncUint64(e, p.Credit); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *streamCreditPacket) Deserialize(d *irpcgen.Decoder) error {
+	if err := p.ReqNum.Deserialize(d); err != nil {
+		return err
+	}
+	if err := irpcgen.DecUint64(d, &p.Credit); err != nil {
+		return err
+	}
+	return nil
+}
+
+// paramItemPacket precedes one serialized item of a streamed parameter
+type paramItemPacket struct {
+	ReqNum reqNumT
+}
+
+func (p paramItemPacket) Serialize(e *irpcgen.Encoder) error {
+	if err := p.ReqNum.Serialize(e); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *paramItemPacket) Deserialize(d *irpcgen.Decoder) error {
+	if err := p.ReqNum.Deserialize(d); err != nil {
+		return err
+	}
+	return nil
+}
+
+// paramEndPacket is the last packet of a streamed parameter
+// it is sent even if the peer has already responded, so that the peer knows, there is nothing more to read
+type paramEndPacket struct {
+	ReqNum reqNumT
+}
+
+func (p paramEndPacket) Serialize(e *irpcgen.Encoder) error {
+	if err := p.ReqNum.Serialize(e); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *paramEndPacket) Deserialize(d *irpcgen.Decoder) error {
+	if err := p.ReqNum.Deserialize(d); err != nil {
+		return err
+	}
+	return nil
+}
+
+// errorKind says, why the request failed without regular response
+type errorKind uint8
+
+const (
+	panicErrorKind           errorKind = iota // service function panicked
+	serviceNotFoundErrorKind                  // requested service is not registered
+	funcNotFoundErrorKind                     // requested service doesn't have the function
+	rejectedErrorKind                         // server interceptor refused the call
+	drainingErrorKind                         // endpoint is shutting down and doesn't accept new requests
+)
+
+// errorResponsePacket replaces the response of a failed request
+type errorResponsePacket struct {
+	ReqNum reqNumT
+	Kind   errorKind
+	Msg    string
+	Stack  string // stack trace of a panic. empty for other kinds
+}
+
+// err converts the packet to error returned to our caller
+func (p errorResponsePacket) err() error {
+	switch p.Kind {
+	case panicErrorKind:
+		return &RemotePanicError{Value: p.Msg, Stack: p.Stack}
+	case serviceNotFoundErrorKind:
+		return fmt.Errorf("%w: %s", ErrServiceNotFound, p.Msg)
+	case funcNotFoundErrorKind:
+		return fmt.Errorf("%w: %s", ErrFunctionNotFound, p.Msg)
+	case rejectedErrorKind:
+		return fmt.Errorf("%w: %s", ErrCallRejected, p.Msg)
+	case drainingErrorKind:
+		return fmt.Errorf("%w: %s", ErrEndpointDraining, p.Msg)
+	default:
+		return fmt.Errorf("irpc: remote call failed: %s", p.Msg)
+	}
+}
+
+func (p errorResponsePacket) Serialize(e *irpcgen.Encoder) error {
+	if err := p.ReqNum.Serialize(e); err != nil {
+		return err
+	}
+	if err := irpcgen.EncUint8(e, p.Kind); err != nil {
+		return err
+	}
+	if err := irpcgen.EncString(e, p.Msg); err != nil {
+		return err
+	}
+	if err := irpcgen.EncString(e, p.Stack); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *errorResponsePacket) Deserialize(d *irpcgen.Decoder) error {
+	if err := p.ReqNum.Deserialize(d); err != nil {
+		return err
+	}
+	if err := irpcgen.DecUint8(d, &p.Kind); err != nil {
+		return err
+	}
+	if err := irpcgen.DecString(d, &p.Msg); err != nil {
+		return err
+	}
+	if err := irpcgen.DecString(d, &p.Stack); err != nil {
+		return err
+	}
+	return nil
+}
+
+// registerClientPacket asks peer for an alias of service it provides
+// peer responds with registerClientResponse, or with error response if it doesn't provide the service
+type registerClientPacket struct {
+	ReqNum    reqNumT
+	ServiceId irpcgen.ServiceId
+}
+
+func (p registerClientPacket) Serialize(e *irpcgen.Encoder) error {
+	if err := p.ReqNum.Serialize(e); err != nil {
+		return err
+	}
+	if err := p.ServiceId.Serialize(e); err != nil {
+		return err
+	}
+	return nil
+}
+
+func (p *registerClientPacket) Deserialize(d *irpcgen.Decoder) error {
+	if err := p.ReqNum.Deserialize(d); err != nil {
+		return err
+	}
+	if err := p.ServiceId.Deserialize(d); err != nil {
+		return err
+	}
+	return nil
+}
+
+// registerClientResponse is sent as the response data of registerClientPacket
+type registerClientResponse struct {
+	Alias uint64 // used instead of service id in subsequent requests
+}
+
+func (p registerClientResponse) Serialize(e *irpcgen.Encoder) error {
+	return irpcgen.EncUint64(e, p.Alias)
+}
+
+func (p *registerClientResponse) Deserialize(d *irpcgen.Decoder) error {
+	return irpcgen.DecUint64(d, &p.Alias)
+}
+
+// pingPacket is sent as both ping and pong. pong echoes ping's sequence number
+type pingPacket struct {
+	Seq uint64
+}
+
+func (p pingPacket) Serialize(e *irpcgen.Encoder) error {
+	return irpcgen.EncUint64(e, p.Seq)
+}
+
+func (p *pingPacket) Deserialize(d *irpcgen.Decoder) error {
+	return irpcgen.DecUint64(d, &p.Seq)
+}
+
+// rejectPacket tells the peer, why its connection was refused
+type rejectPacket struct {
+	Reason string
+}
+
+func (p rejectPacket) Serialize(e *irpcgen.Encoder) error {
+	return irpcgen.EncString(e, p.Reason)
+}
+
+func (p *rejectPacket) Deserialize(d *irpcgen.Decoder) error {
+	return irpcgen.DecString(d, &p.Reason)
+}
@@ -0,0 +1,484 @@
+package irpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// ErrNotConnected is returned by calls of [ReconnectingEndpoint], that is waiting to redial its peer.
+var ErrNotConnected = errors.New("irpc: not connected")
+
+// ErrClientRefused is returned by calls of [ReconnectingEndpoint], whose peer refused to register one of its clients.
+var ErrClientRefused = errors.New("irpc: peer refused client")
+
+// DialFunc opens a new connection to the peer. It is called by [ReconnectingEndpoint] on each connection attempt.
+type DialFunc func(ctx context.Context) (io.ReadWriteCloser, error)
+
+// Backoff returns how long to wait before the next connection attempt, after attempt consecutive failures (starting at 1).
+type Backoff func(attempt int) time.Duration
+
+// ExponentialBackoff doubles the wait after each failed attempt, starting at base and never exceeding max.
+func ExponentialBackoff(base, max time.Duration) Backoff {
+	return func(attempt int) time.Duration {
+		d := base
+		for i := 1; i < attempt && d < max; i++ {
+			d *= 2
+		}
+		return min(d, max)
+	}
+}
+
+// DefaultReconnectBackoff is the backoff of [ReconnectingEndpoint].
+// It can be overridden for each endpoint with [WithBackoff] option
+var DefaultReconnectBackoff = ExponentialBackoff(100*time.Millisecond, 10*time.Second)
+
+// ConnState is the state of the connection of [ReconnectingEndpoint].
+type ConnState int
+
+const (
+	StateConnecting   ConnState = iota // dialing the peer. calls wait for the result
+	StateConnected                     // calls are sent to the peer
+	StateDisconnected                  // last attempt failed, or the connection was lost. waiting for the next attempt
+	StateClosed                        // endpoint was closed. it won't reconnect
+	StateFailed                        // peer refused one of our clients. it won't reconnect. see [ReconnectingEndpoint.Err]
+)
+
+func (s ConnState) String() string {
+	switch s {
+	case StateConnecting:
+		return "connecting"
+	case StateConnected:
+		return "connected"
+	case StateDisconnected:
+		return "disconnected"
+	case StateClosed:
+		return "closed"
+	case StateFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ReconnectingEndpoint is a client side endpoint, that redials its peer, whenever the connection is lost.
+// Generated clients can use it in place of [Endpoint].
+//
+// Calls made while connecting wait for the connection. Calls made while disconnected fail with [ErrNotConnected],
+// unless [WithRetryNotSent] option is used.
+//
+// Failed connection attempts are retried, but if the peer refuses to register any of our clients (ie it doesn't provide the service),
+// redialing won't help. The endpoint then stops reconnecting, switches to [StateFailed] and its calls fail with the error, see [ReconnectingEndpoint.Err].
+//
+// ReconnectingEndpoint implements [irpcgen.Endpoint].
+type ReconnectingEndpoint struct {
+	dial          DialFunc
+	backoff       Backoff
+	endpointOpts  []EndpointOption
+	retryNotSent  bool
+	onStateChange func(ConnState)
+
+	// registered on every new connection
+	services []irpcgen.Service
+	clients  map[irpcgen.ServiceId]*irpcgen.ServiceDesc // description is nil if unknown
+
+	state    ConnState
+	ep       *Endpoint     // nil, unless connected
+	err      error         // why we failed. nil, unless in StateFailed
+	changedC chan struct{} // closed and replaced on every state change
+	m        sync.Mutex
+
+	// ends on Close()
+	ctx       context.Context
+	ctxCancel context.CancelFunc
+	doneC     chan struct{} // closed once the connection loop exits
+}
+
+// NewReconnectingEndpoint creates an endpoint, that connects using dial and redials, whenever the connection is lost.
+// It immediately starts connecting in a separate goroutine.
+func NewReconnectingEndpoint(dial DialFunc, opts ...ReconnectOption) *ReconnectingEndpoint {
+	ctx, cancel := context.WithCancel(context.Background())
+	r := &ReconnectingEndpoint{
+		dial:      dial,
+		backoff:   DefaultReconnectBackoff,
+		clients:   make(map[irpcgen.ServiceId]*irpcgen.ServiceDesc),
+		state:     StateConnecting,
+		changedC:  make(chan struct{}),
+		ctx:       ctx,
+		ctxCancel: cancel,
+		doneC:     make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	go r.run()
+
+	return r
+}
+
+// run keeps us connected until Close()
+func (r *ReconnectingEndpoint) run() {
+	defer close(r.doneC)
+	defer r.setState(StateClosed, nil)
+
+	failures := 0
+	for {
+		if failures > 0 {
+			select {
+			case <-time.After(r.backoff(failures)):
+			case <-r.ctx.Done():
+				return
+			}
+		}
+
+		r.setState(StateConnecting, nil)
+		ep, err := r.connect()
+		if err != nil {
+			if r.ctx.Err() != nil {
+				return
+			}
+			if errors.Is(err, ErrClientRefused) {
+				r.m.Lock()
+				r.err = err
+				r.m.Unlock()
+				r.setState(StateFailed, nil)
+				<-r.ctx.Done()
+				return
+			}
+			failures++
+			r.setState(StateDisconnected, nil)
+			continue
+		}
+		failures = 0
+
+		select {
+		case <-ep.Context().Done():
+			r.setState(StateDisconnected, nil)
+		case <-r.ctx.Done():
+			ep.Close()
+			return
+		}
+	}
+}
+
+// registration is what we registered with a new endpoint
+type registration struct {
+	services int // number of our services
+	clients  map[irpcgen.ServiceId]*irpcgen.ServiceDesc
+}
+
+// connect dials the peer, registers our services and clients with the new endpoint and publishes it
+func (r *ReconnectingEndpoint) connect() (*Endpoint, error) {
+	conn, err := r.dial(r.ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	r.m.Lock()
+	opts := append(r.endpointOpts[:len(r.endpointOpts):len(r.endpointOpts)], WithEndpointServices(r.services...))
+	reg := registration{
+		services: len(r.services),
+		clients:  make(map[irpcgen.ServiceId]*irpcgen.ServiceDesc),
+	}
+	r.m.Unlock()
+
+	if nc, ok := conn.(net.Conn); ok {
+		opts = append(opts, WithLocalAddress(nc.LocalAddr()), WithRemoteAddress(nc.RemoteAddr()))
+	}
+	ep := NewEndpoint(conn, opts...)
+
+	if err := r.setConnected(ep, reg); err != nil {
+		ep.Close()
+		return nil, err
+	}
+	return ep, nil
+}
+
+// setConnected registers our clients with ep and publishes it.
+// services and clients may be added while we register, so we only publish ep once there is nothing left to register
+func (r *ReconnectingEndpoint) setConnected(ep *Endpoint, reg registration) error {
+	for {
+		r.m.Lock()
+		services := r.services[reg.services:]
+		missed := make(map[irpcgen.ServiceId]*irpcgen.ServiceDesc)
+		for sid, desc := range r.clients {
+			if registered, found := reg.clients[sid]; !found || (registered == nil && desc != nil) {
+				missed[sid] = desc
+			}
+		}
+		if len(services) == 0 && len(missed) == 0 {
+			// anything added from now on registers with ep itself
+			r.changeState(StateConnected, ep)
+			r.m.Unlock()
+			break
+		}
+		r.m.Unlock()
+
+		ep.RegisterService(services...)
+		reg.services += len(services)
+		for sid, desc := range missed {
+			if err := registerWith(ep, sid, desc); err != nil {
+				if errors.Is(err, ErrServiceNotFound) {
+					return fmt.Errorf("%w: register client of service %s: %w", ErrClientRefused, sid, err)
+				}
+				return err
+			}
+			reg.clients[sid] = desc
+		}
+	}
+
+	if r.onStateChange != nil {
+		r.onStateChange(StateConnected)
+	}
+	return nil
+}
+
+// registerWith registers client with ep. desc is nil if unknown
+func registerWith(ep *Endpoint, serviceId irpcgen.ServiceId, desc *irpcgen.ServiceDesc) error {
+	if desc != nil {
+		return ep.RegisterDescribedClient(desc)
+	}
+	return ep.RegisterClient(serviceId)
+}
+
+func (r *ReconnectingEndpoint) setState(state ConnState, ep *Endpoint) {
+	r.m.Lock()
+	r.changeState(state, ep)
+	r.m.Unlock()
+
+	if r.onStateChange != nil {
+		r.onStateChange(state)
+	}
+}
+
+// changeState wakes up everyone waiting for the state to change. r.m must be held
+func (r *ReconnectingEndpoint) changeState(state ConnState, ep *Endpoint) {
+	r.state = state
+	r.ep = ep
+	close(r.changedC)
+	r.changedC = make(chan struct{})
+}
+
+// State returns the current state of the connection
+func (r *ReconnectingEndpoint) State() ConnState {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.state
+}
+
+// Err returns the error, that made the endpoint stop reconnecting, or nil if there is none.
+// It wraps [ErrClientRefused].
+func (r *ReconnectingEndpoint) Err() error {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.err
+}
+
+// Endpoint returns the currently connected endpoint, or nil if there is none
+func (r *ReconnectingEndpoint) Endpoint() *Endpoint {
+	r.m.Lock()
+	defer r.m.Unlock()
+
+	return r.ep
+}
+
+// endpoint returns connected endpoint. it waits while connecting
+// with retryNotSent, it waits while disconnected as well
+func (r *ReconnectingEndpoint) endpoint(ctx context.Context) (*Endpoint, error) {
+	for {
+		r.m.Lock()
+		state, ep, failure, changedC := r.state, r.ep, r.err, r.changedC
+		r.m.Unlock()
+
+		switch {
+		case state == StateClosed:
+			return nil, ErrEndpointClosed
+		case state == StateFailed:
+			return nil, failure
+		case state == StateDisconnected && !r.retryNotSent:
+			return nil, ErrNotConnected
+		case state == StateConnected && ep.Context().Err() == nil:
+			return ep, nil
+		}
+
+		select {
+		case <-changedC:
+		case <-ctx.Done():
+			return nil, context.Cause(ctx)
+		}
+	}
+}
+
+// requestSentKey is context key of *atomic.Bool, that Endpoint sets once the call's request is sent to the peer
+type requestSentKey struct{}
+
+// shouldRetry reports, whether the call, that failed with err on ep, should be made again
+// sent says, whether its request was sent to the peer
+func (r *ReconnectingEndpoint) shouldRetry(ctx context.Context, ep *Endpoint, err error, sent bool) bool {
+	if err == nil || !r.retryNotSent || ctx.Err() != nil {
+		return false
+	}
+
+	// draining peer refused the call. it will close the connection once it's done and we redial
+	if errors.Is(err, ErrEndpointDraining) {
+		select {
+		case <-ep.Context().Done():
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	// the connection was lost before we could send the request
+	return !sent && ep.Context().Err() != nil
+}
+
+// RegisterClient registers client with the current connection and with every future one.
+// If not connected, it returns nil and the registration happens on the next connection.
+//
+// RegisterClient implements [irpcgen.Endpoint]
+func (r *ReconnectingEndpoint) RegisterClient(serviceId irpcgen.ServiceId) error {
+	return r.registerClient(serviceId, nil)
+}
+
+// RegisterDescribedClient is [ReconnectingEndpoint.RegisterClient] for clients with known description.
+//
+// RegisterDescribedClient implements [irpcgen.DescribedEndpoint]
+func (r *ReconnectingEndpoint) RegisterDescribedClient(desc *irpcgen.ServiceDesc) error {
+	return r.registerClient(desc.Id, desc)
+}
+
+func (r *ReconnectingEndpoint) registerClient(serviceId irpcgen.ServiceId, desc *irpcgen.ServiceDesc) error {
+	r.m.Lock()
+	_, known := r.clients[serviceId]
+	if desc != nil || r.clients[serviceId] == nil {
+		r.clients[serviceId] = desc
+	}
+	ep := r.ep
+	r.m.Unlock()
+
+	if ep == nil {
+		return nil
+	}
+	err := registerWith(ep, serviceId, desc)
+	if errors.Is(err, ErrServiceNotFound) && !known {
+		// caller learns about it now. it mustn't fail our future connections
+		r.m.Lock()
+		delete(r.clients, serviceId)
+		r.m.Unlock()
+	}
+	return err
+}
+
+// RegisterService registers services with the current connection and with every future one, so that our peer can call them.
+func (r *ReconnectingEndpoint) RegisterService(services ...irpcgen.Service) {
+	r.m.Lock()
+	r.services = append(r.services, services...)
+	ep := r.ep
+	r.m.Unlock()
+
+	if ep != nil {
+		ep.RegisterService(services...)
+	}
+}
+
+// CallRemoteFunc invokes a function on the currently connected peer.
+//
+// CallRemoteFunc implements [irpcgen.Endpoint]
+func (r *ReconnectingEndpoint) CallRemoteFunc(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, respData irpcgen.Deserializable) error {
+	for {
+		ep, err := r.endpoint(ctx)
+		if err != nil {
+			return err
+		}
+		sent := new(atomic.Bool)
+		err = ep.CallRemoteFunc(context.WithValue(ctx, requestSentKey{}, sent), serviceId, funcId, reqData, respData)
+		if !r.shouldRetry(ctx, ep, err, sent.Load()) {
+			return err
+		}
+	}
+}
+
+// CallRemoteStream invokes a server-streaming function on the currently connected peer.
+//
+// CallRemoteStream implements [irpcgen.Endpoint]
+func (r *ReconnectingEndpoint) CallRemoteStream(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, newItem func() irpcgen.Deserializable, respData irpcgen.Deserializable) iter.Seq2[irpcgen.Deserializable, error] {
+	return func(yield func(irpcgen.Deserializable, error) bool) {
+		for {
+			ep, err := r.endpoint(ctx)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+
+			// call, that wasn't sent, fails with its first and only element
+			retry := false
+			sent := new(atomic.Bool)
+			sentCtx := context.WithValue(ctx, requestSentKey{}, sent)
+			for item, err := range ep.CallRemoteStream(sentCtx, serviceId, funcId, reqData, newItem, respData) {
+				if r.shouldRetry(ctx, ep, err, sent.Load()) {
+					retry = true
+					break
+				}
+				if !yield(item, err) {
+					return
+				}
+			}
+			if !retry {
+				return
+			}
+		}
+	}
+}
+
+// Close closes the current connection and stops reconnecting.
+func (r *ReconnectingEndpoint) Close() error {
+	r.ctxCancel()
+	<-r.doneC
+	return nil
+}
+
+type ReconnectOption func(*ReconnectingEndpoint)
+
+// WithBackoff sets the wait between failed connection attempts. See [DefaultReconnectBackoff].
+func WithBackoff(backoff Backoff) ReconnectOption {
+	return func(r *ReconnectingEndpoint) {
+		r.backoff = backoff
+	}
+}
+
+// WithReconnectEndpointOptions sets options of every [Endpoint] created for a new connection.
+func WithReconnectEndpointOptions(opts ...EndpointOption) ReconnectOption {
+	return func(r *ReconnectingEndpoint) {
+		r.endpointOpts = append(r.endpointOpts, opts...)
+	}
+}
+
+// WithRetryNotSent makes calls wait for a connection, instead of failing with [ErrNotConnected],
+// and retries calls, that failed without reaching the peer, once reconnected.
+// Calls are still bounded by their context.
+func WithRetryNotSent() ReconnectOption {
+	return func(r *ReconnectingEndpoint) {
+		r.retryNotSent = true
+	}
+}
+
+// WithStateHandler sets a function called on every change of the connection's state.
+// It is called synchronously from the goroutine managing the connection.
+// On [StateFailed], the reason is available from [ReconnectingEndpoint.Err].
+func WithStateHandler(f func(ConnState)) ReconnectOption {
+	return func(r *ReconnectingEndpoint) {
+		r.onStateChange = f
+	}
+}
@@ -0,0 +1,203 @@
+package irpc_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/marben/irpc"
+	"github.com/marben/irpc/cmd/irpc/test/testtools"
+	"github.com/marben/irpc/irpcgen"
+)
+
+func TestReconnectingEndpoint(t *testing.T) {
+	service := testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0))
+	// peer's side of each connection we dial
+	peersC := make(chan *irpc.Endpoint, 10)
+	dial := func(ctx context.Context) (io.ReadWriteCloser, error) {
+		c1, c2 := net.Pipe()
+		peersC <- irpc.NewEndpoint(c2, irpc.WithEndpointServices(service))
+		return c1, nil
+	}
+
+	stateC := make(chan irpc.ConnState, 20)
+	rep := irpc.NewReconnectingEndpoint(dial,
+		irpc.WithRetryNotSent(),
+		irpc.WithStateHandler(func(s irpc.ConnState) { stateC <- s }),
+	)
+	// peer calls our service over every connection
+	rep.RegisterService(testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(1)))
+
+	client, err := testtools.NewTestServiceIrpcClient(rep)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	if res, err := client.DivErr(6, 2); err != nil || res != 3 {
+		t.Fatalf("DivErr(): %d, %v", res, err)
+	}
+
+	// drop the connection
+	peer := <-peersC
+	peer.Close()
+	waitForState := func(want irpc.ConnState) {
+		t.Helper()
+		for s := range stateC {
+			if s == want {
+				return
+			}
+		}
+	}
+	waitForState(irpc.StateDisconnected)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if res, err := client.DivCtxErr(ctx, 8, 2); err != nil || res != 4 {
+		t.Fatalf("DivCtxErr() after reconnect: %d, %v", res, err)
+	}
+	waitForState(irpc.StateConnected)
+
+	peer = <-peersC
+	defer peer.Close()
+	peerClient, err := testtools.NewTestServiceIrpcClient(peer)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	if res, err := peerClient.DivErr(6, 2); err != nil || res != 3+1 {
+		t.Fatalf("peer's DivErr(): %d, %v", res, err)
+	}
+
+	if err := rep.Close(); err != nil {
+		t.Fatalf("Close(): %v", err)
+	}
+	waitForState(irpc.StateClosed)
+	if _, err := client.DivErr(6, 2); !errors.Is(err, irpc.ErrEndpointClosed) {
+		t.Fatalf("expected ErrEndpointClosed, got: %v", err)
+	}
+}
+
+func TestReconnectingEndpointFailsWhileDisconnected(t *testing.T) {
+	errDial := errors.New("dial failed")
+	dial := func(ctx context.Context) (io.ReadWriteCloser, error) {
+		return nil, errDial
+	}
+	stateC := make(chan irpc.ConnState, 20)
+	rep := irpc.NewReconnectingEndpoint(dial,
+		irpc.WithBackoff(func(int) time.Duration { return time.Hour }),
+		irpc.WithStateHandler(func(s irpc.ConnState) { stateC <- s }),
+	)
+	defer rep.Close()
+
+	client, err := testtools.NewTestServiceIrpcClient(rep)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	for s := range stateC {
+		if s == irpc.StateDisconnected {
+			break
+		}
+	}
+	if _, err := client.DivErr(6, 2); !errors.Is(err, irpc.ErrNotConnected) {
+		t.Fatalf("expected ErrNotConnected, got: %v", err)
+	}
+}
+
+// emptyService has no functions. it only lets clients register with it
+type emptyService struct{}
+
+func (emptyService) Id() irpcgen.ServiceId { return irpcgen.ServiceId(1) }
+
+func (emptyService) GetFuncCall(funcId irpcgen.FuncId) (irpcgen.ArgDeserializer, error) {
+	return nil, fmt.Errorf("function %d not found", funcId)
+}
+
+func TestReconnectingEndpointRegistersClientsAddedWhileConnecting(t *testing.T) {
+	proceedC := make(chan struct{})
+	c1, c2 := net.Pipe()
+	dial := func(ctx context.Context) (io.ReadWriteCloser, error) {
+		<-proceedC
+		return c1, nil
+	}
+	// registered client's description names the called method
+	methodC := make(chan string, 1)
+	interceptor := func(ctx context.Context, call irpc.CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable, invoke irpc.ClientInvoker) error {
+		methodC <- call.Method
+		return invoke(ctx, call, req, resp)
+	}
+	rep := irpc.NewReconnectingEndpoint(dial, irpc.WithReconnectEndpointOptions(irpc.WithServiceNegotiation(), irpc.WithClientInterceptors(interceptor)))
+	defer rep.Close()
+
+	// registration of the first client waits for the peer's handshake
+	if err := rep.RegisterClient(emptyService{}.Id()); err != nil {
+		t.Fatalf("RegisterClient(): %v", err)
+	}
+	close(proceedC)
+	time.Sleep(20 * time.Millisecond)
+
+	client, err := testtools.NewTestServiceIrpcClient(rep)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	peer := irpc.NewEndpoint(c2, irpc.WithServiceNegotiation(), irpc.WithEndpointServices(emptyService{}, testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0))))
+	defer peer.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if res, err := client.DivCtxErr(ctx, 6, 2); err != nil || res != 3 {
+		t.Fatalf("DivCtxErr(): %d, %v", res, err)
+	}
+	if m := <-methodC; m != "TestService.DivCtxErr" {
+		t.Fatalf("client was not registered with the new connection. called method: %q", m)
+	}
+}
+
+func TestReconnectingEndpointStopsOnRefusedClient(t *testing.T) {
+	var dials atomic.Int32
+	dial := func(ctx context.Context) (io.ReadWriteCloser, error) {
+		dials.Add(1)
+		c1, c2 := net.Pipe()
+		peer := irpc.NewEndpoint(c2, irpc.WithServiceNegotiation())
+		go func() {
+			<-ctx.Done()
+			peer.Close()
+		}()
+		return c1, nil
+	}
+	stateC := make(chan irpc.ConnState, 20)
+	rep := irpc.NewReconnectingEndpoint(dial,
+		irpc.WithBackoff(func(int) time.Duration { return time.Millisecond }),
+		irpc.WithReconnectEndpointOptions(irpc.WithServiceNegotiation()),
+		irpc.WithRetryNotSent(),
+		irpc.WithStateHandler(func(s irpc.ConnState) { stateC <- s }),
+	)
+	defer rep.Close()
+
+	// peer doesn't provide the service
+	if err := rep.RegisterClient(emptyService{}.Id()); err != nil {
+		t.Fatalf("RegisterClient(): %v", err)
+	}
+	for s := range stateC {
+		if s == irpc.StateFailed {
+			break
+		}
+	}
+	if err := rep.Err(); !errors.Is(err, irpc.ErrClientRefused) || !errors.Is(err, irpc.ErrServiceNotFound) {
+		t.Fatalf("expected ErrClientRefused, got: %v", err)
+	}
+
+	client, err := testtools.NewTestServiceIrpcClient(rep)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	if _, err := client.DivErr(6, 2); !errors.Is(err, irpc.ErrClientRefused) {
+		t.Fatalf("expected ErrClientRefused, got: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if n := dials.Load(); n != 1 {
+		t.Fatalf("redialed after refusal: %d dials", n)
+	}
+}
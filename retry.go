@@ -0,0 +1,166 @@
+package irpc
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"time"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// RetryPolicy determines, how [RetryingEndpoint] retries failed calls.
+type RetryPolicy struct {
+	MaxAttempts int              // including the first one. values below 2 disable retries
+	Backoff     Backoff          // wait before n-th retry (starting at 1). nil means no wait
+	Retryable   func(error) bool // nil means [IsRetryable]
+}
+
+// DefaultRetryPolicy is a reasonable policy for [NewRetryingEndpoint].
+var DefaultRetryPolicy = RetryPolicy{
+	MaxAttempts: 3,
+	Backoff:     ExponentialBackoff(50*time.Millisecond, time.Second),
+	Retryable:   IsRetryable,
+}
+
+// IsRetryable reports, whether err is a transport failure, after which the call may succeed, if made again.
+func IsRetryable(err error) bool {
+	return errors.Is(err, ErrEndpointClosedByPeer) ||
+		errors.Is(err, ErrEndpointDraining) ||
+		errors.Is(err, ErrPeerUnresponsive) ||
+		errors.Is(err, ErrNotConnected) ||
+		errors.Is(err, ErrNoEndpoints)
+}
+
+// RetryingEndpoint retries failed calls of idempotent methods according to its [RetryPolicy].
+//
+// Methods opt in with //irpc:idempotent directive in the interface definition:
+//
+//	type KVStore interface {
+//		//irpc:idempotent
+//		Get(ctx context.Context, key string) (string, error)
+//		Incr(ctx context.Context, key string) (int, error) // never retried
+//	}
+//
+// Other methods, methods of clients without generated description and methods with streamed parameter are never retried.
+// Streams are only retried, if they fail before yielding their first item.
+//
+// Retrying makes sense on top of an endpoint, that switches connections, such as [ReconnectingEndpoint] or [Pool].
+//
+// RetryingEndpoint implements [irpcgen.Endpoint].
+type RetryingEndpoint struct {
+	endpoint irpcgen.Endpoint
+	policy   RetryPolicy
+
+	descs map[irpcgen.ServiceId]*irpcgen.ServiceDesc
+	m     sync.Mutex
+}
+
+// NewRetryingEndpoint creates endpoint, that makes calls over endpoint and retries them according to policy.
+func NewRetryingEndpoint(endpoint irpcgen.Endpoint, policy RetryPolicy) *RetryingEndpoint {
+	if policy.Retryable == nil {
+		policy.Retryable = IsRetryable
+	}
+	return &RetryingEndpoint{
+		endpoint: endpoint,
+		policy:   policy,
+		descs:    make(map[irpcgen.ServiceId]*irpcgen.ServiceDesc),
+	}
+}
+
+// RegisterClient implements [irpcgen.Endpoint]
+func (r *RetryingEndpoint) RegisterClient(serviceId irpcgen.ServiceId) error {
+	return r.endpoint.RegisterClient(serviceId)
+}
+
+// RegisterDescribedClient remembers, which of the service's methods are idempotent.
+//
+// RegisterDescribedClient implements [irpcgen.DescribedEndpoint]
+func (r *RetryingEndpoint) RegisterDescribedClient(desc *irpcgen.ServiceDesc) error {
+	r.m.Lock()
+	r.descs[desc.Id] = desc
+	r.m.Unlock()
+
+	return irpcgen.RegisterClient(r.endpoint, desc)
+}
+
+// idempotent reports, whether the function is known to be idempotent
+func (r *RetryingEndpoint) idempotent(serviceId irpcgen.ServiceId, funcId irpcgen.FuncId) bool {
+	r.m.Lock()
+	desc := r.descs[serviceId]
+	r.m.Unlock()
+
+	if desc == nil {
+		return false
+	}
+	m, ok := desc.Method(funcId)
+	return ok && m.Idempotent
+}
+
+// maxAttempts returns how many times the call can be made
+func (r *RetryingEndpoint) maxAttempts(serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable) int {
+	if _, ok := reqData.(irpcgen.StreamSerializable); ok {
+		// streamed parameter's items can't be sent again
+		return 1
+	}
+	if !r.idempotent(serviceId, funcId) {
+		return 1
+	}
+	return max(r.policy.MaxAttempts, 1)
+}
+
+// shouldRetry reports, whether the call, that failed with err, should be made again
+// it waits for the policy's backoff first
+func (r *RetryingEndpoint) shouldRetry(ctx context.Context, err error, attempt, maxAttempts int) bool {
+	if err == nil || attempt >= maxAttempts || ctx.Err() != nil || !r.policy.Retryable(err) {
+		return false
+	}
+	if r.policy.Backoff == nil {
+		return true
+	}
+
+	timer := time.NewTimer(r.policy.Backoff(attempt))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// CallRemoteFunc implements [irpcgen.Endpoint]
+func (r *RetryingEndpoint) CallRemoteFunc(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, respData irpcgen.Deserializable) error {
+	maxAttempts := r.maxAttempts(serviceId, funcId, reqData)
+	for attempt := 1; ; attempt++ {
+		err := r.endpoint.CallRemoteFunc(ctx, serviceId, funcId, reqData, respData)
+		if !r.shouldRetry(ctx, err, attempt, maxAttempts) {
+			return err
+		}
+	}
+}
+
+// CallRemoteStream implements [irpcgen.Endpoint]
+func (r *RetryingEndpoint) CallRemoteStream(ctx context.Context, serviceId irpcgen.ServiceId, funcId irpcgen.FuncId, reqData irpcgen.Serializable, newItem func() irpcgen.Deserializable, respData irpcgen.Deserializable) iter.Seq2[irpcgen.Deserializable, error] {
+	return func(yield func(irpcgen.Deserializable, error) bool) {
+		maxAttempts := r.maxAttempts(serviceId, funcId, reqData)
+		for attempt := 1; ; attempt++ {
+			retry := false
+			yielded := false
+			for item, err := range r.endpoint.CallRemoteStream(ctx, serviceId, funcId, reqData, newItem, respData) {
+				if !yielded && r.shouldRetry(ctx, err, attempt, maxAttempts) {
+					retry = true
+					break
+				}
+				yielded = true
+				if !yield(item, err) {
+					return
+				}
+			}
+			if !retry {
+				return
+			}
+		}
+	}
+}
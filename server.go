@@ -1,8 +1,10 @@
 package irpc
 
 import (
+	"context"
 	"errors"
 	"fmt"
+	"log/slog"
 	"net"
 	"sync"
 	"sync/atomic"
@@ -21,8 +23,21 @@ type Server struct {
 
 	onConnect func(*Endpoint)
 
+	panicPolicy PanicPolicy  // panic policy of every accepted connection's endpoint
+	logger      *slog.Logger // nil if we don't log
+
+	endpointOptions []EndpointOption                     // applied to every accepted connection's endpoint
+	connOptions     func(conn net.Conn) []EndpointOption // nil if there are no per-connection options
+
+	acceptFilter AcceptFilter // nil if we accept everyone
+	limiter      *connLimiter
+	overLimit    OverLimitPolicy
+
 	inShutdown atomic.Bool
 
+	onShutdown    []func()
+	onShutdownMux sync.Mutex
+
 	listeners    map[net.Listener]struct{} // todo: should we store pointers in a similar fashion std http server does?
 	listenersMux sync.Mutex
 	listenersWg  sync.WaitGroup
@@ -30,8 +45,10 @@ type Server struct {
 
 func NewServer(opts ...ServerOption) *Server {
 	s := &Server{
-		listeners: make(map[net.Listener]struct{}),
-		clients:   make(map[*Endpoint]struct{}),
+		listeners:   make(map[net.Listener]struct{}),
+		clients:     make(map[*Endpoint]struct{}),
+		panicPolicy: DefaultPanicPolicy,
+		limiter:     newConnLimiter(),
 	}
 	for _, opt := range opts {
 		opt(s)
@@ -74,7 +91,9 @@ func (s *Server) rmListener(l net.Listener) {
 
 // Serve always returns a non-nil error. After [Server.Close], the returned error is [ErrServerClosed]
 func (s *Server) Serve(lis net.Listener) error {
-	// log.Printf("irpc server: serving %s on addr %s", lis.Addr().Network(), lis.Addr())
+	if s.logger != nil {
+		s.logger.Info("serving", "network", lis.Addr().Network(), "addr", lis.Addr().String())
+	}
 	if err := s.addListener(lis); err != nil {
 		return err
 	}
@@ -87,44 +106,209 @@ func (s *Server) Serve(lis net.Listener) error {
 			}
 			return fmt.Errorf("listener.Accept(): %w", err)
 		}
-		// log.Println("Accept: ", conn.LocalAddr())
 
 		if s.isShuttingDown() {
 			conn.Close()
 			return ErrServerClosed
 		}
 
-		ep := NewEndpoint(conn,
-			WithEndpointServices(s.services...),
-			WithLocalAddress(conn.LocalAddr()),
-			WithRemoteAddress(conn.RemoteAddr()),
-		)
+		if s.acceptFilter != nil {
+			if err := s.acceptFilter(conn); err != nil {
+				s.reject(conn, err.Error())
+				continue
+			}
+		}
+
+		ip := remoteIP(conn)
+		if reason, ok := s.limiter.tryAcquire(ip); !ok {
+			if s.overLimit == OverLimitQueue {
+				s.queue(conn, ip)
+			} else {
+				s.reject(conn, reason)
+			}
+			continue
+		}
+
+		s.serveConn(conn, ip)
+	}
+}
+
+// reject refuses the connection in the background
+func (s *Server) reject(conn net.Conn, reason string) {
+	if s.logger != nil {
+		s.logger.Info("connection rejected", "remote_addr", conn.RemoteAddr().String(), "reason", reason)
+	}
+	if !s.goTracked(func() { rejectConn(conn, reason) }) {
+		conn.Close()
+	}
+}
+
+// queue serves the connection, once it gets within the limits
+func (s *Server) queue(conn net.Conn, ip string) {
+	if s.logger != nil {
+		s.logger.Info("connection queued", "remote_addr", conn.RemoteAddr().String())
+	}
+	queued := s.goTracked(func() {
+		reason, ok := s.limiter.acquire(ip)
+		switch {
+		case ok:
+			s.serveConn(conn, ip)
+		case reason == "":
+			// server is shutting down
+			conn.Close()
+		default:
+			s.reject(conn, reason)
+		}
+	})
+	if !queued {
+		conn.Close()
+	}
+}
+
+// goTracked runs f in a new goroutine, that shutdown waits for. returns false without running f, if the server is shutting down
+func (s *Server) goTracked(f func()) bool {
+	// shutdown waits for clientsWg only after it went through the clients under the same lock
+	s.clientsMux.Lock()
+	defer s.clientsMux.Unlock()
 
+	if s.isShuttingDown() {
+		return false
+	}
+	s.clientsWg.Add(1)
+	go func() {
+		defer s.clientsWg.Done()
+		f()
+	}()
+	return true
+}
+
+// serveConn runs endpoint for admitted connection. it releases the connection's limiter slot once the endpoint closes
+func (s *Server) serveConn(conn net.Conn, ip string) {
+	opts := []EndpointOption{
+		WithEndpointServices(s.services...),
+		WithLocalAddress(conn.LocalAddr()),
+		WithRemoteAddress(conn.RemoteAddr()),
+		WithPanicPolicy(s.panicPolicy),
+	}
+	if s.logger != nil {
+		s.logger.Info("connection accepted", "remote_addr", conn.RemoteAddr().String())
+		opts = append(opts, WithLogger(s.logger))
+	}
+	opts = append(opts, s.endpointOptions...)
+	if s.connOptions != nil {
+		opts = append(opts, s.connOptions(conn)...)
+	}
+	ep := NewEndpoint(conn, opts...)
+
+	// shutdown may have already snapshotted the clients. it wouldn't know about this endpoint
+	s.clientsMux.Lock()
+	if s.isShuttingDown() {
+		s.clientsMux.Unlock()
+		ep.Close()
+		s.limiter.release(ip)
+		return
+	}
+	s.clients[ep] = struct{}{}
+	s.clientsWg.Add(1)
+	s.clientsMux.Unlock()
+
+	go func() {
+		defer s.clientsWg.Done()
+		if s.onConnect != nil {
+			s.onConnect(ep)
+		}
+
+		<-ep.ctx.Done()
+		// not sure what to do about errors (serve loop of http.Server doesn't seem to care, so we will follow suit for now)
 		s.clientsMux.Lock()
-		s.clients[ep] = struct{}{}
+		delete(s.clients, ep)
 		s.clientsMux.Unlock()
 
-		s.clientsWg.Add(1)
-		go func() {
-			defer s.clientsWg.Done()
-			if s.onConnect != nil {
-				s.onConnect(ep)
-			}
+		s.limiter.release(ip)
+	}()
+}
+
+// Shutdown gracefully shuts down the server. It mirrors [net/http.Server.Shutdown].
+//
+// Shutdown closes all listeners and then shuts down every connected endpoint with [Endpoint.Shutdown],
+// so that calls in progress can finish, while new ones are refused with [ErrEndpointDraining].
+// Once all endpoints are closed, Shutdown returns listener close errors.
+//
+// If ctx ends first, the remaining endpoints are closed immediately and ctx's error is returned without further waiting.
+// Once Shutdown is called, [Server.Serve] returns [ErrServerClosed].
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.inShutdown.Store(true)
+	s.limiter.close()
+
+	s.onShutdownMux.Lock()
+	for _, f := range s.onShutdown {
+		go f()
+	}
+	s.onShutdownMux.Unlock()
+
+	var multiError error
 
-			<-ep.ctx.Done()
-			// not sure what to do about errors (serve loop of http.Server doesn't seem to care, so we will follow suit for now)
-			s.clientsMux.Lock()
-			delete(s.clients, ep)
-			s.clientsMux.Unlock()
+	s.listenersMux.Lock()
+	for l := range s.listeners {
+		if err := l.Close(); err != nil {
+			multiError = errors.Join(multiError, err)
+		}
+	}
+	s.listenersMux.Unlock()
 
+	// endpoints are removed from clients once they close
+	s.clientsMux.Lock()
+	var shutdownWg sync.WaitGroup
+	for c := range s.clients {
+		shutdownWg.Add(1)
+		go func() {
+			defer shutdownWg.Done()
+			// errors are not actionable here. same as with Close()
+			c.Shutdown(ctx)
 		}()
 	}
+	s.clientsMux.Unlock()
+
+	// endpoints' shutdown is bounded by ctx, but onConnect functions or Serve loops are not
+	doneC := make(chan struct{})
+	go func() {
+		shutdownWg.Wait()
+		s.listenersWg.Wait()
+		s.clientsWg.Wait()
+		close(doneC)
+	}()
+
+	select {
+	case <-doneC:
+		return multiError
+	case <-ctx.Done():
+	}
+
+	select {
+	case <-doneC:
+		// we made it in time after all
+		return multiError
+	default:
+		s.closeClients()
+		return ctx.Err()
+	}
+}
+
+// RegisterOnShutdown registers a function to be called by [Server.Shutdown].
+// Each function runs in its own goroutine, as soon as shutdown starts.
+// It can be used by services to notify their peers.
+func (s *Server) RegisterOnShutdown(f func()) {
+	s.onShutdownMux.Lock()
+	defer s.onShutdownMux.Unlock()
+
+	s.onShutdown = append(s.onShutdown, f)
 }
 
 // Close stops accepting new connections, closes all listeners, closes all active connections,
 // waits for shutdown, and returns listener close errors.
 func (s *Server) Close() error {
 	s.inShutdown.Store(true)
+	s.limiter.close()
 
 	var multiError error
 
@@ -137,19 +321,35 @@ func (s *Server) Close() error {
 	}
 	s.listenersMux.Unlock()
 
+	s.closeClients()
+
+	s.listenersWg.Wait()
+	s.clientsWg.Wait()
+
+	return multiError
+}
+
+// closeClients closes all connected endpoints
+func (s *Server) closeClients() {
 	s.clientsMux.Lock()
+	clients := make([]*Endpoint, 0, len(s.clients))
 	for c := range s.clients {
-		// Ignore client Close() errors. During shutdown, connections may already
-		// have been closed by peers, and these errors are not actionable.
-		c.Close()
+		clients = append(clients, c)
 		delete(s.clients, c)
 	}
 	s.clientsMux.Unlock()
 
-	s.listenersWg.Wait()
-	s.clientsWg.Wait()
-
-	return multiError
+	var wg sync.WaitGroup
+	for _, c := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			// Ignore client Close() errors. During shutdown, connections may already
+			// have been closed by peers, and these errors are not actionable.
+			c.Close()
+		}()
+	}
+	wg.Wait()
 }
 
 type ServerOption func(*Server)
@@ -166,3 +366,42 @@ func WithServices(svcs ...irpcgen.Service) ServerOption {
 		s.services = append(s.services, svcs...)
 	}
 }
+
+// WithServerPanicPolicy sets the [PanicPolicy] of all connections accepted by the server
+func WithServerPanicPolicy(policy PanicPolicy) ServerOption {
+	return func(s *Server) {
+		s.panicPolicy = policy
+	}
+}
+
+// WithServerLogger makes the server log served listeners and accepted connections.
+// Endpoints of accepted connections log to the same logger (see [WithLogger]).
+// It is the server's [WithLogger]. The name differs, because [WithLogger] is already an [EndpointOption], same as with [WithServerPanicPolicy].
+func WithServerLogger(logger *slog.Logger) ServerOption {
+	return func(s *Server) {
+		s.logger = logger
+	}
+}
+
+// WithEndpointOptions adds options of endpoints created for accepted connections.
+// They are applied after the server's own settings, so they can override them.
+func WithEndpointOptions(opts ...EndpointOption) ServerOption {
+	return func(s *Server) {
+		s.endpointOptions = append(s.endpointOptions, opts...)
+	}
+}
+
+// WithConnEndpointOptions makes the server derive options of each accepted connection's endpoint from the connection.
+// They are applied last, after options set with [WithEndpointOptions]:
+//
+//	irpc.WithConnEndpointOptions(func(conn net.Conn) []irpc.EndpointOption {
+//		if internal.Contains(conn.RemoteAddr().(*net.TCPAddr).IP) {
+//			return []irpc.EndpointOption{irpc.WithParallelWorkers(64)}
+//		}
+//		return nil
+//	})
+func WithConnEndpointOptions(f func(conn net.Conn) []EndpointOption) ServerOption {
+	return func(s *Server) {
+		s.connOptions = f
+	}
+}
@@ -3,7 +3,11 @@ package irpc_test
 import (
 	"context"
 	"errors"
+	"log/slog"
 	"net"
+	"slices"
+	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -273,3 +277,319 @@ func TestIrpcServerSimpleCall(t *testing.T) {
 		t.Fatalf("clientEp.Close(): %+v", err)
 	}
 }
+
+func TestServerShutdown(t *testing.T) {
+	impl := testtools.NewTestServiceImpl(0)
+	startedC := make(chan struct{})
+	unblockC := make(chan struct{})
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		if a == 8 {
+			close(startedC)
+			<-unblockC
+		}
+		return a / b, nil
+	}
+	server := irpc.NewServer(irpc.WithServices(testtools.NewTestServiceIrpcService(impl)))
+	hookC := make(chan struct{})
+	server.RegisterOnShutdown(func() { close(hookC) })
+
+	l, err := net.Listen("tcp", ":")
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	serveErrC := make(chan error)
+	go func() { serveErrC <- server.Serve(l) }()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial(%s): %v", l.Addr().String(), err)
+	}
+	cEp := irpc.NewEndpoint(conn)
+	defer cEp.Close()
+	client, err := testtools.NewTestServiceIrpcClient(cEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+
+	runningErrC := make(chan error)
+	go func() {
+		_, err := client.DivCtxErr(context.Background(), 8, 2)
+		runningErrC <- err
+	}()
+	<-startedC
+
+	shutdownC := make(chan error)
+	go func() { shutdownC <- server.Shutdown(context.Background()) }()
+
+	<-hookC
+	if err := <-serveErrC; err != irpc.ErrServerClosed {
+		t.Fatalf("server.Serve(): %v", err)
+	}
+	for {
+		_, err := client.DivCtxErr(context.Background(), 1, 1)
+		if errors.Is(err, irpc.ErrEndpointDraining) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("DivCtxErr(): %v", err)
+		}
+	}
+
+	select {
+	case err := <-shutdownC:
+		t.Fatalf("Shutdown() returned before the running call finished: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	close(unblockC)
+	if err := <-runningErrC; err != nil {
+		t.Fatalf("running call: %v", err)
+	}
+	if err := <-shutdownC; err != nil {
+		t.Fatalf("Shutdown(): %v", err)
+	}
+	<-cEp.Context().Done()
+}
+
+func TestServerShutdownTimeout(t *testing.T) {
+	impl := testtools.NewTestServiceImpl(0)
+	startedC := make(chan struct{})
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		// ignores its context and never returns
+		close(startedC)
+		select {}
+	}
+	// neither does the onConnect hook
+	blockC := make(chan struct{})
+	defer close(blockC)
+	server := irpc.NewServer(irpc.WithServices(testtools.NewTestServiceIrpcService(impl)), irpc.WithOnConnect(func(*irpc.Endpoint) { <-blockC }))
+
+	l, err := net.Listen("tcp", ":")
+	if err != nil {
+		t.Fatalf("failed to create listener: %v", err)
+	}
+	go server.Serve(l)
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial(%s): %v", l.Addr().String(), err)
+	}
+	cEp := irpc.NewEndpoint(conn)
+	defer cEp.Close()
+	client, err := testtools.NewTestServiceIrpcClient(cEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+
+	go client.DivCtxErr(context.Background(), 8, 2)
+	<-startedC
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	shutdownC := make(chan error)
+	go func() { shutdownC <- server.Shutdown(ctx) }()
+
+	select {
+	case err := <-shutdownC:
+		if !errors.Is(err, context.DeadlineExceeded) {
+			t.Fatalf("Shutdown(): %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Shutdown() didn't return after its context expired")
+	}
+	<-cEp.Context().Done()
+}
+
+func TestServerLogger(t *testing.T) {
+	logs := &logBuffer{}
+	server := irpc.NewServer(irpc.WithServerLogger(slog.New(slog.NewJSONHandler(logs, nil))))
+
+	l, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatalf("net.Listen(): %v", err)
+	}
+	serveErrC := make(chan error, 1)
+	go func() { serveErrC <- server.Serve(l) }()
+
+	conn, err := net.Dial("tcp", l.Addr().String())
+	if err != nil {
+		t.Fatalf("net.Dial(): %v", err)
+	}
+	ep := irpc.NewEndpoint(conn)
+
+	if rec := logs.find(t, "connection accepted"); rec["remote_addr"] != conn.LocalAddr().String() {
+		t.Fatalf("accept record: %v", rec)
+	}
+
+	ep.Close()
+	if rec := logs.find(t, "connection closed"); rec["remote_addr"] != conn.LocalAddr().String() || rec["cause"] != irpc.ErrEndpointClosedByPeer.Error() {
+		t.Fatalf("close record: %v", rec)
+	}
+
+	server.Close()
+	if err := <-serveErrC; err != irpc.ErrServerClosed {
+		t.Fatalf("Serve(): %v", err)
+	}
+}
+
+// startTestServer serves test service on a local tcp listener and returns a function dialing it
+func startTestServer(t *testing.T, opts ...irpc.ServerOption) func() (*irpc.Endpoint, *testtools.TestServiceIrpcClient) {
+	t.Helper()
+	service := testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0))
+	server := irpc.NewServer(append(opts, irpc.WithServices(service))...)
+	l, err := net.Listen("tcp", "127.0.0.1:")
+	if err != nil {
+		t.Fatalf("net.Listen(): %v", err)
+	}
+	go server.Serve(l)
+	t.Cleanup(func() { server.Close() })
+
+	return func() (*irpc.Endpoint, *testtools.TestServiceIrpcClient) {
+		t.Helper()
+		conn, err := net.Dial("tcp", l.Addr().String())
+		if err != nil {
+			t.Fatalf("net.Dial(): %v", err)
+		}
+		ep := irpc.NewEndpoint(conn)
+		t.Cleanup(func() { ep.Close() })
+		client, err := testtools.NewTestServiceIrpcClient(ep)
+		if err != nil {
+			t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+		}
+		return ep, client
+	}
+}
+
+func TestServerMaxConnections(t *testing.T) {
+	dial := startTestServer(t, irpc.WithMaxConnections(1))
+
+	ep1, client1 := dial()
+	if res, err := client1.DivErr(4, 2); err != nil || res != 2 {
+		t.Fatalf("DivErr(): %d, %v", res, err)
+	}
+
+	ep2, client2 := dial()
+	if _, err := client2.DivErr(4, 2); !errors.Is(err, irpc.ErrConnectionRejected) {
+		t.Fatalf("DivErr() over the limit: %v", err)
+	}
+	if cause := context.Cause(ep2.Context()); !errors.Is(cause, irpc.ErrConnectionRejected) || !errors.Is(cause, irpc.ErrEndpointClosed) {
+		t.Fatalf("rejected endpoint's cause: %v", cause)
+	}
+
+	// once the first connection closes, there is room for another one
+	ep1.Close()
+	deadline := time.Now().Add(time.Second)
+	for {
+		_, client3 := dial()
+		_, err := client3.DivErr(4, 2)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, irpc.ErrConnectionRejected) || time.Now().After(deadline) {
+			t.Fatalf("DivErr() after the first connection closed: %v", err)
+		}
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestServerMaxConnectionsPerIPQueue(t *testing.T) {
+	dial := startTestServer(t, irpc.WithMaxConnectionsPerIP(1), irpc.WithOverLimitPolicy(irpc.OverLimitQueue))
+
+	ep1, client1 := dial()
+	if res, err := client1.DivErr(4, 2); err != nil || res != 2 {
+		t.Fatalf("DivErr(): %d, %v", res, err)
+	}
+
+	_, client2 := dial()
+	errC := make(chan error, 1)
+	go func() {
+		_, err := client2.DivErr(4, 2)
+		errC <- err
+	}()
+
+	select {
+	case err := <-errC:
+		t.Fatalf("queued connection's call returned: %v", err)
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	ep1.Close()
+	select {
+	case err := <-errC:
+		if err != nil {
+			t.Fatalf("queued connection's call: %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("queued connection was not served")
+	}
+}
+
+func TestServerQueueLimits(t *testing.T) {
+	dial := startTestServer(t, irpc.WithMaxConnectionsPerIP(1), irpc.WithOverLimitPolicy(irpc.OverLimitQueue), irpc.WithQueueLimits(1, 50*time.Millisecond))
+
+	_, client1 := dial()
+	if res, err := client1.DivErr(4, 2); err != nil || res != 2 {
+		t.Fatalf("DivErr(): %d, %v", res, err)
+	}
+
+	// one of the connections doesn't fit in the queue. the other one waits for too long
+	errC := make(chan error, 2)
+	for range 2 {
+		_, client := dial()
+		go func() {
+			_, err := client.DivErr(4, 2)
+			errC <- err
+		}()
+	}
+	var reasons []string
+	for range 2 {
+		err := <-errC
+		if !errors.Is(err, irpc.ErrConnectionRejected) {
+			t.Fatalf("DivErr() over the limit: %v", err)
+		}
+		reasons = append(reasons, err.Error())
+	}
+	slices.Sort(reasons)
+	if !strings.Contains(reasons[0], "connection queue is full") || !strings.Contains(reasons[1], "timed out in connection queue") {
+		t.Fatalf("unexpected reasons: %q", reasons)
+	}
+}
+
+func TestServerAcceptFilter(t *testing.T) {
+	dial := startTestServer(t, irpc.WithAcceptFilter(func(conn net.Conn) error {
+		return errors.New("not welcome")
+	}))
+
+	ep, client := dial()
+	if _, err := client.DivErr(4, 2); !errors.Is(err, irpc.ErrConnectionRejected) {
+		t.Fatalf("DivErr() on filtered connection: %v", err)
+	}
+	if cause := context.Cause(ep.Context()); !strings.Contains(cause.Error(), "not welcome") {
+		t.Fatalf("rejected endpoint's cause doesn't carry the reason: %v", cause)
+	}
+}
+
+func TestServerEndpointOptions(t *testing.T) {
+	var conns atomic.Int32
+	dial := startTestServer(t,
+		irpc.WithEndpointOptions(irpc.WithHello([]byte("general"))),
+		irpc.WithConnEndpointOptions(func(conn net.Conn) []irpc.EndpointOption {
+			// every other connection gets its own hello
+			if conns.Add(1)%2 == 0 {
+				return []irpc.EndpointOption{irpc.WithHello([]byte("special"))}
+			}
+			return nil
+		}),
+	)
+
+	for _, want := range []string{"general", "special"} {
+		ep, _ := dial()
+		peer, err := ep.Peer(context.Background())
+		if err != nil {
+			t.Fatalf("Peer(): %v", err)
+		}
+		if string(peer.Hello) != want {
+			t.Fatalf("server's hello: %q, want %q", peer.Hello, want)
+		}
+	}
+}
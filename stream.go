@@ -0,0 +1,208 @@
+package irpc
+
+import (
+	"context"
+	"errors"
+	"iter"
+	"sync"
+	"sync/atomic"
+
+	"github.com/marben/irpc/irpcgen"
+)
+
+// DefaultStreamWindow is the number of stream items our peer can send us before it has to wait for us to consume them.
+// It can be overridden for each endpoint with [WithStreamWindow] option
+var DefaultStreamWindow = 32
+
+// errStreamAbandoned is sent to the peer as context cancelation cause, when our client stops iterating a stream early
+var errStreamAbandoned = errors.New("irpc: stream abandoned by client")
+
+// itemStream receives stream items sent by our peer.
+// items are deserialized by the readLoop and handed over to a consumer goroutine
+type itemStream struct {
+	newItem    func() irpcgen.Deserializable
+	newItemMux sync.Mutex
+
+	// itemC is closed by readLoop after the peer ended the stream
+	itemC chan irpcgen.Deserializable
+
+	// abandoned is closed when our consumer stopped consuming items before the stream ended
+	abandoned   chan struct{}
+	abandonOnce sync.Once
+}
+
+// newItem can be nil, if it is not known yet. it needs to be set before we give peer any credit
+func newItemStream(newItem func() irpcgen.Deserializable, window int) *itemStream {
+	return &itemStream{
+		newItem:   newItem,
+		itemC:     make(chan irpcgen.Deserializable, window),
+		abandoned: make(chan struct{}),
+	}
+}
+
+func (s *itemStream) setNewItem(newItem func() irpcgen.Deserializable) {
+	s.newItemMux.Lock()
+	defer s.newItemMux.Unlock()
+	s.newItem = newItem
+}
+
+// push deserializes next item and hands it over to the consumer
+// items of abandoned stream are deserialized and dropped
+func (s *itemStream) push(dec *irpcgen.Decoder) error {
+	s.newItemMux.Lock()
+	newItem := s.newItem
+	s.newItemMux.Unlock()
+	if newItem == nil {
+		return errors.Join(errProtocolError, errors.New("peer sent stream item without credit"))
+	}
+
+	item := newItem()
+	if err := item.Deserialize(dec); err != nil {
+		return err
+	}
+
+	select {
+	case s.itemC <- item:
+	case <-s.abandoned:
+	default:
+		// peer doesn't respect the credit we gave it. we never block the read loop
+		return errors.Join(errProtocolError, errors.New("peer exceeded stream window"))
+	}
+	return nil
+}
+
+func (s *itemStream) abandon() {
+	s.abandonOnce.Do(func() { close(s.abandoned) })
+}
+
+// streamCredits counts the stream items we are allowed to send to our peer
+type streamCredits struct {
+	m       sync.Mutex
+	n       uint64
+	notifyC chan struct{}
+}
+
+func newStreamCredits() *streamCredits {
+	return &streamCredits{notifyC: make(chan struct{}, 1)}
+}
+
+func (c *streamCredits) grant(n uint64) {
+	c.m.Lock()
+	c.n += n
+	c.m.Unlock()
+
+	select {
+	case c.notifyC <- struct{}{}:
+	default:
+	}
+}
+
+// take blocks until there is credit to send one item, or until ctx ends
+func (c *streamCredits) take(ctx context.Context) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return context.Cause(ctx)
+		}
+
+		c.m.Lock()
+		if c.n > 0 {
+			c.n--
+			c.m.Unlock()
+			return nil
+		}
+		c.m.Unlock()
+
+		select {
+		case <-c.notifyC:
+		case <-ctx.Done():
+			return context.Cause(ctx)
+		}
+	}
+}
+
+var _ irpcgen.ParamStream = &paramStream{}
+
+// paramStream receives items of a streamed parameter of a function we execute for our peer
+type paramStream struct {
+	reqNum     reqNumT
+	stream     *itemStream
+	ctx        context.Context // worker's context
+	window     int
+	sendCredit func(reqNum reqNumT, credit int) error
+
+	consumed atomic.Bool
+
+	// once closed, we never send credit again. peer may already be reusing the request number
+	closed    bool
+	closedMux sync.Mutex
+}
+
+func newParamStream(ctx context.Context, reqNum reqNumT, window int, sendCredit func(reqNum reqNumT, credit int) error) *paramStream {
+	return &paramStream{
+		reqNum:     reqNum,
+		stream:     newItemStream(nil, window),
+		ctx:        ctx,
+		window:     window,
+		sendCredit: sendCredit,
+	}
+}
+
+// Items implements [irpcgen.ParamStream]
+func (ps *paramStream) Items(newItem func() irpcgen.Deserializable) iter.Seq[irpcgen.Deserializable] {
+	return func(yield func(irpcgen.Deserializable) bool) {
+		if !ps.consumed.CompareAndSwap(false, true) {
+			return
+		}
+		defer ps.stream.abandon()
+
+		// peer can't send anything until we give it credit
+		ps.stream.setNewItem(newItem)
+		if !ps.grant(ps.window) {
+			return
+		}
+
+		creditBatch := max(ps.window/2, 1)
+		consumed := 0
+		for {
+			if ps.ctx.Err() != nil {
+				return
+			}
+			select {
+			case item, ok := <-ps.stream.itemC:
+				if !ok {
+					return
+				}
+				consumed++
+				if consumed >= creditBatch {
+					if !ps.grant(consumed) {
+						return
+					}
+					consumed = 0
+				}
+				if !yield(item) {
+					return
+				}
+			case <-ps.ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+// grant returns false, if credit could not be sent
+func (ps *paramStream) grant(credit int) bool {
+	ps.closedMux.Lock()
+	defer ps.closedMux.Unlock()
+
+	if ps.closed {
+		return false
+	}
+	return ps.sendCredit(ps.reqNum, credit) == nil
+}
+
+// close is called before we respond to the request
+func (ps *paramStream) close() {
+	ps.closedMux.Lock()
+	defer ps.closedMux.Unlock()
+	ps.closed = true
+}
@@ -0,0 +1,134 @@
+package tracing
+
+import (
+	"context"
+	"encoding/binary"
+	"maps"
+	"math/rand/v2"
+	"slices"
+	"sync"
+	"time"
+)
+
+// SpanData is a finished span, as passed to [Exporter].
+type SpanData struct {
+	Name       string
+	Kind       SpanKind
+	Context    SpanContext
+	Parent     SpanContext // invalid for root spans
+	Start, End time.Time
+	Attributes map[string]string
+	Err        error
+}
+
+// Exporter receives spans finished by tracer created with [NewTracer].
+type Exporter interface {
+	Export(span SpanData)
+}
+
+// NewTracer returns a tracer, that passes finished spans to exporter.
+// Spans inherit the sampling decision of their parent. Root spans are sampled. Spans, that are not sampled, are not exported.
+func NewTracer(exporter Exporter) Tracer {
+	return &tracer{exporter: exporter}
+}
+
+type tracer struct {
+	exporter Exporter
+}
+
+// Start implements [Tracer]
+func (t *tracer) Start(ctx context.Context, name string, kind SpanKind) (context.Context, Span) {
+	parent := SpanContextFromContext(ctx)
+	sc := SpanContext{TraceID: parent.TraceID, Sampled: parent.Sampled}
+	if !parent.IsValid() {
+		binary.BigEndian.PutUint64(sc.TraceID[:8], rand.Uint64())
+		binary.BigEndian.PutUint64(sc.TraceID[8:], rand.Uint64())
+		sc.Sampled = true
+	}
+	for sc.SpanID == (SpanID{}) {
+		binary.BigEndian.PutUint64(sc.SpanID[:], rand.Uint64())
+	}
+
+	s := &span{
+		exporter: t.exporter,
+		data: SpanData{
+			Name:       name,
+			Kind:       kind,
+			Context:    sc,
+			Parent:     parent,
+			Start:      time.Now(),
+			Attributes: make(map[string]string),
+		},
+	}
+	return ContextWithSpanContext(ctx, sc), s
+}
+
+type span struct {
+	exporter Exporter
+	data     SpanData
+	ended    bool
+	m        sync.Mutex
+}
+
+// SpanContext implements [Span]
+func (s *span) SpanContext() SpanContext {
+	return s.data.Context
+}
+
+// SetAttribute implements [Span]
+func (s *span) SetAttribute(key, value string) {
+	s.m.Lock()
+	defer s.m.Unlock()
+
+	if !s.ended {
+		s.data.Attributes[key] = value
+	}
+}
+
+// End implements [Span]. Only the first call has an effect.
+func (s *span) End(err error) {
+	s.m.Lock()
+	if s.ended {
+		s.m.Unlock()
+		return
+	}
+	s.ended = true
+	s.data.End = time.Now()
+	s.data.Err = err
+	s.m.Unlock()
+
+	if s.data.Context.Sampled {
+		s.exporter.Export(s.data)
+	}
+}
+
+// InMemoryExporter keeps finished spans in memory. It is meant for tests.
+type InMemoryExporter struct {
+	spans []SpanData
+	m     sync.Mutex
+}
+
+// Export implements [Exporter]
+func (e *InMemoryExporter) Export(span SpanData) {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	span.Attributes = maps.Clone(span.Attributes)
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns spans exported so far, in the order they ended.
+func (e *InMemoryExporter) Spans() []SpanData {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	return slices.Clone(e.spans)
+}
+
+// Reset forgets exported spans.
+func (e *InMemoryExporter) Reset() {
+	e.m.Lock()
+	defer e.m.Unlock()
+
+	e.spans = nil
+}
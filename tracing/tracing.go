@@ -0,0 +1,222 @@
+// Package tracing adds distributed tracing to irpc endpoints.
+//
+// Each call made by an endpoint and each execution of its service functions produces a span named "Service/Method".
+// The caller's span context is sent to the peer in the call's metadata in W3C trace context format ("traceparent"),
+// so that the service function's context continues the caller's trace.
+//
+// The package only depends on irpc. Spans are created by a [Tracer], which is either the package's own [NewTracer],
+// or a thin adapter over another tracing library, such as OpenTelemetry.
+package tracing
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"strings"
+
+	"github.com/marben/irpc"
+	"github.com/marben/irpc/irpcgen"
+)
+
+// TraceparentKey is the metadata key carrying the caller's span context.
+const TraceparentKey = "traceparent"
+
+// TraceID identifies a trace. It is compatible with W3C trace context and OpenTelemetry.
+type TraceID [16]byte
+
+func (t TraceID) String() string {
+	return hex.EncodeToString(t[:])
+}
+
+// SpanID identifies a span within a trace. It is compatible with W3C trace context and OpenTelemetry.
+type SpanID [8]byte
+
+func (s SpanID) String() string {
+	return hex.EncodeToString(s[:])
+}
+
+// SpanContext identifies a span across process boundaries.
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+	Sampled bool
+	Remote  bool // span context was received from the peer
+}
+
+// IsValid reports, whether both ids are set.
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID != TraceID{} && sc.SpanID != SpanID{}
+}
+
+// Traceparent formats span context as W3C traceparent header value.
+func (sc SpanContext) Traceparent() string {
+	flags := "00"
+	if sc.Sampled {
+		flags = "01"
+	}
+	return "00-" + sc.TraceID.String() + "-" + sc.SpanID.String() + "-" + flags
+}
+
+// ParseTraceparent parses W3C traceparent header value. The returned span context is marked remote.
+func ParseTraceparent(s string) (SpanContext, error) {
+	parts := strings.Split(s, "-")
+	if len(parts) < 4 {
+		return SpanContext{}, fmt.Errorf("traceparent %q: expected 4 parts", s)
+	}
+	version, traceId, spanId, flags := parts[0], parts[1], parts[2], parts[3]
+	if version == "ff" || len(version) != 2 || (version == "00" && len(parts) != 4) {
+		return SpanContext{}, fmt.Errorf("traceparent %q: unsupported version", s)
+	}
+
+	sc := SpanContext{Remote: true}
+	if err := decodeHex(sc.TraceID[:], traceId); err != nil {
+		return SpanContext{}, fmt.Errorf("traceparent %q: trace id: %w", s, err)
+	}
+	if err := decodeHex(sc.SpanID[:], spanId); err != nil {
+		return SpanContext{}, fmt.Errorf("traceparent %q: span id: %w", s, err)
+	}
+	var f [1]byte
+	if err := decodeHex(f[:], flags); err != nil {
+		return SpanContext{}, fmt.Errorf("traceparent %q: flags: %w", s, err)
+	}
+	sc.Sampled = f[0]&1 == 1
+	if !sc.IsValid() {
+		return SpanContext{}, fmt.Errorf("traceparent %q: zero id", s)
+	}
+	return sc, nil
+}
+
+// decodeHex decodes exactly len(dst) bytes
+func decodeHex(dst []byte, s string) error {
+	if len(s) != 2*len(dst) {
+		return fmt.Errorf("expected %d hex digits, got %d", 2*len(dst), len(s))
+	}
+	_, err := hex.Decode(dst, []byte(s))
+	return err
+}
+
+// SpanKind tells, which side of the call the span describes.
+type SpanKind int
+
+const (
+	SpanKindClient SpanKind = iota // our call to the peer
+	SpanKindServer                 // execution of our service function for the peer
+)
+
+func (k SpanKind) String() string {
+	switch k {
+	case SpanKindClient:
+		return "client"
+	case SpanKindServer:
+		return "server"
+	default:
+		return fmt.Sprintf("SpanKind(%d)", int(k))
+	}
+}
+
+// Span is a single traced operation.
+type Span interface {
+	SpanContext() SpanContext
+	SetAttribute(key, value string)
+	// End finishes the span. err is the call's failure, or the error returned by the called function, or nil
+	End(err error)
+}
+
+// Tracer creates spans.
+type Tracer interface {
+	// Start starts a span, that is a child of the span context in ctx (see [SpanContextFromContext]), if there is any.
+	// It returns ctx carrying the new span's context.
+	Start(ctx context.Context, name string, kind SpanKind) (context.Context, Span)
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext returns a context carrying sc. Spans started with it become children of sc.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context carried by ctx. It is invalid, if there is none.
+// Within a service function, it is the context of the function's server span.
+func SpanContextFromContext(ctx context.Context) SpanContext {
+	sc, _ := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc
+}
+
+// Attribute keys set on every span. They follow OpenTelemetry's semantic conventions for RPC.
+const (
+	AttrRPCSystem  = "rpc.system"
+	AttrRPCService = "rpc.service"
+	AttrRPCMethod  = "rpc.method"
+)
+
+// spanNames returns span name and the service and method names of the call
+func spanNames(call irpc.CallInfo) (name, service, method string) {
+	service = call.ServiceId.String()
+	method = fmt.Sprintf("%d", call.FuncId)
+	if call.Service != nil {
+		service = call.Service.Name
+		if m, ok := call.Service.Method(call.FuncId); ok {
+			method = m.Name
+		}
+	}
+	return service + "/" + method, service, method
+}
+
+func startSpan(ctx context.Context, tracer Tracer, call irpc.CallInfo, kind SpanKind) (context.Context, Span) {
+	name, service, method := spanNames(call)
+	ctx, span := tracer.Start(ctx, name, kind)
+	span.SetAttribute(AttrRPCSystem, "irpc")
+	span.SetAttribute(AttrRPCService, service)
+	span.SetAttribute(AttrRPCMethod, method)
+	return ctx, span
+}
+
+// ClientInterceptor traces our calls to the peer and sends the span context along with them.
+// Span of a streaming call ends together with the stream.
+func ClientInterceptor(tracer Tracer) irpc.ClientInterceptor {
+	return func(ctx context.Context, call irpc.CallInfo, req irpcgen.Serializable, resp irpcgen.Deserializable, invoke irpc.ClientInvoker) error {
+		ctx, span := startSpan(ctx, tracer, call, SpanKindClient)
+		if sc := span.SpanContext(); sc.IsValid() {
+			ctx = irpc.ContextWithMetadata(ctx, irpc.Metadata{TraceparentKey: sc.Traceparent()})
+		}
+		err := invoke(ctx, call, req, resp)
+		span.End(resultError(err, resp))
+		return err
+	}
+}
+
+// ServerInterceptor traces executions of our service functions.
+// The span continues the caller's trace, if the caller sent its span context.
+func ServerInterceptor(tracer Tracer) irpc.ServerInterceptor {
+	return func(ctx context.Context, call irpc.CallInfo, handler irpc.ServerHandler) (irpcgen.Serializable, error) {
+		if tp, found := irpc.MetadataFromContext(ctx)[TraceparentKey]; found {
+			if sc, err := ParseTraceparent(tp); err == nil {
+				ctx = ContextWithSpanContext(ctx, sc)
+			}
+		}
+		ctx, span := startSpan(ctx, tracer, call, SpanKindServer)
+		resp, err := handler(ctx)
+		span.End(resultError(err, resp))
+		return resp, err
+	}
+}
+
+// resultError returns err, or the error returned by the called function within resp (see [irpcgen.ErrorResult])
+func resultError(err error, resp any) error {
+	if err != nil {
+		return err
+	}
+	if r, ok := resp.(irpcgen.ErrorResult); ok {
+		return r.ResultError()
+	}
+	return nil
+}
+
+// WithTracing traces both the endpoint's calls and executions of its services.
+func WithTracing(tracer Tracer) irpc.EndpointOption {
+	return func(ep *irpc.Endpoint) {
+		irpc.WithClientInterceptors(ClientInterceptor(tracer))(ep)
+		irpc.WithServerInterceptors(ServerInterceptor(tracer))(ep)
+	}
+}
@@ -0,0 +1,163 @@
+package tracing_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/marben/irpc"
+	"github.com/marben/irpc/cmd/irpc/test/testtools"
+	"github.com/marben/irpc/tracing"
+)
+
+func TestTracePropagation(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracer := tracing.NewTracer(exporter)
+
+	var handlerSc tracing.SpanContext
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivCtxErrFunc = func(ctx context.Context, a, b int) (int, error) {
+		handlerSc = tracing.SpanContextFromContext(ctx)
+		return a / b, nil
+	}
+
+	c1, c2 := net.Pipe()
+	serviceEp := irpc.NewEndpoint(c2, tracing.WithTracing(tracer), irpc.WithEndpointServices(testtools.NewTestServiceIrpcService(impl)))
+	defer serviceEp.Close()
+	clientEp := irpc.NewEndpoint(c1, tracing.WithTracing(tracer))
+	defer clientEp.Close()
+
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+
+	ctx, parent := tracer.Start(context.Background(), "parent", tracing.SpanKindClient)
+	if res, err := client.DivCtxErr(ctx, 6, 3); err != nil || res != 2 {
+		t.Fatalf("DivCtxErr(): %d, %v", res, err)
+	}
+	parent.End(nil)
+
+	spans := exporter.Spans()
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(spans))
+	}
+	var clientSpan, serverSpan tracing.SpanData
+	for _, s := range spans {
+		switch {
+		case s.Name == "parent":
+		case s.Kind == tracing.SpanKindClient:
+			clientSpan = s
+		case s.Kind == tracing.SpanKindServer:
+			serverSpan = s
+		}
+	}
+
+	for _, s := range []tracing.SpanData{clientSpan, serverSpan} {
+		if s.Name != "TestService/DivCtxErr" {
+			t.Fatalf("%s span name: %q", s.Kind, s.Name)
+		}
+		if s.Attributes[tracing.AttrRPCMethod] != "DivCtxErr" || s.Attributes[tracing.AttrRPCSystem] != "irpc" {
+			t.Fatalf("%s span attributes: %v", s.Kind, s.Attributes)
+		}
+		if s.Context.TraceID != parent.SpanContext().TraceID {
+			t.Fatalf("%s span is not part of parent's trace", s.Kind)
+		}
+	}
+	if clientSpan.Parent != parent.SpanContext() {
+		t.Fatalf("client span's parent: %+v", clientSpan.Parent)
+	}
+	if !serverSpan.Parent.Remote || serverSpan.Parent.SpanID != clientSpan.Context.SpanID {
+		t.Fatalf("server span's parent: %+v, client span: %+v", serverSpan.Parent, clientSpan.Context)
+	}
+	if handlerSc != serverSpan.Context {
+		t.Fatalf("handler's span context: %+v, server span: %+v", handlerSc, serverSpan.Context)
+	}
+}
+
+func TestClientSpanRecordsError(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	c1, c2 := net.Pipe()
+	serviceEp := irpc.NewEndpoint(c2, irpc.WithEndpointServices(testtools.NewTestServiceIrpcService(testtools.NewTestServiceImpl(0))))
+	clientEp := irpc.NewEndpoint(c1, tracing.WithTracing(tracing.NewTracer(exporter)))
+	defer clientEp.Close()
+
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	serviceEp.Close()
+
+	if _, err := client.DivErr(1, 1); err == nil {
+		t.Fatalf("DivErr() on closed connection succeeded")
+	}
+	spans := exporter.Spans()
+	if len(spans) != 1 || spans[0].Err == nil {
+		t.Fatalf("spans: %+v", spans)
+	}
+	if spans[0].Parent.IsValid() {
+		t.Fatalf("root span has parent: %+v", spans[0].Parent)
+	}
+}
+
+func TestParseTraceparent(t *testing.T) {
+	const tp = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	sc, err := tracing.ParseTraceparent(tp)
+	if err != nil {
+		t.Fatalf("ParseTraceparent(): %v", err)
+	}
+	if !sc.Sampled || !sc.Remote || sc.TraceID.String() != "4bf92f3577b34da6a3ce929d0e0e4736" || sc.SpanID.String() != "00f067aa0ba902b7" {
+		t.Fatalf("parsed: %+v", sc)
+	}
+	if got := sc.Traceparent(); got != tp {
+		t.Fatalf("Traceparent(): %q", got)
+	}
+
+	for _, invalid := range []string{
+		"",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7",
+		"ff-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"00-00000000000000000000000000000000-00f067aa0ba902b7-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902-01",
+		"00-4bf92f3577b34da6a3ce929d0e0e47zz-00f067aa0ba902b7-01",
+	} {
+		if _, err := tracing.ParseTraceparent(invalid); err == nil {
+			t.Fatalf("ParseTraceparent(%q) succeeded", invalid)
+		}
+	}
+}
+
+func TestSpansRecordFunctionError(t *testing.T) {
+	exporter := &tracing.InMemoryExporter{}
+	tracer := tracing.NewTracer(exporter)
+	errDiv := errors.New("division by zero")
+	impl := testtools.NewTestServiceImpl(0)
+	impl.DivErrFunc = func(a, b int) (int, error) {
+		return 0, errDiv
+	}
+
+	c1, c2 := net.Pipe()
+	serviceEp := irpc.NewEndpoint(c2, tracing.WithTracing(tracer), irpc.WithEndpointServices(testtools.NewTestServiceIrpcService(impl)))
+	defer serviceEp.Close()
+	clientEp := irpc.NewEndpoint(c1, tracing.WithTracing(tracer))
+	defer clientEp.Close()
+
+	client, err := testtools.NewTestServiceIrpcClient(clientEp)
+	if err != nil {
+		t.Fatalf("NewTestServiceIrpcClient(): %v", err)
+	}
+	if _, err := client.DivErr(1, 0); err == nil || err.Error() != errDiv.Error() {
+		t.Fatalf("DivErr(): %v", err)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("exported %d spans, want 2", len(spans))
+	}
+	for _, s := range spans {
+		if s.Err == nil || s.Err.Error() != errDiv.Error() {
+			t.Fatalf("%s span error: %v", s.Kind, s.Err)
+		}
+	}
+}